@@ -0,0 +1,51 @@
+package netatmo
+
+import "context"
+
+// Severity defines importance of an alert.
+type Severity string
+
+// Supported severities.
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Alert defines a condition worth notifying a person about.
+type Alert struct {
+	Time     int64 // Unix time the condition was detected
+	Severity Severity
+	Kind     string // Machine readable kind (ex. "bias", "frost")
+	DeviceID string
+	ModuleID string
+	Metric   string  // Measurement name (ex. Temperature), empty if not specific to a measurement
+	Value    float64 // Value that triggered the alert, meaning depends on the kind
+	Message  string  // Human readable description
+}
+
+// Notifier delivers alerts.
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// NotifierFunc adapts an ordinary function to the Notifier interface.
+type NotifierFunc func(ctx context.Context, alert Alert) error
+
+// Notify calls f(ctx, alert).
+func (f NotifierFunc) Notify(ctx context.Context, alert Alert) error {
+	return f(ctx, alert)
+}
+
+// notifyAll delivers the alerts with the notifier, if any, stopping at the first error.
+func notifyAll(ctx context.Context, notifier Notifier, alerts []Alert) error {
+	if notifier == nil {
+		return nil
+	}
+	for _, a := range alerts {
+		if err := notifier.Notify(ctx, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package netatmo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error codes of the Netatmo API.
+// Reference: https://dev.netatmo.com/apidocumentation/general#status-ok
+const (
+	APIErrorAccessTokenMissing = 1
+	APIErrorInvalidAccessToken = 2
+	APIErrorAccessTokenExpired = 3
+	APIErrorDeviceNotFound     = 9
+	APIErrorMissingArguments   = 10
+	APIErrorOperationForbidden = 13
+	APIErrorInvalidArgument    = 21
+	APIErrorUserUsageReached   = 26
+)
+
+// APIError defines error response of an API method, wrapped by RequestError.
+type APIError struct {
+	StatusCode int
+	Code       int    // Netatmo error code (ex. APIErrorInvalidAccessToken), zero if the body has none
+	Message    string // Message of the response, or the HTTP status text
+}
+
+// Error returns the message with the error code.
+func (e *APIError) Error() string {
+	if e.Code == 0 {
+		return fmt.Sprintf("api error (status %d): %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("api error %d (status %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// apiError returns APIError if the response has an error status or an error body, nil otherwise.
+func apiError(status int, data []byte) error {
+	var body struct {
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	_ = json.Unmarshal(data, &body)
+	if status < 400 && body.Error == nil {
+		return nil
+	}
+	e := &APIError{StatusCode: status, Message: http.StatusText(status)}
+	if body.Error != nil {
+		e.Code = body.Error.Code
+		if body.Error.Message != "" {
+			e.Message = body.Error.Message
+		}
+	}
+	return e
+}
+
+// transportError returns the error unless it is an APIError, which is a response of the API.
+func transportError(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,285 @@
+package netatmo
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// ArchiveFormat is the format identifier written into archive manifests.
+const ArchiveFormat = "netatmo-weather-archive"
+
+// ArchiveVersion is the newest archive version this package can read and write.
+const ArchiveVersion = 1
+
+// importBatchSize defines number of measures written into the store at once on import.
+const importBatchSize = 1000
+
+// ArchiveManifest describes contents of an archive. It is always the first record of the archive.
+type ArchiveManifest struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	CreatedAt  int64  `json:"created_at"`
+	Devices    int    `json:"devices"`    // Number of device records
+	Measures   int    `json:"measures"`   // Number of measure records
+	Aggregates int    `json:"aggregates"` // Number of aggregate records
+	Begin      int64  `json:"begin"`      // Oldest measure timestamp, 0 if no measures
+	End        int64  `json:"end"`        // Newest measure timestamp, 0 if no measures
+
+	// Cutoffs holds the newest data of each module written so far, including previous archives of an incremental
+	// chain, so the next incremental archive continues from them.
+	Cutoffs []ArchiveCutoff `json:"cutoffs,omitempty"`
+}
+
+// ArchiveCutoff defines the newest data of a module written into an archive chain.
+type ArchiveCutoff struct {
+	Account  string `json:"account,omitempty"`
+	DeviceID string `json:"device_id"`
+	ModuleID string `json:"module_id"`
+	Measure  int64  `json:"measure,omitempty"` // Newest measure timestamp, 0 if no measures
+	Hourly   int64  `json:"hourly,omitempty"`  // Newest hourly aggregate start, 0 if no hourly aggregates
+	Daily    int64  `json:"daily,omitempty"`   // Newest daily aggregate start, 0 if no daily aggregates
+}
+
+// includesAggregate reports whether the aggregate may have changed since the cutoff: the newest written bucket of
+// its resolution or a later one, or a bucket ending after the newest written measure (compacted later).
+func (c *ArchiveCutoff) includesAggregate(a *Aggregate) bool {
+	last := c.Hourly
+	if a.Resolution == ResolutionDaily {
+		last = c.Daily
+	}
+	size := int64(a.Resolution.Duration() / time.Second)
+	return a.Timestamp >= last || (c.Measure != 0 && a.Timestamp+size > c.Measure)
+}
+
+// archiveRecord defines single line of the archive. Exactly one field is set.
+type archiveRecord struct {
+	Manifest  *ArchiveManifest `json:"manifest,omitempty"`
+	Device    *Device          `json:"device,omitempty"`
+	Measure   *Measure         `json:"measure,omitempty"`
+	Aggregate *Aggregate       `json:"aggregate,omitempty"`
+}
+
+// Export writes station metadata, measures and aggregates matching the filter from the store into w.
+// The archive is gzipped NDJSON: a manifest line followed by one line per device, measure and aggregate.
+func Export(ctx context.Context, w io.Writer, store Store, filter MeasureFilter) (*ArchiveManifest, error) {
+	return export(ctx, w, store, filter, nil)
+}
+
+// ExportIncremental writes station metadata and the data written after the previous archive of the chain into w,
+// continuing from the cutoff of each module: measures newer than its newest written measure, and aggregates which
+// may have been compacted or re-compacted since. Modules without cutoffs (ex. new modules, or a previous archive
+// without cutoffs) are written in full. Cutoffs of modules without new data are carried forward, so an empty
+// archive continues the chain.
+func ExportIncremental(ctx context.Context, w io.Writer, store Store,
+	previous *ArchiveManifest) (*ArchiveManifest, error) {
+	return export(ctx, w, store, MeasureFilter{}, previous)
+}
+
+func export(ctx context.Context, w io.Writer, store Store, filter MeasureFilter,
+	previous *ArchiveManifest) (*ArchiveManifest, error) {
+	devices, err := store.Devices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	measures, err := store.Measures(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var aggregates []Aggregate
+	for _, res := range []Resolution{ResolutionHourly, ResolutionDaily} {
+		a, err := store.Aggregates(ctx, res, filter)
+		if err != nil {
+			return nil, err
+		}
+		aggregates = append(aggregates, a...)
+	}
+	cutoffs := make(map[seriesKey]ArchiveCutoff)
+	if previous != nil {
+		for _, c := range previous.Cutoffs {
+			cutoffs[seriesKey{Account: c.Account, DeviceID: c.DeviceID, ModuleID: c.ModuleID}] = c
+		}
+		newMeasures := measures[:0]
+		for i := range measures {
+			m := &measures[i]
+			c, ok := cutoffs[seriesKey{Account: m.Account, DeviceID: m.DeviceID, ModuleID: m.ModuleID}]
+			if !ok || m.Timestamp > c.Measure {
+				newMeasures = append(newMeasures, *m)
+			}
+		}
+		measures = newMeasures
+		newAggregates := aggregates[:0]
+		for i := range aggregates {
+			a := &aggregates[i]
+			c, ok := cutoffs[seriesKey{Account: a.Account, DeviceID: a.DeviceID, ModuleID: a.ModuleID}]
+			if !ok || c.includesAggregate(a) {
+				newAggregates = append(newAggregates, *a)
+			}
+		}
+		aggregates = newAggregates
+	}
+	manifest := &ArchiveManifest{
+		Format:     ArchiveFormat,
+		Version:    ArchiveVersion,
+		CreatedAt:  ClockFromContext(ctx).Now().Unix(),
+		Devices:    len(devices),
+		Measures:   len(measures),
+		Aggregates: len(aggregates),
+	}
+	for _, m := range measures {
+		if manifest.Begin == 0 || m.Timestamp < manifest.Begin {
+			manifest.Begin = m.Timestamp
+		}
+		if m.Timestamp > manifest.End {
+			manifest.End = m.Timestamp
+		}
+		k := seriesKey{Account: m.Account, DeviceID: m.DeviceID, ModuleID: m.ModuleID}
+		c := cutoffs[k]
+		c.Measure = max(c.Measure, m.Timestamp)
+		cutoffs[k] = c
+	}
+	for _, a := range aggregates {
+		k := seriesKey{Account: a.Account, DeviceID: a.DeviceID, ModuleID: a.ModuleID}
+		c := cutoffs[k]
+		if a.Resolution == ResolutionDaily {
+			c.Daily = max(c.Daily, a.Timestamp)
+		} else {
+			c.Hourly = max(c.Hourly, a.Timestamp)
+		}
+		cutoffs[k] = c
+	}
+	manifest.Cutoffs = sortedCutoffs(cutoffs)
+	gw := gzip.NewWriter(w)
+	enc := json.NewEncoder(gw)
+	if err := enc.Encode(archiveRecord{Manifest: manifest}); err != nil {
+		return nil, err
+	}
+	for i := range devices {
+		if err := enc.Encode(archiveRecord{Device: &devices[i]}); err != nil {
+			return nil, err
+		}
+	}
+	for i := range measures {
+		if i%importBatchSize == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err := enc.Encode(archiveRecord{Measure: &measures[i]}); err != nil {
+			return nil, err
+		}
+	}
+	for i := range aggregates {
+		if err := enc.Encode(archiveRecord{Aggregate: &aggregates[i]}); err != nil {
+			return nil, err
+		}
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// sortedCutoffs returns the cutoffs ordered by account, device and module.
+func sortedCutoffs(cutoffs map[seriesKey]ArchiveCutoff) []ArchiveCutoff {
+	sorted := make([]ArchiveCutoff, 0, len(cutoffs))
+	for k, c := range cutoffs {
+		c.Account, c.DeviceID, c.ModuleID = k.Account, k.DeviceID, k.ModuleID
+		sorted = append(sorted, c)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		x, y := &sorted[i], &sorted[j]
+		if x.Account != y.Account {
+			return x.Account < y.Account
+		}
+		if x.DeviceID != y.DeviceID {
+			return x.DeviceID < y.DeviceID
+		}
+		return x.ModuleID < y.ModuleID
+	})
+	return sorted
+}
+
+// ReadArchiveManifest reads only the manifest of an archive from r.
+func ReadArchiveManifest(r io.Reader) (*ArchiveManifest, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	return readManifest(json.NewDecoder(gr))
+}
+
+// Import reads an archive written by Export from r and stores its contents into the store.
+func Import(ctx context.Context, r io.Reader, store Store) (*ArchiveManifest, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	dec := json.NewDecoder(bufio.NewReader(gr))
+	manifest, err := readManifest(dec)
+	if err != nil {
+		return nil, err
+	}
+	var devices []Device
+	var aggregates []Aggregate
+	var batch []Measure
+	for {
+		var record archiveRecord
+		if err := dec.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		switch {
+		case record.Device != nil:
+			devices = append(devices, *record.Device)
+		case record.Measure != nil:
+			batch = append(batch, *record.Measure)
+			if len(batch) >= importBatchSize {
+				if err := store.Write(ctx, batch); err != nil {
+					return nil, err
+				}
+				batch = batch[:0]
+			}
+		case record.Aggregate != nil:
+			aggregates = append(aggregates, *record.Aggregate)
+		}
+	}
+	if len(batch) > 0 {
+		if err := store.Write(ctx, batch); err != nil {
+			return nil, err
+		}
+	}
+	if len(aggregates) > 0 {
+		if err := store.WriteAggregates(ctx, aggregates); err != nil {
+			return nil, err
+		}
+	}
+	if len(devices) > 0 {
+		if err := store.PutDevices(ctx, devices); err != nil {
+			return nil, err
+		}
+	}
+	return manifest, nil
+}
+
+func readManifest(dec *json.Decoder) (*ArchiveManifest, error) {
+	var first archiveRecord
+	if err := dec.Decode(&first); err != nil {
+		return nil, fmt.Errorf("failed to read archive manifest: %w", err)
+	}
+	manifest := first.Manifest
+	if manifest == nil || manifest.Format != ArchiveFormat {
+		return nil, errors.New("not a netatmo weather archive")
+	}
+	if manifest.Version > ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version: %d", manifest.Version)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,123 @@
+package netatmo
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+// outlierThreshold defines how many scaled median absolute deviations from the median a value may lie before it is
+// rejected as an outlier.
+const outlierThreshold = 3.0
+
+// AreaValue defines robust aggregate of a single measurement across public stations.
+type AreaValue struct {
+	Median   float64 // Median of accepted values
+	Min      float64 // Minimum of accepted values
+	Max      float64 // Maximum of accepted values
+	Count    int     // Number of accepted values
+	Rejected int     // Number of values rejected as outliers
+}
+
+// AreaReading defines "neighborhood" reading aggregated from public stations. Measurements no station reported are
+// nil.
+type AreaReading struct {
+	Stations    int
+	Temperature *AreaValue
+	Humidity    *AreaValue
+	Pressure    *AreaValue
+	Rain60Min   *AreaValue
+	Rain24H     *AreaValue
+}
+
+// GetAreaReading gathers public data within the box and aggregates it with AggregateArea.
+func (c *Client) GetAreaReading(box BoundingBox) (*AreaReading, error) {
+	return c.GetAreaReadingContext(context.Background(), box)
+}
+
+// GetAreaReadingContext is GetAreaReading with a context canceling the request.
+func (c *Client) GetAreaReadingContext(ctx context.Context, box BoundingBox) (*AreaReading, error) {
+	stations, err := c.GetPublicDataContext(ctx, box)
+	if err != nil {
+		return nil, err
+	}
+	return AggregateArea(stations), nil
+}
+
+// AggregateArea aggregates readings of public stations into medians, rejecting values further than 3 scaled median
+// absolute deviations from the median (ex. sensors in direct sun or broken stations).
+func AggregateArea(stations []PublicStation) *AreaReading {
+	var temperature, humidity, pressure, rain60, rain24 []float64
+	for _, s := range stations {
+		if s.Temperature != nil {
+			temperature = append(temperature, *s.Temperature)
+		}
+		if s.Humidity != nil {
+			humidity = append(humidity, float64(*s.Humidity))
+		}
+		if s.Pressure != nil {
+			pressure = append(pressure, *s.Pressure)
+		}
+		if s.Rain60Min != nil {
+			rain60 = append(rain60, *s.Rain60Min)
+		}
+		if s.Rain24H != nil {
+			rain24 = append(rain24, *s.Rain24H)
+		}
+	}
+	return &AreaReading{
+		Stations:    len(stations),
+		Temperature: robustAggregate(temperature),
+		Humidity:    robustAggregate(humidity),
+		Pressure:    robustAggregate(pressure),
+		Rain60Min:   robustAggregate(rain60),
+		Rain24H:     robustAggregate(rain24),
+	}
+}
+
+func robustAggregate(values []float64) *AreaValue {
+	if len(values) == 0 {
+		return nil
+	}
+	accepted, rejected := rejectOutliers(values)
+	v := &AreaValue{Median: median(accepted), Min: accepted[0], Max: accepted[0], Count: len(accepted), Rejected: rejected}
+	for _, x := range accepted {
+		v.Min = math.Min(v.Min, x)
+		v.Max = math.Max(v.Max, x)
+	}
+	return v
+}
+
+// rejectOutliers returns values within the threshold of scaled median absolute deviation and the rejected count.
+func rejectOutliers(values []float64) ([]float64, int) {
+	m := median(values)
+	deviations := make([]float64, len(values))
+	for i, x := range values {
+		deviations[i] = math.Abs(x - m)
+	}
+	mad := 1.4826 * median(deviations) // Scaled to be consistent with standard deviation of normal distribution
+	if mad == 0 {
+		return values, 0
+	}
+	var accepted []float64
+	for _, x := range values {
+		if math.Abs(x-m) <= outlierThreshold*mad {
+			accepted = append(accepted, x)
+		}
+	}
+	return accepted, len(values) - len(accepted)
+}
+
+// median returns median of the values without modifying them. It returns NaN for empty values.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
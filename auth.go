@@ -0,0 +1,153 @@
+package netatmo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultRedirectURL defines callback of AuthCodeFlow without RedirectURL. It must be registered as redirect URI of
+// the application on the developer portal.
+const DefaultRedirectURL = "http://localhost:8089/callback"
+
+// AuthCodeFlow implements the OAuth2 authorization code flow: the user grants access on the consent page of Netatmo,
+// which redirects the browser to a temporary listener on RedirectURL receiving the code.
+// Reference: https://dev.netatmo.com/apidocumentation/oauth#authorization-code
+type AuthCodeFlow struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string   // Local callback (http://localhost:<port>/<path>), defaults to DefaultRedirectURL
+	Scopes       []string // Defaults to read_station
+	Options      []Option // Options of the created client, also used for the endpoint and transport of token requests
+}
+
+func (f *AuthCodeFlow) config(options *clientOptions) *oauth2.Config {
+	config := options.oauthConfig(f.ClientID, f.ClientSecret)
+	config.RedirectURL = f.RedirectURL
+	if config.RedirectURL == "" {
+		config.RedirectURL = DefaultRedirectURL
+	}
+	if len(f.Scopes) > 0 {
+		config.Scopes = f.Scopes
+	}
+	return config
+}
+
+// AuthCodeURL returns URL of the consent page. The state is passed back to the callback to prevent cross-site
+// request forgery.
+func (f *AuthCodeFlow) AuthCodeURL(state string) string {
+	return f.config(newClientOptions(f.Options)).AuthCodeURL(state)
+}
+
+// Exchange exchanges the authorization code received by the callback for a token.
+func (f *AuthCodeFlow) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	options := newClientOptions(f.Options)
+	token, err := f.config(options).Exchange(options.context(ctx), code)
+	if err != nil {
+		return nil, oauthError(err)
+	}
+	return token, nil
+}
+
+// Token listens on RedirectURL, passes the consent URL to prompt (ex. to print it or open a browser) and waits for
+// the callback, then exchanges the code. It returns when the user granted or denied access, or the context is done.
+func (f *AuthCodeFlow) Token(ctx context.Context, prompt func(authURL string)) (*oauth2.Token, error) {
+	options := newClientOptions(f.Options)
+	config := f.config(options)
+	redirect, err := url.Parse(config.RedirectURL)
+	if err != nil {
+		return nil, err
+	}
+	if redirect.Scheme != "http" || redirect.Port() == "" {
+		return nil, fmt.Errorf("redirect URL must be http://<host>:<port>/<path>: %s", config.RedirectURL)
+	}
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomState()
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	path := redirect.Path
+	if path == "" {
+		path = "/"
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var res result
+		switch {
+		case q.Get("state") != state:
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return // Ignore forged or stale requests, keep waiting
+		case q.Get("error") != "":
+			res.err = fmt.Errorf("authorization denied: %s", q.Get("error"))
+		case q.Get("code") == "":
+			res.err = errors.New("authorization callback without code")
+		default:
+			res.code = q.Get("code")
+		}
+		message := "Access granted, you can close this window."
+		if res.err != nil {
+			message = res.err.Error()
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, "<!DOCTYPE html><title>Netatmo</title><p>%s</p>", html.EscapeString(message))
+		select {
+		case results <- res:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+	prompt(config.AuthCodeURL(state))
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-results:
+		if res.err != nil {
+			return nil, res.err
+		}
+		token, err := config.Exchange(options.context(ctx), res.code)
+		if err != nil {
+			return nil, oauthError(err)
+		}
+		return token, nil
+	}
+}
+
+// Authorize runs the flow (see Token) and creates client from the obtained token. The context only bounds the flow,
+// the client keeps refreshing the token after it ends.
+func (f *AuthCodeFlow) Authorize(ctx context.Context, prompt func(authURL string)) (*Client, error) {
+	token, err := f.Token(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	options := newClientOptions(f.Options)
+	// Refreshes outlive the flow, so the client must not keep its context (ex. with a timeout for the user consent)
+	return newClient(options.context(context.Background()), f.config(options), token, nil, options), nil
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,406 @@
+package netatmo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// AvroNamespace defines the namespace of the Avro schemas.
+const AvroNamespace = "com.github.mikan.netatmo"
+
+// maxAvroLength defines the longest string accepted by decoders, protecting from corrupted lengths.
+const maxAvroLength = 1 << 20
+
+// avroField defines a nullable measurement of the Measure record, either a double or an int.
+type avroField struct {
+	name    string
+	double  func(m *Measure) **float64
+	integer func(m *Measure) **int
+}
+
+// avroFields defines nullable fields in record order. Append new fields at the end with null defaults to keep
+// schema evolution backward and forward compatible.
+var avroFields = []avroField{
+	{name: "temperature", double: func(m *Measure) **float64 { return &m.Temperature }},
+	{name: "co2", integer: func(m *Measure) **int { return &m.CO2 }},
+	{name: "humidity", integer: func(m *Measure) **int { return &m.Humidity }},
+	{name: "pressure", double: func(m *Measure) **float64 { return &m.Pressure }},
+	{name: "noise", integer: func(m *Measure) **int { return &m.Noise }},
+	{name: "wind_strength", integer: func(m *Measure) **int { return &m.WindStrength }},
+	{name: "wind_angle", integer: func(m *Measure) **int { return &m.WindAngle }},
+	{name: "gust_strength", integer: func(m *Measure) **int { return &m.GustStrength }},
+	{name: "gust_angle", integer: func(m *Measure) **int { return &m.GustAngle }},
+	{name: "min_temp", double: func(m *Measure) **float64 { return &m.MinTemperature }},
+	{name: "max_temp", double: func(m *Measure) **float64 { return &m.MaxTemperature }},
+	{name: "min_hum", integer: func(m *Measure) **int { return &m.MinHumidity }},
+	{name: "max_hum", integer: func(m *Measure) **int { return &m.MaxHumidity }},
+	{name: "min_pressure", double: func(m *Measure) **float64 { return &m.MinPressure }},
+	{name: "max_pressure", double: func(m *Measure) **float64 { return &m.MaxPressure }},
+	{name: "min_noise", integer: func(m *Measure) **int { return &m.MinNoise }},
+	{name: "max_noise", integer: func(m *Measure) **int { return &m.MaxNoise }},
+	{name: "sum_rain", double: func(m *Measure) **float64 { return &m.SumRain }},
+	{name: "rain", double: func(m *Measure) **float64 { return &m.Rain }},
+	{name: "dew_point", double: func(m *Measure) **float64 { return &m.DewPoint }},
+	{name: "humidex", double: func(m *Measure) **float64 { return &m.Humidex }},
+}
+
+// AvroMeasureSchema defines the Avro schema of measure records encoded by MarshalMeasureAvro. Times are
+// timestamp-millis and null measurements are ["null", type] unions.
+var AvroMeasureSchema = avroMeasureSchema()
+
+func avroMeasureSchema() string {
+	timestamp := map[string]string{"type": "long", "logicalType": "timestamp-millis"}
+	fields := []map[string]interface{}{
+		{"name": "account", "type": "string", "default": ""},
+		{"name": "device_id", "type": "string"},
+		{"name": "module_id", "type": "string"},
+		{"name": "timestamp", "type": timestamp},
+	}
+	for _, f := range avroFields {
+		typ := "int"
+		if f.double != nil {
+			typ = "double"
+		}
+		fields = append(fields, map[string]interface{}{"name": f.name, "type": []string{"null", typ}, "default": nil})
+	}
+	fields = append(fields,
+		map[string]interface{}{"name": "date_max_gust", "type": []interface{}{"null", timestamp}, "default": nil},
+		map[string]interface{}{"name": "co2_calibrating", "type": "boolean", "default": false},
+		map[string]interface{}{"name": "flagged", "type": map[string]string{"type": "map", "values": "string"},
+			"default": map[string]string{}},
+	)
+	data, _ := json.Marshal(map[string]interface{}{
+		"type":      "record",
+		"name":      "Measure",
+		"namespace": AvroNamespace,
+		"fields":    fields,
+	})
+	return string(data)
+}
+
+// MarshalMeasureAvro encodes the measure with Avro binary encoding of AvroMeasureSchema.
+func MarshalMeasureAvro(m *Measure) []byte {
+	var b []byte
+	b = appendAvroString(b, m.Account)
+	b = appendAvroString(b, m.DeviceID)
+	b = appendAvroString(b, m.ModuleID)
+	b = appendAvroLong(b, m.Timestamp*1000)
+	for _, f := range avroFields {
+		switch {
+		case f.double != nil && *f.double(m) != nil:
+			b = appendAvroLong(b, 1)
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(**f.double(m)))
+		case f.integer != nil && *f.integer(m) != nil:
+			b = appendAvroLong(b, 1)
+			b = appendAvroLong(b, int64(**f.integer(m)))
+		default:
+			b = appendAvroLong(b, 0)
+		}
+	}
+	if m.MaxGustTime != nil {
+		b = appendAvroLong(b, 1)
+		b = appendAvroLong(b, *m.MaxGustTime*1000)
+	} else {
+		b = appendAvroLong(b, 0)
+	}
+	if m.CO2Calibrating {
+		b = append(b, 1)
+	} else {
+		b = append(b, 0)
+	}
+	if len(m.Flagged) > 0 {
+		keys := make([]string, 0, len(m.Flagged))
+		for k := range m.Flagged {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = appendAvroLong(b, int64(len(keys)))
+		for _, k := range keys {
+			b = appendAvroString(b, k)
+			b = appendAvroString(b, m.Flagged[k])
+		}
+	}
+	return appendAvroLong(b, 0)
+}
+
+// UnmarshalMeasureAvro decodes a measure encoded with AvroMeasureSchema.
+func UnmarshalMeasureAvro(b []byte) (Measure, error) {
+	r := bytes.NewReader(b)
+	m, err := readMeasureAvro(r)
+	if err == nil && r.Len() > 0 {
+		err = fmt.Errorf("avro: %d trailing bytes", r.Len())
+	}
+	return m, err
+}
+
+func readMeasureAvro(r avroReader) (Measure, error) {
+	var m Measure
+	d := avroDecoder{r: r}
+	m.Account = d.string()
+	m.DeviceID = d.string()
+	m.ModuleID = d.string()
+	m.Timestamp = d.long() / 1000
+	for _, f := range avroFields {
+		if !d.present() {
+			continue
+		}
+		if f.double != nil {
+			v := d.double()
+			*f.double(&m) = &v
+		} else {
+			v := int(d.long())
+			*f.integer(&m) = &v
+		}
+	}
+	if d.present() {
+		v := d.long() / 1000
+		m.MaxGustTime = &v
+	}
+	m.CO2Calibrating = d.byte() != 0
+	for {
+		n := d.long()
+		if n == 0 || d.err != nil {
+			break
+		}
+		if n < 0 {
+			n = -n
+			d.long() // Block size in bytes
+		}
+		if m.Flagged == nil {
+			m.Flagged = make(map[string]string)
+		}
+		for i := int64(0); i < n && d.err == nil; i++ {
+			k := d.string()
+			m.Flagged[k] = d.string()
+		}
+	}
+	if d.err != nil {
+		return m, fmt.Errorf("avro: %w", d.err)
+	}
+	return m, nil
+}
+
+// ConfluentAvro prefixes the Avro payload with the Confluent schema registry wire format header (magic byte 0 and
+// the big-endian schema ID), as expected by Kafka consumers using a registry.
+func ConfluentAvro(schemaID uint32, payload []byte) []byte {
+	b := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(b[1:], schemaID)
+	return append(b, payload...)
+}
+
+// ParseConfluentAvro returns the schema ID and the Avro payload of the Confluent wire format.
+func ParseConfluentAvro(b []byte) (uint32, []byte, error) {
+	if len(b) < 5 || b[0] != 0 {
+		return 0, nil, errors.New("avro: not in confluent wire format")
+	}
+	return binary.BigEndian.Uint32(b[1:5]), b[5:], nil
+}
+
+// RegisterAvroSchema registers AvroMeasureSchema under the subject (ex. netatmo-measures-value) of the schema
+// registry and returns the schema ID. Registering an already registered schema returns the existing ID.
+func RegisterAvroSchema(ctx context.Context, client *http.Client, registryURL, subject string) (uint32, error) {
+	body, err := json.Marshal(map[string]string{"schema": AvroMeasureSchema})
+	if err != nil {
+		return 0, err
+	}
+	endpoint := strings.TrimSuffix(registryURL, "/") + "/subjects/" + url.PathEscape(subject) + "/versions"
+	data, err := sinkPost(ctx, client, endpoint, "application/vnd.schemaregistry.v1+json", bytes.NewReader(body), nil)
+	if err != nil {
+		return 0, fmt.Errorf("schema registry: %w", err)
+	}
+	var resp struct {
+		ID uint32 `json:"id"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return 0, fmt.Errorf("schema registry: %w", err)
+	}
+	return resp.ID, nil
+}
+
+// avroMagic defines the header of Avro object container files.
+var avroMagic = []byte{'O', 'b', 'j', 1}
+
+// WriteMeasuresAvro writes the measures as an Avro object container file (uncompressed, single block) embedding
+// AvroMeasureSchema, readable by Spark, Hive, DuckDB and other data lake tools.
+func WriteMeasuresAvro(w io.Writer, measures []Measure) error {
+	var sync [16]byte
+	if _, err := rand.Read(sync[:]); err != nil {
+		return err
+	}
+	b := append([]byte{}, avroMagic...)
+	b = appendAvroLong(b, 2)
+	b = appendAvroString(b, "avro.schema")
+	b = appendAvroString(b, AvroMeasureSchema)
+	b = appendAvroString(b, "avro.codec")
+	b = appendAvroString(b, "null")
+	b = appendAvroLong(b, 0)
+	b = append(b, sync[:]...)
+	if len(measures) > 0 {
+		var block []byte
+		for i := range measures {
+			block = append(block, MarshalMeasureAvro(&measures[i])...)
+		}
+		b = appendAvroLong(b, int64(len(measures)))
+		b = appendAvroLong(b, int64(len(block)))
+		b = append(b, block...)
+		b = append(b, sync[:]...)
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// ReadMeasuresAvro reads measures of an uncompressed Avro object container file written with AvroMeasureSchema.
+func ReadMeasuresAvro(r io.Reader) ([]Measure, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(avroMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || !bytes.Equal(magic, avroMagic) {
+		return nil, errors.New("avro: not an object container file")
+	}
+	d := avroDecoder{r: br}
+	meta := make(map[string]string)
+	for {
+		n := d.long()
+		if n == 0 || d.err != nil {
+			break
+		}
+		if n < 0 {
+			n = -n
+			d.long()
+		}
+		for i := int64(0); i < n && d.err == nil; i++ {
+			k := d.string()
+			meta[k] = d.string()
+		}
+	}
+	var sync [16]byte
+	d.read(sync[:])
+	if d.err != nil {
+		return nil, fmt.Errorf("avro: %w", d.err)
+	}
+	if codec := meta["avro.codec"]; codec != "" && codec != "null" {
+		return nil, fmt.Errorf("avro: unsupported codec: %s", codec)
+	}
+	var measures []Measure
+	for {
+		if _, err := br.Peek(1); err == io.EOF {
+			return measures, nil
+		}
+		count := d.long()
+		d.long() // Block size in bytes
+		for i := int64(0); i < count && d.err == nil; i++ {
+			m, err := readMeasureAvro(br)
+			if err != nil {
+				return measures, err
+			}
+			measures = append(measures, m)
+		}
+		var marker [16]byte
+		d.read(marker[:])
+		if d.err != nil {
+			return measures, fmt.Errorf("avro: %w", d.err)
+		}
+		if marker != sync {
+			return measures, errors.New("avro: invalid sync marker")
+		}
+	}
+}
+
+func appendAvroLong(b []byte, v int64) []byte {
+	return binary.AppendUvarint(b, uint64((v<<1)^(v>>63)))
+}
+
+func appendAvroString(b []byte, s string) []byte {
+	return append(appendAvroLong(b, int64(len(s))), s...)
+}
+
+type avroReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// avroDecoder reads Avro primitives, keeping the first error.
+type avroDecoder struct {
+	r   avroReader
+	err error
+}
+
+func (d *avroDecoder) long() int64 {
+	if d.err != nil {
+		return 0
+	}
+	u, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		d.err = unexpectedEOF(err)
+		return 0
+	}
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (d *avroDecoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	c, err := d.r.ReadByte()
+	d.err = unexpectedEOF(err)
+	return c
+}
+
+func (d *avroDecoder) read(b []byte) {
+	if d.err == nil {
+		_, err := io.ReadFull(d.r, b)
+		d.err = unexpectedEOF(err)
+	}
+}
+
+func (d *avroDecoder) double() float64 {
+	var b [8]byte
+	d.read(b[:])
+	return math.Float64frombits(binary.LittleEndian.Uint64(b[:]))
+}
+
+func (d *avroDecoder) string() string {
+	n := d.long()
+	if n < 0 || n > maxAvroLength {
+		if d.err == nil {
+			d.err = fmt.Errorf("invalid string length: %d", n)
+		}
+		return ""
+	}
+	b := make([]byte, n)
+	d.read(b)
+	return string(b)
+}
+
+// present reads the branch of a ["null", type] union.
+func (d *avroDecoder) present() bool {
+	switch branch := d.long(); branch {
+	case 0:
+		return false
+	case 1:
+		return true
+	default:
+		if d.err == nil {
+			d.err = fmt.Errorf("invalid union branch: %d", branch)
+		}
+		return false
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
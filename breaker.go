@@ -0,0 +1,95 @@
+package netatmo
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the API while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState defines state of the circuit breaker.
+type CircuitState string
+
+// Circuit breaker states.
+const (
+	CircuitClosed   CircuitState = "closed"    // Requests are sent
+	CircuitOpen     CircuitState = "open"      // Requests fail fast with ErrCircuitOpen
+	CircuitHalfOpen CircuitState = "half-open" // A single probe request is sent to test recovery
+)
+
+// WithCircuitBreaker opens the circuit after the number of consecutive failed requests (transport errors and 5xx
+// responses, not requests canceled or timed out by the caller's context), failing fast with ErrCircuitOpen. After
+// the cooldown a single probe request is let through; the circuit closes if it succeeds and opens again otherwise.
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(o *clientOptions) {
+		o.breaker = &circuitBreaker{threshold: failures, cooldown: cooldown, state: CircuitClosed}
+	}
+}
+
+// circuitBreaker counts consecutive failures of API requests.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+}
+
+// allow reports whether a request may be sent now, switching to half-open after the cooldown.
+func (b *circuitBreaker) allow(now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case CircuitOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		return nil
+	case CircuitHalfOpen:
+		return ErrCircuitOpen // Probe in flight
+	default:
+		return nil
+	}
+}
+
+// record updates the state with the result of an allowed request.
+func (b *circuitBreaker) record(success bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if success {
+		b.state, b.failures = CircuitClosed, 0
+		return
+	}
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.state, b.openedAt = CircuitOpen, now
+	}
+}
+
+// release ends an allowed request without an outcome (ex. canceled by the caller), letting the next request probe
+// again if this one was the probe.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitHalfOpen {
+		b.state = CircuitOpen // Cooldown already elapsed
+	}
+}
+
+func (b *circuitBreaker) current() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// CircuitState returns state of the circuit breaker, CircuitClosed if not enabled.
+func (c *Client) CircuitState() CircuitState {
+	if c.options.breaker == nil {
+		return CircuitClosed
+	}
+	return c.options.breaker.current()
+}
@@ -0,0 +1,77 @@
+package netatmo
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL defines lifetime of cached responses if WithCache is given no TTL, half the 10 minutes interval at
+// which stations upload their data.
+const DefaultCacheTTL = 5 * time.Minute
+
+// WithCache serves repeated GetStationsData and GetMeasureByNewest calls (and helpers based on them, ex. GetRain)
+// from memory for the TTL, DefaultCacheTTL if zero, sparing API quota of dashboards refreshing often. Responses are
+// cached per query, so calls with other devices or options are fetched separately; failures are not cached. Hits and
+// misses are counted in Stats.
+func WithCache(ttl time.Duration) Option {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return func(o *clientOptions) {
+		o.cache = &responseCache{ttl: ttl, entries: make(map[string]cachedResponse)}
+	}
+}
+
+// ClearCache discards the responses cached by WithCache, so the next calls fetch fresh data.
+func (c *Client) ClearCache() {
+	if cache := c.options.cache; cache != nil {
+		cache.clear()
+	}
+}
+
+// cacheable reports whether responses of the request are cached: station data and newest measures, not time ranges.
+func cacheable(endpoint string, query url.Values) bool {
+	return endpoint == "getstationsdata" || (endpoint == "getmeasure" && query.Get("date_end") == "last")
+}
+
+// responseCache keeps response bodies keyed by request URL.
+type responseCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	data    []byte
+	expires time.Time
+}
+
+// get returns the unexpired response of the URL.
+func (c *responseCache) get(u string, now time.Time) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[u]
+	if !ok || !now.Before(entry.expires) {
+		return nil, false
+	}
+	return entry.data, true
+}
+
+// put caches the response of the URL, dropping expired entries.
+func (c *responseCache) put(u string, data []byte, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[u] = cachedResponse{data: data, expires: now.Add(c.ttl)}
+}
+
+func (c *responseCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]cachedResponse)
+}
@@ -0,0 +1,80 @@
+package netatmo
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// CallInfo defines metadata of an API call, including the status, server time and execution time fields every
+// response carries.
+type CallInfo struct {
+	Endpoint   string
+	RequestID  string        // Empty unless WithRequestID is set
+	HTTPStatus int           // Zero if no response was received
+	Status     string        // Status field of the response (ex. "ok"), empty if absent
+	ServerTime time.Time     // Zero if absent
+	ExecTime   time.Duration // Server side execution time
+	Sent       time.Time
+	Received   time.Time
+}
+
+// Duration returns round trip time of the call.
+func (i CallInfo) Duration() time.Duration {
+	return i.Received.Sub(i.Sent)
+}
+
+// ClockSkew returns server time minus local time at the middle of the call, accurate to about a second since the
+// server time has second precision. The second result is false if the response had no server time.
+func (i CallInfo) ClockSkew() (time.Duration, bool) {
+	if i.ServerTime.IsZero() {
+		return 0, false
+	}
+	return i.ServerTime.Sub(i.Sent.Add(i.Duration() / 2)), true
+}
+
+// WithCallInfoHook calls the function after each API call with its metadata. Calls must be quick, they run on the
+// calling goroutine.
+func WithCallInfoHook(fn func(info CallInfo)) Option {
+	return func(o *clientOptions) {
+		o.callInfoHooks = append(o.callInfoHooks, fn)
+	}
+}
+
+// LastCallInfo returns metadata of the latest completed API call. The second result is false if no call was made.
+func (c *Client) LastCallInfo() (CallInfo, bool) {
+	c.lastCall.mu.Lock()
+	defer c.lastCall.mu.Unlock()
+	return c.lastCall.info, c.lastCall.ok
+}
+
+type lastCallInfo struct {
+	mu   sync.Mutex
+	info CallInfo
+	ok   bool
+}
+
+// responseMeta defines the metadata fields common to all API responses.
+type responseMeta struct {
+	Status     string  `json:"status"`
+	ExecTime   float64 `json:"time_exec"`
+	ServerTime int64   `json:"time_server"`
+}
+
+// recordCall parses the metadata of the response body, if any, and reports the call.
+func (c *Client) recordCall(info CallInfo, data []byte) {
+	var meta responseMeta
+	if len(data) > 0 && json.Unmarshal(data, &meta) == nil {
+		info.Status = meta.Status
+		info.ExecTime = time.Duration(meta.ExecTime * float64(time.Second))
+		if meta.ServerTime != 0 {
+			info.ServerTime = time.Unix(meta.ServerTime, 0)
+		}
+	}
+	c.lastCall.mu.Lock()
+	c.lastCall.info, c.lastCall.ok = info, true
+	c.lastCall.mu.Unlock()
+	for _, hook := range c.options.callInfoHooks {
+		hook(info)
+	}
+}
@@ -0,0 +1,336 @@
+package netatmo
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// ChartFormat defines image format of charts.
+type ChartFormat string
+
+// Supported chart formats.
+const (
+	ChartSVG ChartFormat = "svg"
+	ChartPNG ChartFormat = "png"
+)
+
+// ChartOptions defines appearance of charts. The zero value renders a 640x320 SVG without title.
+type ChartOptions struct {
+	Format ChartFormat // Defaults to ChartSVG
+	Width  int         // Pixels, defaults to 640
+	Height int         // Pixels, defaults to 320
+	Title  string
+	Unit   string      // Appended to Y axis labels (ex. °C)
+	Color  color.Color // Defaults to a blue
+}
+
+// Chart margins in pixels.
+const (
+	chartLeft   = 60
+	chartRight  = 20
+	chartTop    = 30
+	chartBottom = 30
+)
+
+var (
+	chartAxisColor = color.RGBA{0x66, 0x66, 0x66, 0xff}
+	chartGridColor = color.RGBA{0xdd, 0xdd, 0xdd, 0xff}
+	chartTextColor = color.RGBA{0x22, 0x22, 0x22, 0xff}
+	chartBlue      = color.RGBA{0x1e, 0x88, 0xe5, 0xff}
+)
+
+// WriteLineChart renders the points ordered by timestamp as a line over time (ex. temperature).
+func WriteLineChart(w io.Writer, points []Point, opts ChartOptions) error {
+	c := newChartCanvas(&opts)
+	low, high := valueRange(points, false)
+	plot := c.axes(&opts, points, low, high)
+	for i := 0; i+1 < len(points); i++ {
+		x1, y1 := plot(points[i])
+		x2, y2 := plot(points[i+1])
+		c.line(x1, y1, x2, y2, opts.Color)
+	}
+	return c.encode(w)
+}
+
+// WriteBarChart renders the points ordered by timestamp as bars from zero (ex. rain per step).
+func WriteBarChart(w io.Writer, points []Point, opts ChartOptions) error {
+	c := newChartCanvas(&opts)
+	low, high := valueRange(points, true)
+	plot := c.axes(&opts, points, low, high)
+	width := float64(opts.Width-chartLeft-chartRight) / math.Max(float64(len(points)), 1)
+	_, zero := plot(Point{Value: 0})
+	for _, p := range points {
+		x, y := plot(p)
+		// Bars are centered on their timestamp, clipped to the plot area
+		barLeft := math.Max(x-width/2+0.5, chartLeft+1)
+		barRight := math.Min(x+width/2-0.5, float64(opts.Width-chartRight))
+		c.rect(barLeft, math.Min(y, zero), math.Max(barRight-barLeft, 1), math.Abs(zero-y), opts.Color)
+	}
+	return c.encode(w)
+}
+
+// WriteWindRose renders the share of samples with wind coming from each of 16 compass directions, calm samples
+// excluded.
+func WriteWindRose(w io.Writer, s WindSeries, opts ChartOptions) error {
+	c := newChartCanvas(&opts)
+	var counts [16]int
+	total := 0
+	for _, sample := range s {
+		if sample.Angle == nil || sample.Strength == nil || *sample.Strength == 0 {
+			continue
+		}
+		counts[int(math.Round(float64(*sample.Angle)/22.5))%16]++
+		total++
+	}
+	maxCount := 0
+	for _, n := range counts {
+		if n > maxCount {
+			maxCount = n
+		}
+	}
+	cx, cy := float64(opts.Width)/2, float64(opts.Height+chartTop-chartBottom)/2
+	radius := math.Min(float64(opts.Width), float64(opts.Height-chartTop-chartBottom))/2 - 10
+	for _, ratio := range []float64{0.25, 0.5, 0.75, 1} {
+		c.polygon(circlePoints(cx, cy, radius*ratio), nil, chartGridColor)
+	}
+	for i, n := range counts {
+		if n == 0 {
+			continue
+		}
+		r := radius * float64(n) / float64(maxCount)
+		center := float64(i) * 22.5
+		wedge := [][2]float64{{cx, cy}}
+		for a := center - 10; a <= center+10; a += 2.5 {
+			rad := a * math.Pi / 180
+			wedge = append(wedge, [2]float64{cx + r*math.Sin(rad), cy - r*math.Cos(rad)})
+		}
+		c.polygon(wedge, opts.Color, nil)
+	}
+	for i, label := range []string{"N", "E", "S", "W"} {
+		rad := float64(i) * math.Pi / 2
+		c.text(cx+(radius+8)*math.Sin(rad), cy-(radius+8)*math.Cos(rad)+4, label, "middle")
+	}
+	if total > 0 {
+		c.text(float64(opts.Width-chartRight), float64(opts.Height-8),
+			fmt.Sprintf("outer ring: %.0f %%", percentOf(maxCount, total)), "end")
+	}
+	return c.encode(w)
+}
+
+// circlePoints approximates a circle by a polygon.
+func circlePoints(cx, cy, r float64) [][2]float64 {
+	var points [][2]float64
+	for a := 0.0; a < 360; a += 5 {
+		rad := a * math.Pi / 180
+		points = append(points, [2]float64{cx + r*math.Sin(rad), cy - r*math.Cos(rad)})
+	}
+	return points
+}
+
+// valueRange returns bounds of the Y axis, including zero if asked.
+func valueRange(points []Point, withZero bool) (float64, float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	if withZero {
+		low, high = 0, 0
+	}
+	for _, p := range points {
+		low, high = math.Min(low, p.Value), math.Max(high, p.Value)
+	}
+	switch {
+	case math.IsInf(low, 1):
+		return 0, 1
+	case high == low:
+		return low - 1, high + 1
+	}
+	return low, high
+}
+
+// chartCanvas draws on either SVG or PNG.
+type chartCanvas struct {
+	svg *strings.Builder // Nil for PNG
+	img *image.RGBA      // Nil for SVG
+}
+
+func newChartCanvas(opts *ChartOptions) *chartCanvas {
+	if opts.Width <= 0 {
+		opts.Width = 640
+	}
+	if opts.Height <= 0 {
+		opts.Height = 320
+	}
+	if opts.Color == nil {
+		opts.Color = chartBlue
+	}
+	c := &chartCanvas{}
+	if opts.Format == ChartPNG {
+		c.img = image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+		draw.Draw(c.img, c.img.Bounds(), image.White, image.Point{}, draw.Src)
+	} else {
+		c.svg = &strings.Builder{}
+		fmt.Fprintf(c.svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" `+
+			`font-family="sans-serif" font-size="11"><rect width="100%%" height="100%%" fill="white"/>`,
+			opts.Width, opts.Height, opts.Width, opts.Height)
+	}
+	if opts.Title != "" {
+		c.text(float64(opts.Width)/2, 18, opts.Title, "middle")
+	}
+	return c
+}
+
+// axes draws grid, axes and labels of a time series chart and returns the function placing points.
+func (c *chartCanvas) axes(opts *ChartOptions, points []Point, low, high float64) func(Point) (float64, float64) {
+	left, right := float64(chartLeft), float64(opts.Width-chartRight)
+	top, bottom := float64(chartTop), float64(opts.Height-chartBottom)
+	begin, end := int64(0), int64(1)
+	if len(points) > 0 {
+		begin, end = points[0].Timestamp, points[len(points)-1].Timestamp
+	}
+	if end == begin {
+		end = begin + 1
+	}
+	plot := func(p Point) (float64, float64) {
+		return left + (right-left)*float64(p.Timestamp-begin)/float64(end-begin),
+			bottom - (bottom-top)*(p.Value-low)/(high-low)
+	}
+	decimals := int(math.Max(0, 1-math.Floor(math.Log10((high-low)/4))))
+	for i := 0; i <= 4; i++ {
+		v := low + (high-low)*float64(i)/4
+		y := bottom - (bottom-top)*float64(i)/4
+		c.line(left, y, right, y, chartGridColor)
+		c.text(left-6, y+4, strings.TrimSpace(fmt.Sprintf("%.*f %s", decimals, v, opts.Unit)), "end")
+	}
+	for i, anchor := range []string{"start", "middle", "end"} {
+		x := left + (right-left)*float64(i)/2
+		t := time.Unix(begin+(end-begin)*int64(i)/2, 0)
+		c.text(x, bottom+18, t.Format("01-02 15:04"), anchor)
+	}
+	c.line(left, top, left, bottom, chartAxisColor)
+	c.line(left, bottom, right, bottom, chartAxisColor)
+	return plot
+}
+
+func (c *chartCanvas) line(x1, y1, x2, y2 float64, col color.Color) {
+	if c.svg != nil {
+		fmt.Fprintf(c.svg, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/>`, x1, y1, x2, y2, svgColor(col))
+		return
+	}
+	steps := math.Max(math.Abs(x2-x1), math.Abs(y2-y1))
+	for i := 0.0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = i / steps
+		}
+		c.img.Set(int(math.Round(x1+(x2-x1)*t)), int(math.Round(y1+(y2-y1)*t)), col)
+	}
+}
+
+func (c *chartCanvas) rect(x, y, width, height float64, col color.Color) {
+	if c.svg != nil {
+		fmt.Fprintf(c.svg, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`, x, y, width, height,
+			svgColor(col))
+		return
+	}
+	r := image.Rect(int(math.Round(x)), int(math.Round(y)), int(math.Round(x+width)), int(math.Round(y+height)))
+	draw.Draw(c.img, r, image.NewUniform(col), image.Point{}, draw.Over)
+}
+
+// polygon fills and/or strokes the polygon; nil colors are skipped.
+func (c *chartCanvas) polygon(points [][2]float64, fill, stroke color.Color) {
+	if c.svg != nil {
+		coords := make([]string, len(points))
+		for i, p := range points {
+			coords[i] = fmt.Sprintf("%.1f,%.1f", p[0], p[1])
+		}
+		fillAttr, strokeAttr := "none", "none"
+		if fill != nil {
+			fillAttr = svgColor(fill)
+		}
+		if stroke != nil {
+			strokeAttr = svgColor(stroke)
+		}
+		fmt.Fprintf(c.svg, `<polygon points="%s" fill="%s" stroke="%s"/>`, strings.Join(coords, " "), fillAttr,
+			strokeAttr)
+		return
+	}
+	if fill != nil {
+		bounds := image.Rectangle{}
+		for i, p := range points {
+			r := image.Rect(int(p[0]), int(p[1]), int(p[0])+1, int(p[1])+1)
+			if i == 0 {
+				bounds = r
+			}
+			bounds = bounds.Union(r)
+		}
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				if insidePolygon(points, float64(x)+0.5, float64(y)+0.5) {
+					c.img.Set(x, y, fill)
+				}
+			}
+		}
+	}
+	if stroke != nil {
+		for i := range points {
+			next := points[(i+1)%len(points)]
+			c.line(points[i][0], points[i][1], next[0], next[1], stroke)
+		}
+	}
+}
+
+// text draws the label with its baseline at y, anchored at x by "start", "middle" or "end".
+func (c *chartCanvas) text(x, y float64, label, anchor string) {
+	if c.svg != nil {
+		fmt.Fprintf(c.svg, `<text x="%.1f" y="%.1f" text-anchor="%s" fill="%s">%s</text>`, x, y, anchor,
+			svgColor(chartTextColor), html.EscapeString(label))
+		return
+	}
+	label = strings.Replace(label, "°", "", -1) // Not in the bitmap font
+	d := &font.Drawer{Dst: c.img, Src: image.NewUniform(chartTextColor), Face: basicfont.Face7x13}
+	width := float64(d.MeasureString(label).Round())
+	switch anchor {
+	case "middle":
+		x -= width / 2
+	case "end":
+		x -= width
+	}
+	d.Dot = fixed.P(int(math.Round(x)), int(math.Round(y)))
+	d.DrawString(label)
+}
+
+func (c *chartCanvas) encode(w io.Writer) error {
+	if c.svg != nil {
+		c.svg.WriteString("</svg>\n")
+		_, err := io.WriteString(w, c.svg.String())
+		return err
+	}
+	return png.Encode(w, c.img)
+}
+
+// insidePolygon reports whether the point is inside the polygon by the even-odd rule.
+func insidePolygon(points [][2]float64, x, y float64) bool {
+	inside := false
+	for i, j := 0, len(points)-1; i < len(points); j, i = i, i+1 {
+		a, b := points[i], points[j]
+		if (a[1] > y) != (b[1] > y) && x < (b[0]-a[0])*(y-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func svgColor(col color.Color) string {
+	r, g, b, _ := col.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
@@ -0,0 +1,143 @@
+package netatmo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultClickHouseBatchSize defines number of rows per insert of ClickHouseSink.
+const DefaultClickHouseBatchSize = 10000
+
+// ClickHouseSink inserts measures into a ClickHouse table over the HTTP interface, one row per measure with a
+// column per measurement (see ClickHouseSchema).
+type ClickHouseSink struct {
+	URL        string // HTTP interface (ex. http://localhost:8123)
+	Database   string // Defaults to "default"
+	Table      string // Defaults to "netatmo_measures"
+	Username   string // Optional
+	Password   string
+	BatchSize  int          // Rows per insert, defaults to DefaultClickHouseBatchSize
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+}
+
+// ClickHouseSchema returns the CREATE TABLE statement of the table written by ClickHouseSink. The table is
+// partitioned by month and ordered by module and time; ReplacingMergeTree collapses rows written twice for the same
+// module and timestamp on merges (query with FINAL for exact results).
+func ClickHouseSchema(table string) string {
+	var b strings.Builder
+	b.WriteString("CREATE TABLE IF NOT EXISTS " + table + " (\n")
+	b.WriteString("    timestamp DateTime('UTC') CODEC(DoubleDelta, ZSTD),\n")
+	b.WriteString("    account LowCardinality(String),\n")
+	b.WriteString("    device_id LowCardinality(String),\n")
+	b.WriteString("    module_id LowCardinality(String)")
+	for _, column := range clickHouseColumns() {
+		b.WriteString(",\n    " + column + " Nullable(Float32) CODEC(Gorilla, ZSTD)")
+	}
+	b.WriteString("\n) ENGINE = ReplacingMergeTree\n")
+	b.WriteString("PARTITION BY toYYYYMM(timestamp)\n")
+	b.WriteString("ORDER BY (device_id, module_id, timestamp)")
+	return b.String()
+}
+
+// CreateTable creates the table with ClickHouseSchema unless it exists.
+func (s *ClickHouseSink) CreateTable(ctx context.Context) error {
+	return s.post(ctx, "", strings.NewReader(ClickHouseSchema(s.table())))
+}
+
+// Write inserts the measures in batches of JSONEachRow rows.
+func (s *ClickHouseSink) Write(ctx context.Context, measures []Measure) error {
+	size := s.BatchSize
+	if size <= 0 {
+		size = DefaultClickHouseBatchSize
+	}
+	query := "INSERT INTO " + s.table() + " FORMAT JSONEachRow"
+	if IsDryRun(ctx) {
+		var body bytes.Buffer
+		encoder := json.NewEncoder(&body)
+		for i := range measures {
+			if err := encoder.Encode(clickHouseRow(&measures[i])); err != nil {
+				return err
+			}
+		}
+		dryRun(ctx, "clickhouse", fmt.Sprintf("%d rows into %s in %d batches", len(measures), s.table(),
+			(len(measures)+size-1)/size), body.String())
+		return nil
+	}
+	for start := 0; start < len(measures); start += size {
+		var body bytes.Buffer
+		encoder := json.NewEncoder(&body)
+		for i := start; i < len(measures) && i < start+size; i++ {
+			if err := encoder.Encode(clickHouseRow(&measures[i])); err != nil {
+				return err
+			}
+		}
+		if err := s.post(ctx, query, &body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// table returns the qualified table name.
+func (s *ClickHouseSink) table() string {
+	database, table := s.Database, s.Table
+	if database == "" {
+		database = "default"
+	}
+	if table == "" {
+		table = "netatmo_measures"
+	}
+	return database + "." + table
+}
+
+// post sends the body, which is the statement itself without the query, or the data of the query.
+func (s *ClickHouseSink) post(ctx context.Context, query string, body io.Reader) error {
+	endpoint := strings.TrimSuffix(s.URL, "/") + "/"
+	if query != "" {
+		endpoint += "?" + url.Values{"query": {query}}.Encode()
+	}
+	_, err := sinkPost(ctx, s.HTTPClient, endpoint, "text/plain; charset=utf-8", body, func(req *http.Request) {
+		if s.Username != "" {
+			req.Header.Set("X-ClickHouse-User", s.Username)
+			req.Header.Set("X-ClickHouse-Key", s.Password)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("clickhouse: %w", err)
+	}
+	return nil
+}
+
+// clickHouseColumns returns measurement columns in schema order.
+func clickHouseColumns() []string {
+	var columns []string
+	for _, names := range [][]string{TargetMeasurements, AggregateMeasurements, DerivedMeasurements} {
+		for _, name := range names {
+			if name != "date_max_gust" {
+				columns = append(columns, snakeCase(name))
+			}
+		}
+	}
+	return columns
+}
+
+// clickHouseRow returns JSONEachRow row of the measure. Omitted measurement columns are inserted as NULL.
+func clickHouseRow(m *Measure) map[string]interface{} {
+	row := map[string]interface{}{
+		"timestamp": time.Unix(m.Timestamp, 0).UTC().Format("2006-01-02 15:04:05"),
+		"account":   m.Account,
+		"device_id": m.DeviceID,
+		"module_id": m.ModuleID,
+	}
+	for _, v := range sinkValues(m) {
+		row[snakeCase(v.Name)] = v.Value
+	}
+	return row
+}
@@ -2,40 +2,73 @@ package netatmo
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
-	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/oauth2"
 )
 
 // TargetMeasurements defines list of target measurement attributes.
 var TargetMeasurements = []string{"Temperature", "CO2", "Humidity", "Pressure", "Noise", "WindStrength", "WindAngle",
-	"GustStrength", "GustAngle"}
+	"GustStrength", "GustAngle", "Rain"}
 
 // Client implements Netatmo API client.
 type Client struct {
-	oauth  *oauth2.Config
-	client *http.Client
+	oauth    *oauth2.Config
+	client   *http.Client
+	options  *clientOptions
+	stats    *clientStats
+	lastCall lastCallInfo
+
+	closeOnce sync.Once
+	closed    chan struct{} // Closed by Close, background goroutines of the client stop on it
 }
 
 // Measure defines each measurable series.
 type Measure struct {
-	DeviceID     string
-	ModuleID     string
-	Timestamp    int64
-	Temperature  *float64 // Nullable
-	CO2          *int     // Nullable
-	Humidity     *int     // Nullable
-	Pressure     *float64 // Nullable
-	Noise        *int     // Nullable
-	WindStrength *int     // Nullable
-	WindAngle    *int     // Nullable
-	GustStrength *int     // Nullable
-	GustAngle    *int     // Nullable
+	Account      string   `json:"account,omitempty"` // Account namespace set by Syncer, empty for single account
+	DeviceID     string   `json:"device_id"`
+	ModuleID     string   `json:"module_id"`
+	Timestamp    int64    `json:"timestamp"`
+	Temperature  *float64 `json:"temperature"`   // Nullable
+	CO2          *int     `json:"co2"`           // Nullable
+	Humidity     *int     `json:"humidity"`      // Nullable
+	Pressure     *float64 `json:"pressure"`      // Nullable
+	Noise        *int     `json:"noise"`         // Nullable
+	WindStrength *int     `json:"wind_strength"` // Nullable
+	WindAngle    *int     `json:"wind_angle"`    // Nullable
+	GustStrength *int     `json:"gust_strength"` // Nullable
+	GustAngle    *int     `json:"gust_angle"`    // Nullable
+	Rain         *float64 `json:"rain"`          // Nullable, mm since the previous sample
+
+	// CO2Calibrating is set by ApplyCO2Calibration when CO2 was gathered during the sensor calibration.
+	CO2Calibrating bool `json:"co2_calibrating,omitempty"`
+
+	// Flagged maps measurement names to reasons set by FilterSink for suspicious values (ex. "Temperature": "spike").
+	Flagged map[string]string `json:"flagged,omitempty"`
+
+	// Aggregate measurements, available only when the scale is not max (see AggregateMeasurements).
+	// At those scales GustStrength and GustAngle describe the strongest gust of the step.
+	MinTemperature *float64 `json:"min_temp,omitempty"`      // Nullable
+	MaxTemperature *float64 `json:"max_temp,omitempty"`      // Nullable
+	MinHumidity    *int     `json:"min_hum,omitempty"`       // Nullable
+	MaxHumidity    *int     `json:"max_hum,omitempty"`       // Nullable
+	MinPressure    *float64 `json:"min_pressure,omitempty"`  // Nullable
+	MaxPressure    *float64 `json:"max_pressure,omitempty"`  // Nullable
+	MinNoise       *int     `json:"min_noise,omitempty"`     // Nullable
+	MaxNoise       *int     `json:"max_noise,omitempty"`     // Nullable
+	SumRain        *float64 `json:"sum_rain,omitempty"`      // Nullable
+	MaxGustTime    *int64   `json:"date_max_gust,omitempty"` // Nullable
+
+	// Derived measurements, set by ApplyDerivedMeasurements (see DerivedMeasurements).
+	DewPoint *float64 `json:"dew_point,omitempty"` // Nullable, °C
+	Humidex  *float64 `json:"humidex,omitempty"`   // Nullable, °C
 }
 
 // Place defines place attributes.
@@ -44,7 +77,7 @@ type Place struct {
 	City     string    `json:"city"`     // Name of city (ex. 千代田区)
 	Country  string    `json:"country"`  // Country code (ex. JP)
 	Timezone string    `json:"timezone"` // TZ Database name (ex. Asia/Tokyo)
-	Location []float64 `json:"location"` // Lat, Lon (ex. 139.752778, 35.682500)
+	Location []float64 `json:"location"` // Lon, Lat (ex. 139.752778, 35.682500)
 }
 
 // Latitude returns latitude value from location data.
@@ -52,7 +85,7 @@ func (n *Place) Latitude() float64 {
 	if len(n.Location) != 2 {
 		return 0
 	}
-	return n.Location[0]
+	return n.Location[1]
 }
 
 // Longitude returns longitude value location data.
@@ -60,7 +93,7 @@ func (n *Place) Longitude() float64 {
 	if len(n.Location) != 2 {
 		return 0
 	}
-	return n.Location[1]
+	return n.Location[0]
 }
 
 // DashboardData defines newest measured data gathered by device or module.
@@ -126,6 +159,7 @@ type Device struct {
 	Place               Place          `json:"place"`
 	DashboardData       *DashboardData `json:"dashboard_data"` // Nullable
 	Modules             []Module       `json:"modules"`
+	Account             string         `json:"account,omitempty"` // Account namespace set by Syncer, not by the API
 }
 
 // Administrative defines user administrative attributes.
@@ -141,58 +175,22 @@ type Administrative struct {
 
 // DescribeUnit describes unit identifier.
 func (a *Administrative) DescribeUnit() string {
-	switch a.Unit {
-	case 0:
-		return "metric system"
-	case 1:
-		return "imperial system"
-	default:
-		return fmt.Sprintf("unknown unit: %d", a.Unit)
-	}
+	return a.DescribeUnitIn(DefaultLocale)
 }
 
 // DescribeWindUnit describes wind unit identifier.
 func (a *Administrative) DescribeWindUnit() string {
-	switch a.WindUnit {
-	case 0:
-		return "kph"
-	case 1:
-		return "mph"
-	case 2:
-		return "ms"
-	case 3:
-		return "beaufort"
-	case 4:
-		return "knot"
-	default:
-		return fmt.Sprintf("unknown wind unit: %d", a.WindUnit)
-	}
+	return a.DescribeWindUnitIn(DefaultLocale)
 }
 
 // DescribePressureUnit describes pressure unit identifier.
 func (a *Administrative) DescribePressureUnit() string {
-	switch a.PressureUnit {
-	case 0:
-		return "mbar"
-	case 1:
-		return "inHg"
-	case 2:
-		return "mmHg"
-	default:
-		return fmt.Sprintf("unknown pressure unit: %d", a.PressureUnit)
-	}
+	return a.DescribePressureUnitIn(DefaultLocale)
 }
 
 // DescribeFeelLikeAlgorithm describes identifier of algorithm used to compute feel like temperature.
 func (a *Administrative) DescribeFeelLikeAlgorithm() string {
-	switch a.FeelLikeAlgorithm {
-	case 0:
-		return "humidex"
-	case 1:
-		return "heat-index"
-	default:
-		return fmt.Sprintf("unknown feel like algorithm: %d", a.FeelLikeAlgorithm)
-	}
+	return a.DescribeFeelLikeAlgorithmIn(DefaultLocale)
 }
 
 // User defines user attributes.
@@ -227,82 +225,326 @@ type getMeasureResponse struct {
 }
 
 // NewClient will creates Netatmo client object.
-func NewClient(ctx context.Context, clientID, clientSecret, username, password string) (*Client, error) {
-	oauth := &oauth2.Config{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		Scopes:       []string{"read_station"},
-		Endpoint: oauth2.Endpoint{
-			AuthURL:  "https://api.netatmo.net/",
-			TokenURL: "https://api.netatmo.net/oauth2/token",
-		},
+func NewClient(ctx context.Context, clientID, clientSecret, username, password string, opts ...Option) (*Client, error) {
+	options := newClientOptions(opts)
+	ctx = options.context(ctx)
+	oauth := options.oauthConfig(clientID, clientSecret)
+	stored, err := options.storedToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		return newClient(ctx, oauth, stored, stored, options), nil
 	}
 	token, err := oauth.PasswordCredentialsToken(ctx, username, password)
+	if err != nil {
+		return nil, oauthError(err)
+	}
+	return newClient(ctx, oauth, token, nil, options), nil
+}
+
+// NewClientWithRefreshToken creates Netatmo client from a refresh token, obtained with the authorization code flow
+// (Netatmo no longer grants passwords to new applications) or from the token generator of the developer portal. The
+// access token is fetched on the first call and refreshed when it expires; Netatmo rotates refresh tokens on each
+// refresh and the client keeps using the latest one (see WithTokenStore to keep it across restarts; a stored token is
+// used instead of the refresh token argument).
+func NewClientWithRefreshToken(ctx context.Context, clientID, clientSecret, refreshToken string, opts ...Option) (*Client, error) {
+	if refreshToken == "" {
+		return nil, errors.New("empty refresh token")
+	}
+	options := newClientOptions(opts)
+	ctx = options.context(ctx)
+	oauth := options.oauthConfig(clientID, clientSecret)
+	stored, err := options.storedToken(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if stored != nil {
+		return newClient(ctx, oauth, stored, stored, options), nil
+	}
+	// An expired token without access token makes the token source refresh on first use
+	token := &oauth2.Token{RefreshToken: refreshToken, Expiry: time.Unix(1, 0)}
+	return newClient(ctx, oauth, token, token, options), nil
+}
+
+// NewClientWithToken creates Netatmo client from a token obtained elsewhere, ex. by another service handling the
+// authentication. The token is refreshed when it expires if it has a refresh token, otherwise calls fail once it has
+// expired. Unlike the other constructors, the token argument is used even if the token store has a saved one;
+// refreshed tokens are saved as usual.
+func NewClientWithToken(ctx context.Context, clientID, clientSecret string, token *oauth2.Token, opts ...Option) (*Client, error) {
+	if token == nil || (token.AccessToken == "" && token.RefreshToken == "") {
+		return nil, errors.New("empty token")
+	}
+	options := newClientOptions(opts)
+	ctx = options.context(ctx)
+	copied := *token // the token source must not share the caller's token
+	return newClient(ctx, options.oauthConfig(clientID, clientSecret), &copied, &copied, options), nil
+}
+
+// newClient creates client authenticated with the token, refreshed by the OAuth2 configuration. Tokens other than
+// the known one (nil if the token itself is new) are passed to the token store and hooks of the options.
+func newClient(ctx context.Context, oauth *oauth2.Config, token, known *oauth2.Token, options *clientOptions) *Client {
+	var source oauth2.TokenSource = oauthErrorTokenSource{oauth.TokenSource(ctx, token)}
+	if options.tokenStore != nil || len(options.tokenHooks) > 0 {
+		source = &notifyingTokenSource{source: source, last: known,
+			notify: func(t *oauth2.Token) error { return options.tokenChanged(ctx, t) }}
+	}
+	// Outermost source, so that Revoke discards the token cached for reuse
+	source = &revocableTokenSource{source: oauth2.ReuseTokenSource(nil, source), last: token}
 	return &Client{
-		oauth:  oauth,
-		client: oauth.Client(ctx, token),
-	}, err
+		oauth:   oauth,
+		client:  &http.Client{Transport: &oauth2.Transport{Base: options.baseTransport, Source: source}},
+		options: options,
+		stats:   newClientStats(),
+		closed:  make(chan struct{}),
+	}
 }
 
 // GetStationsData gathers station data from Netatmo API.
 // Reference: https://dev.netatmo.com/apidocumentation/weather#getstationsdata
 func (c *Client) GetStationsData() ([]Device, *User, error) {
-	resp, err := c.client.Get("https://api.netatmo.com/api/getstationsdata")
-	if err != nil {
+	return c.GetStationsDataContext(context.Background())
+}
+
+// GetStationsDataContext is GetStationsData with a context canceling the request.
+func (c *Client) GetStationsDataContext(ctx context.Context) ([]Device, *User, error) {
+	devices, user, err := c.getStationsData(ctx, nil)
+	cache := c.options.offline
+	if cache == nil {
+		return devices, user, err
+	}
+	if err == nil {
+		cache.putStations(devices, user)
+		return devices, user, nil
+	}
+	if !offlineFailure(err) {
 		return nil, nil, err
 	}
-	data, err := ioutil.ReadAll(resp.Body)
+	cached, cachedUser, ok := cache.stations(ctx)
+	if !ok {
+		c.stats.cache(false)
+		return nil, nil, err
+	}
+	c.stats.cache(true)
+	return cached, cachedUser, &StaleError{Age: stationsAge(cached, c.now()), Err: err}
+}
+
+func (c *Client) getStationsData(ctx context.Context, query url.Values) ([]Device, *User, error) {
+	data, err := c.get(ctx, "getstationsdata", query)
 	if err != nil {
 		return nil, nil, err
 	}
 	var respData getStationsDataResponse
-	if err := json.Unmarshal(data, &respData); err != nil {
+	if err := c.decode("getstationsdata", data, &respData); err != nil {
 		return nil, nil, err
 	}
 	return respData.Body.Devices, &respData.Body.User, nil
 }
 
-// GetMeasureByTimeRange gathers measure data by specified time window.
+// getDevice gathers station data of the single device.
+func (c *Client) getDevice(ctx context.Context, deviceID string) (*Device, error) {
+	devices, _, err := c.getStationsData(ctx, url.Values{"device_id": {deviceID}})
+	if err != nil {
+		return nil, err
+	}
+	for i := range devices {
+		if devices[i].ID == deviceID {
+			return &devices[i], nil
+		}
+	}
+	return nil, fmt.Errorf("device not found: %s", deviceID)
+}
+
+// GetMeasureByTimeRange gathers measure data by specified time window. Zero begin omits the beginning of the window
+// and zero end extends the window to now. A single call returns at most the oldest 1024 steps of the window, in the
+// order of the options (see GetMeasureSince and EachMeasurePage).
 // Reference: https://dev.netatmo.com/apidocumentation/weather#getmeasure
-func (c *Client) GetMeasureByTimeRange(deviceID, moduleID string, begin, end int64) ([]Measure, error) {
-	resp, err := c.client.Get("https://api.netatmo.com/api/getmeasure" +
-		"?device_id=" + deviceID +
-		"&module_id=" + moduleID +
-		"&scale=max" + // {max, 30min, 1hour, 3hours, 1day, 1week, 1month}
-		"&type=" + strings.Join(TargetMeasurements, ",") +
-		"&real_time=true" + // default: false
-		"&date_begin=" + strconv.FormatInt(begin, 10) +
-		"&date_end=" + strconv.FormatInt(end, 10))
+func (c *Client) GetMeasureByTimeRange(deviceID, moduleID string, begin, end int64, opts ...MeasureOption) ([]Measure, error) {
+	return c.GetMeasureByTimeRangeContext(context.Background(), deviceID, moduleID, begin, end, opts...)
+}
+
+// GetMeasureByTimeRangeContext is GetMeasureByTimeRange with a context canceling the request.
+func (c *Client) GetMeasureByTimeRangeContext(ctx context.Context, deviceID, moduleID string, begin, end int64,
+	opts ...MeasureOption) ([]Measure, error) {
+	req, err := newMeasureRequest(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.validateRange(begin, end, c.now()); err != nil {
+		return nil, err
+	}
+	measures, err := c.getMeasureRange(ctx, deviceID, moduleID, begin, end, req)
 	if err != nil {
 		return nil, err
 	}
-	data, err := ioutil.ReadAll(resp.Body)
+	return req.sort(measures), nil
+}
+
+// getMeasureRange gathers a single page of measure data of the time window in ascending order.
+func (c *Client) getMeasureRange(ctx context.Context, deviceID, moduleID string, begin, end int64, req *measureRequest) ([]Measure, error) {
+	if req.realTime == nil {
+		realTime := true // API default: false
+		req.realTime = &realTime
+	}
+	query := req.values(deviceID, moduleID)
+	if begin != 0 {
+		query.Set("date_begin", strconv.FormatInt(begin, 10))
+	}
+	if end != 0 {
+		query.Set("date_end", strconv.FormatInt(end, 10))
+	}
+	data, err := c.get(ctx, "getmeasure", query)
 	if err != nil {
 		return nil, err
 	}
-	return buildGetMeasureResponse(deviceID, moduleID, data)
+	return c.buildGetMeasureResponse(deviceID, moduleID, req.types, data)
+}
+
+// GetMeasureSince gathers all measure data from the specified unix time to now, following as many pages as needed.
+// Reference: https://dev.netatmo.com/apidocumentation/weather#getmeasure
+func (c *Client) GetMeasureSince(deviceID, moduleID string, since int64, opts ...MeasureOption) ([]Measure, error) {
+	return c.GetMeasureSinceContext(context.Background(), deviceID, moduleID, since, opts...)
+}
+
+// GetMeasureSinceContext is GetMeasureSince with a context canceling the requests.
+func (c *Client) GetMeasureSinceContext(ctx context.Context, deviceID, moduleID string, since int64,
+	opts ...MeasureOption) ([]Measure, error) {
+	return c.getMeasurePages(ctx, deviceID, moduleID, since, 0, opts)
+}
+
+// getMeasurePages gathers measure data of the time window following as many pages as needed.
+func (c *Client) getMeasurePages(ctx context.Context, deviceID, moduleID string, begin, end int64, opts []MeasureOption) ([]Measure, error) {
+	req, err := newMeasureRequest(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.validateRange(begin, end, c.now()); err != nil {
+		return nil, err
+	}
+	measures, err := c.getMeasureAll(ctx, deviceID, moduleID, begin, end, req)
+	if err != nil {
+		return nil, err
+	}
+	return req.sort(measures), nil
+}
+
+// getMeasureAll gathers measure data of the time window in ascending order following as many pages as needed.
+func (c *Client) getMeasureAll(ctx context.Context, deviceID, moduleID string, begin, end int64, req *measureRequest) ([]Measure, error) {
+	var measures []Measure
+	for {
+		page, err := c.getMeasureRange(ctx, deviceID, moduleID, begin, end, req)
+		if err != nil {
+			return nil, err
+		}
+		measures = append(measures, page...)
+		if len(page) < measureLimit {
+			return measures, nil
+		}
+		begin = page[len(page)-1].Timestamp + 1
+	}
+}
+
+// EachMeasurePage gathers measure data of the time window page by page, passing each non-empty page to fn until it
+// returns false. With OrderDescending, pages start at the end of the window (zero means now) and go back in time,
+// so consumers of the latest pages do not fetch the whole window; a zero begin then stops at the first window of
+// 512 steps without data.
+func (c *Client) EachMeasurePage(deviceID, moduleID string, begin, end int64, fn func(page []Measure) bool,
+	opts ...MeasureOption) error {
+	return c.EachMeasurePageContext(context.Background(), deviceID, moduleID, begin, end, fn, opts...)
+}
+
+// EachMeasurePageContext is EachMeasurePage with a context canceling the requests.
+func (c *Client) EachMeasurePageContext(ctx context.Context, deviceID, moduleID string, begin, end int64,
+	fn func(page []Measure) bool, opts ...MeasureOption) error {
+	req, err := newMeasureRequest(opts)
+	if err != nil {
+		return err
+	}
+	if err := req.validateRange(begin, end, c.now()); err != nil {
+		return err
+	}
+	if req.order == OrderAscending {
+		for {
+			page, err := c.getMeasureRange(ctx, deviceID, moduleID, begin, end, req)
+			if err != nil {
+				return err
+			}
+			if len(page) > 0 && !fn(page) {
+				return nil
+			}
+			if len(page) < measureLimit {
+				return nil
+			}
+			begin = page[len(page)-1].Timestamp + 1
+		}
+	}
+	if end == 0 {
+		end = c.now().Unix()
+	}
+	// Half the page limit leaves room for irregular sampling, full windows are split by getMeasureAll anyway
+	window := int64(req.scale.step()/time.Second) * measureLimit / 2
+	for end >= begin {
+		windowBegin := end - window + 1
+		if windowBegin < begin {
+			windowBegin = begin
+		}
+		measures, err := c.getMeasureAll(ctx, deviceID, moduleID, windowBegin, end, req)
+		if err != nil {
+			return err
+		}
+		if len(measures) == 0 && begin == 0 {
+			return nil
+		}
+		if len(measures) > 0 && !fn(req.sort(measures)) {
+			return nil
+		}
+		end = windowBegin - 1
+	}
+	return nil
 }
 
 // GetMeasureByNewest gathers newest measure data.
 // Reference: https://dev.netatmo.com/apidocumentation/weather#getmeasure
-func (c *Client) GetMeasureByNewest(deviceID, moduleID string) (*Measure, error) {
-	resp, err := c.client.Get("https://api.netatmo.com/api/getmeasure" +
-		"?device_id=" + deviceID +
-		"&module_id=" + moduleID +
-		"&scale=max" + // {max, 30min, 1hour, 3hours, 1day, 1week, 1month}
-		"&type=" + strings.Join(TargetMeasurements, ",") +
-		"&date_end=last")
+func (c *Client) GetMeasureByNewest(deviceID, moduleID string, opts ...MeasureOption) (*Measure, error) {
+	return c.GetMeasureByNewestContext(context.Background(), deviceID, moduleID, opts...)
+}
+
+// GetMeasureByNewestContext is GetMeasureByNewest with a context canceling the request.
+func (c *Client) GetMeasureByNewestContext(ctx context.Context, deviceID, moduleID string,
+	opts ...MeasureOption) (*Measure, error) {
+	measure, err := c.getMeasureByNewest(ctx, deviceID, moduleID, opts)
+	cache := c.options.offline
+	if cache == nil {
+		return measure, err
+	}
+	if err == nil {
+		cache.putNewest(deviceID, moduleID, measure)
+		return measure, nil
+	}
+	if !offlineFailure(err) {
+		return nil, err
+	}
+	cached, ok := cache.measure(ctx, deviceID, moduleID)
+	if !ok {
+		c.stats.cache(false)
+		return nil, err
+	}
+	c.stats.cache(true)
+	return cached, &StaleError{Age: c.now().Sub(time.Unix(cached.Timestamp, 0)), Err: err}
+}
+
+func (c *Client) getMeasureByNewest(ctx context.Context, deviceID, moduleID string, opts []MeasureOption) (*Measure, error) {
+	req, err := newMeasureRequest(opts)
 	if err != nil {
 		return nil, err
 	}
-	data, err := ioutil.ReadAll(resp.Body)
+	query := req.values(deviceID, moduleID)
+	query.Set("date_end", "last")
+	data, err := c.get(ctx, "getmeasure", query)
 	if err != nil {
 		return nil, err
 	}
-	measures, err := buildGetMeasureResponse(deviceID, moduleID, data)
+	measures, err := c.buildGetMeasureResponse(deviceID, moduleID, req.types, data)
 	if err != nil {
 		return nil, err
 	}
@@ -312,27 +554,102 @@ func (c *Client) GetMeasureByNewest(deviceID, moduleID string) (*Measure, error)
 	return &measures[len(measures)-1], nil
 }
 
-func buildGetMeasureResponse(deviceID, moduleID string, data []byte) ([]Measure, error) {
+// get calls the API endpoint and returns the response body.
+func (c *Client) get(ctx context.Context, endpoint string, query url.Values) (data []byte, err error) {
+	u := c.options.endpoint.APIURL + endpoint
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	if c.isClosed() {
+		return nil, &RequestError{Endpoint: endpoint, Err: ErrClientClosed}
+	}
+	if cache := c.options.cache; cache != nil && cacheable(endpoint, query) {
+		if data, ok := cache.get(u, c.now()); ok {
+			c.stats.cache(true)
+			return data, nil
+		}
+		c.stats.cache(false)
+		defer func() {
+			if err == nil {
+				cache.put(u, data, c.now())
+			}
+		}()
+	}
+	if err := c.waitRateLimit(ctx); err != nil {
+		return nil, &RequestError{Endpoint: endpoint, Err: err}
+	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	requestID := ""
+	if c.options.requestIDHeader != "" {
+		if id, ok := RequestIDFromContext(ctx); ok {
+			requestID = id
+		} else {
+			requestID = c.options.newRequestID()
+			ctx = ContextWithRequestID(ctx, requestID)
+		}
+		req.Header.Set(c.options.requestIDHeader, requestID)
+	}
+	status, start := 0, c.now()
+	if b := c.options.breaker; b != nil {
+		if err := b.allow(start); err != nil {
+			return nil, &RequestError{Endpoint: endpoint, RequestID: requestID, Err: err}
+		}
+		defer func() {
+			if err != nil && ctx.Err() != nil {
+				b.release() // Canceled or timed out by the caller, not a failure of the API
+				return
+			}
+			b.record(transportError(err) == nil && status < 500, c.now())
+		}()
+	}
+	var body []byte
+	ctx, endTrace := c.startRequest(ctx, endpoint, query)
+	defer func() {
+		c.recordCall(CallInfo{Endpoint: endpoint, RequestID: requestID, HTTPStatus: status, Sent: start,
+			Received: c.now()}, body)
+		c.stats.request(endpoint, status, transportError(err))
+		for _, hook := range c.options.metricsHooks {
+			hook.ObserveRequest(endpoint, c.now().Sub(start), status, err)
+		}
+		c.logRequest(ctx, endpoint, requestID, c.now().Sub(start), status, err)
+		endTrace(status, err)
+	}()
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, &RequestError{Endpoint: endpoint, RequestID: requestID, Err: err}
+	}
+	defer resp.Body.Close()
+	status = resp.StatusCode
+	body, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &RequestError{Endpoint: endpoint, RequestID: requestID, Err: err}
+	}
+	if err := apiError(status, body); err != nil {
+		return nil, &RequestError{Endpoint: endpoint, RequestID: requestID, Err: err}
+	}
+	return body, nil
+}
+
+func (c *Client) buildGetMeasureResponse(deviceID, moduleID string, types []string, data []byte) ([]Measure, error) {
 	var response getMeasureResponse
-	if err := json.Unmarshal(data, &response); err != nil {
+	if err := c.decode("getmeasure", data, &response); err != nil {
 		return nil, err
 	}
 	var measures []Measure
 	for _, v := range response.Body {
-		for i, m := range v.Value {
+		for i, values := range v.Value {
 			measure := Measure{
-				DeviceID:     deviceID,
-				ModuleID:     moduleID,
-				Timestamp:    v.BeginTime + (v.StepTime * int64(i)),
-				Temperature:  handleFloat(m[0]),
-				CO2:          handleInt(m[1]),
-				Humidity:     handleInt(m[2]),
-				Pressure:     handleFloat(m[3]),
-				Noise:        handleInt(m[4]),
-				WindStrength: handleInt(m[5]),
-				WindAngle:    handleInt(m[6]),
-				GustStrength: handleInt(m[7]),
-				GustAngle:    handleInt(m[8]),
+				DeviceID:  deviceID,
+				ModuleID:  moduleID,
+				Timestamp: v.BeginTime + (v.StepTime * int64(i)),
+			}
+			for j, value := range values {
+				if j < len(types) {
+					measure.setResponseValue(types[j], value)
+				}
 			}
 			measures = append(measures, measure)
 		}
@@ -342,24 +659,3 @@ func buildGetMeasureResponse(deviceID, moduleID string, data []byte) ([]Measure,
 	}
 	return measures, nil
 }
-
-func handleFloat(v *float64) *float64 {
-	if v == nil {
-		return nil
-	}
-	if *v == 0.0 { // If the value exactly matches 0.0, treat it as null value
-		return nil
-	}
-	return v
-}
-
-func handleInt(v *float64) *int {
-	if v == nil {
-		return nil
-	}
-	if *v == 0.0 { // If the value exactly matches 0.0, treat it as null value
-		return nil
-	}
-	iv := int(*v)
-	return &iv
-}
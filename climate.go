@@ -0,0 +1,233 @@
+package netatmo
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Thresholds of climate statistics.
+const (
+	FrostDayBelow      = 0.0  // Daily low in °C below which a day is a frost day
+	IceDayBelow        = 0.0  // Daily high in °C below which a day is an ice day
+	SummerDayAbove     = 25.0 // Daily high in °C above which a day is a summer day
+	TropicalNightAbove = 20.0 // Daily low in °C above which a night is tropical
+	RainDayAtLeast     = 1.0  // Daily rain in mm from which a day is a rain day (WMO precipitation day)
+	NormalMinDays      = 20   // Days with data from which a month counts in normals
+)
+
+// DayValue defines a daily value and its day.
+type DayValue struct {
+	Date  time.Time // Midnight of the day in the station time zone
+	Value float64
+}
+
+// ClimateSummary defines climate statistics of a month or a year, from daily outdoor temperature extremes and rain
+// totals. Daily mean temperatures are the mean of the low and the high.
+type ClimateSummary struct {
+	TemperatureDays int       // Days with outdoor temperature
+	RainDataDays    int       // Days with rain gauge data
+	MeanTemperature *float64  // Nullable, °C
+	MeanLow         *float64  // Nullable, °C
+	MeanHigh        *float64  // Nullable, °C
+	Low             *DayValue // Nullable, lowest temperature in °C
+	High            *DayValue // Nullable, highest temperature in °C
+	FrostDays       int
+	IceDays         int
+	SummerDays      int
+	TropicalNights  int
+	Rain            *float64  // Nullable, total in mm
+	RainDays        int       // Days with at least RainDayAtLeast
+	MaxDailyRain    *DayValue // Nullable, mm
+}
+
+// MonthClimate defines climate statistics of a calendar month.
+type MonthClimate struct {
+	Year  int
+	Month time.Month
+	ClimateSummary
+}
+
+// YearClimate defines climate statistics of a calendar year and its months with data.
+type YearClimate struct {
+	Year int
+	ClimateSummary
+	Months []MonthClimate
+}
+
+// MonthNormal defines means of a calendar month over the years with at least NormalMinDays days of data, as running
+// normals of a station history shorter than the 30 years of WMO normals.
+type MonthNormal struct {
+	Month           time.Month
+	Years           int      // Years averaged
+	MeanTemperature *float64 // Nullable, °C
+	MeanLow         *float64 // Nullable, °C
+	MeanHigh        *float64 // Nullable, °C
+	FrostDays       float64  // Mean per year
+	SummerDays      float64
+	Rain            *float64 // Nullable, mean total in mm
+	RainDays        float64
+}
+
+// Climatology defines climate statistics of a station, suitable for year in review reports.
+type Climatology struct {
+	DeviceID string
+	Name     string
+	Years    []YearClimate // Ordered by year
+	Normals  []MonthNormal // Ordered by month, months without enough data are omitted
+}
+
+// climateAccumulator sums daily summaries.
+type climateAccumulator struct {
+	summary                  ClimateSummary
+	sumMean, sumLow, sumHigh float64
+}
+
+func (a *climateAccumulator) add(day *DaySummary) {
+	s := &a.summary
+	if day.Low != nil && day.High != nil {
+		low, high := *day.Low, *day.High
+		s.TemperatureDays++
+		a.sumLow, a.sumHigh, a.sumMean = a.sumLow+low, a.sumHigh+high, a.sumMean+(low+high)/2
+		if s.Low == nil || low < s.Low.Value {
+			s.Low = &DayValue{Date: day.Date, Value: low}
+		}
+		if s.High == nil || high > s.High.Value {
+			s.High = &DayValue{Date: day.Date, Value: high}
+		}
+		if low < FrostDayBelow {
+			s.FrostDays++
+		}
+		if high < IceDayBelow {
+			s.IceDays++
+		}
+		if high > SummerDayAbove {
+			s.SummerDays++
+		}
+		if low > TropicalNightAbove {
+			s.TropicalNights++
+		}
+	}
+	if day.Rain != nil {
+		rain := *day.Rain
+		s.RainDataDays++
+		total := rain
+		if s.Rain != nil {
+			total += *s.Rain
+		}
+		s.Rain = &total
+		if rain >= RainDayAtLeast {
+			s.RainDays++
+		}
+		if s.MaxDailyRain == nil || rain > s.MaxDailyRain.Value {
+			s.MaxDailyRain = &DayValue{Date: day.Date, Value: rain}
+		}
+	}
+}
+
+func (a *climateAccumulator) result() ClimateSummary {
+	s := a.summary
+	if n := float64(s.TemperatureDays); n > 0 {
+		s.MeanTemperature, s.MeanLow, s.MeanHigh = floatPtr(a.sumMean/n), floatPtr(a.sumLow/n), floatPtr(a.sumHigh/n)
+	}
+	return s
+}
+
+// ComputeClimatology computes monthly and annual statistics and monthly normals of the station from its measures
+// (outdoor module temperature and rain gauge), with days in the station time zone.
+func ComputeClimatology(d *Device, measures []Measure) *Climatology {
+	c := &Climatology{DeviceID: d.ID, Name: d.StationName}
+	years := make(map[int]*climateAccumulator)
+	months := make(map[[2]int]*climateAccumulator)
+	for _, day := range summarizeDays(d, measures) {
+		y, m := day.Date.Year(), int(day.Date.Month())
+		if years[y] == nil {
+			years[y] = &climateAccumulator{}
+		}
+		if months[[2]int{y, m}] == nil {
+			months[[2]int{y, m}] = &climateAccumulator{}
+		}
+		years[y].add(&day)
+		months[[2]int{y, m}].add(&day)
+	}
+	for y, a := range years {
+		year := YearClimate{Year: y, ClimateSummary: a.result()}
+		for m := time.January; m <= time.December; m++ {
+			if a := months[[2]int{y, int(m)}]; a != nil {
+				year.Months = append(year.Months, MonthClimate{Year: y, Month: m, ClimateSummary: a.result()})
+			}
+		}
+		c.Years = append(c.Years, year)
+	}
+	sort.Slice(c.Years, func(i, j int) bool { return c.Years[i].Year < c.Years[j].Year })
+	c.Normals = monthNormals(c.Years)
+	return c
+}
+
+// monthNormals averages months with enough days of data over the years.
+func monthNormals(years []YearClimate) []MonthNormal {
+	var normals []MonthNormal
+	for m := time.January; m <= time.December; m++ {
+		n := MonthNormal{Month: m}
+		var temperature, low, high, rain float64
+		rainYears := 0
+		for _, y := range years {
+			for _, mc := range y.Months {
+				if mc.Month != m {
+					continue
+				}
+				if mc.TemperatureDays >= NormalMinDays {
+					n.Years++
+					temperature, low, high = temperature+*mc.MeanTemperature, low+*mc.MeanLow, high+*mc.MeanHigh
+					n.FrostDays += float64(mc.FrostDays)
+					n.SummerDays += float64(mc.SummerDays)
+				}
+				if mc.RainDataDays >= NormalMinDays {
+					rainYears++
+					rain += *mc.Rain
+					n.RainDays += float64(mc.RainDays)
+				}
+			}
+		}
+		if n.Years == 0 && rainYears == 0 {
+			continue
+		}
+		if years := float64(n.Years); years > 0 {
+			n.MeanTemperature, n.MeanLow, n.MeanHigh = floatPtr(temperature/years), floatPtr(low/years),
+				floatPtr(high/years)
+			n.FrostDays, n.SummerDays = n.FrostDays/years, n.SummerDays/years
+		}
+		if rainYears > 0 {
+			n.Rain, n.RainDays = floatPtr(rain/float64(rainYears)), n.RainDays/float64(rainYears)
+		}
+		normals = append(normals, n)
+	}
+	return normals
+}
+
+// QueryClimatology computes climatology of each stored station from stored measures matching the filter.
+func QueryClimatology(ctx context.Context, store Store, filter MeasureFilter) ([]Climatology, error) {
+	devices, err := store.Devices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []Climatology
+	for i := range devices {
+		d := &devices[i]
+		if (filter.Account != "" && filter.Account != d.Account) || (filter.DeviceID != "" && filter.DeviceID != d.ID) {
+			continue
+		}
+		f := filter
+		f.Account, f.DeviceID = d.Account, d.ID
+		measures, err := store.Measures(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *ComputeClimatology(d, measures))
+	}
+	return result, nil
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,162 @@
+package netatmo
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock defines source of time of time-dependent logic (pollers, background jobs, circuit breaker cooldowns, ages of
+// cached data). Tests can use a ManualClock to be deterministic and fast.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker defines periodic ticks of a Clock.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// SystemClock uses the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTicker struct {
+	t *time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t systemTicker) Stop() {
+	t.t.Stop()
+}
+
+type clockKey struct{}
+
+// ContextWithClock returns context carrying the clock, used by functions taking a context (ex. RunRetention,
+// RunCompaction, QueryStore) instead of SystemClock.
+func ContextWithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// ClockFromContext returns the clock carried by the context, or SystemClock.
+func ClockFromContext(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok && clock != nil {
+		return clock
+	}
+	return SystemClock
+}
+
+// WithClock sets the clock of the client, used by circuit breaker cooldowns, request durations and ages of cached
+// data. Default is SystemClock.
+func WithClock(clock Clock) Option {
+	return func(o *clientOptions) {
+		o.clock = clock
+	}
+}
+
+// orSystemClock returns the clock, or SystemClock if nil.
+func orSystemClock(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+	return clock
+}
+
+// ManualClock is a Clock moved only by Set and Advance, firing due tickers. It is safe for concurrent use.
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*manualTicker
+}
+
+// NewManualClock returns a clock stopped at the time.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTicker returns a ticker firing when the clock passes each multiple of the duration from now. Like time.Ticker,
+// ticks are dropped if the previous one was not received.
+func (c *ManualClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for ManualClock.NewTicker")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &manualTicker{clock: c, c: make(chan time.Time, 1), interval: d, next: c.now.Add(d)}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward by the duration.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to the time, firing tickers due until then in time order.
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for {
+		sort.Slice(c.tickers, func(i, j int) bool { return c.tickers[i].next.Before(c.tickers[j].next) })
+		if len(c.tickers) == 0 || c.tickers[0].next.After(now) {
+			break
+		}
+		t := c.tickers[0]
+		select {
+		case t.c <- t.next:
+		default:
+		}
+		t.next = t.next.Add(t.interval)
+	}
+	c.now = now
+}
+
+type manualTicker struct {
+	clock    *ManualClock
+	c        chan time.Time
+	interval time.Duration
+	next     time.Time
+}
+
+func (t *manualTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *manualTicker) Stop() {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, other := range c.tickers {
+		if other == t {
+			c.tickers = append(c.tickers[:i], c.tickers[i+1:]...)
+			return
+		}
+	}
+}
+
+// now returns the current time of the client clock.
+func (c *Client) now() time.Time {
+	return orSystemClock(c.options.clock).Now()
+}
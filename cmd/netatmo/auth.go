@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mikan/netatmo-weather-go"
+)
+
+func runAuth(cred *credentials, args []string) error {
+	fs := flag.NewFlagSet("auth", flag.ExitOnError)
+	redirect := fs.String("redirect", netatmo.DefaultRedirectURL, "redirect URI registered for the application")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if cred.clientID == "" || cred.clientSecret == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	flow := &netatmo.AuthCodeFlow{ClientID: cred.clientID, ClientSecret: cred.clientSecret, RedirectURL: *redirect,
+		Options: []netatmo.Option{netatmo.WithEndpoint(cred.endpoint)}}
+	token, err := flow.Token(ctx, func(authURL string) {
+		fmt.Fprintf(os.Stderr, "Open the following URL in a browser and grant access:\n\n%s\n\n", authURL)
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Refresh token (use with -r or NETATMO_REFRESH_TOKEN):")
+	fmt.Println(token.RefreshToken)
+	return nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func runCheck(cred *credentials, args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	deviceID := fs.String("d", "", "device id (MAC address, required)")
+	moduleID := fs.String("m", "", "module id (MAC address), defaults to the device id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *deviceID == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *moduleID == "" {
+		moduleID = deviceID
+	}
+	report, err := mustClient(cred).CheckConsistency(*deviceID, *moduleID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Dashboard time: %s\n", formatTimestamp(report.DashboardTime))
+	fmt.Printf("Measure time:   %s (skew: %s)\n", formatTimestamp(report.MeasureTime), report.Skew)
+	if report.Consistent() {
+		fmt.Println("No discrepancy")
+		return nil
+	}
+	for _, d := range report.Discrepancies {
+		fmt.Printf("[%s] %s\n", d.Kind, d.Message)
+	}
+	return nil
+}
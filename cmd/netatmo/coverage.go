@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mikan/netatmo-weather-go"
+)
+
+func runCoverage(_ *credentials, args []string) error {
+	fs := flag.NewFlagSet("coverage", flag.ExitOnError)
+	storePath := fs.String("store", defaultStorePath, "local store file")
+	deviceID := fs.String("d", "", "device id (MAC address, required)")
+	moduleID := fs.String("m", "", "module id (MAC address), defaults to the device id")
+	days := fs.Int("days", 7, "how many days ago")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *deviceID == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *moduleID == "" {
+		moduleID = deviceID
+	}
+	ctx := context.Background()
+	store, err := netatmo.OpenFileStore(ctx, *storePath)
+	if err != nil {
+		return err
+	}
+	end := time.Now()
+	filter := netatmo.MeasureFilter{DeviceID: *deviceID, ModuleID: *moduleID, Begin: end.AddDate(0, 0, -*days).Unix(),
+		End: end.Unix()}
+	report, err := netatmo.StoreCoverage(ctx, store, filter, netatmo.DefaultSampleInterval)
+	if err != nil {
+		return err
+	}
+	tw := new(tabwriter.Writer).Init(os.Stdout, 0, 8, 1, '\t', 0)
+	must(fmt.Fprintf(tw, "Samples:\t%d of %d (%.1f %%)\n", report.Actual, report.Expected, report.Percent))
+	for _, m := range report.Metrics {
+		must(fmt.Fprintf(tw, "\t%s:\t%d (%.1f %%)\n", m.Metric, m.Actual, m.Percent))
+	}
+	must(fmt.Fprintf(tw, "Gaps:\t%d\n", len(report.Gaps)))
+	for _, g := range report.Gaps {
+		must(fmt.Fprintf(tw, "\t%s - %s\t%d missing\n", formatTimestamp(g.Begin), formatTimestamp(g.End), g.Missing))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/mikan/netatmo-weather-go"
+)
+
+// Syslog priorities of daemon log lines.
+const (
+	priorityError   = 3
+	priorityWarning = 4
+	priorityInfo    = 6
+)
+
+// daemonLogger writes log lines to stderr with key=value fields. Under systemd (JOURNAL_STREAM set) lines are
+// prefixed with the syslog priority parsed by journald (ex. "<3>"), otherwise with the time.
+type daemonLogger struct {
+	journal bool
+}
+
+func (l daemonLogger) printf(priority int, format string, args ...interface{}) {
+	prefix := time.Now().Format("2006-01-02T15:04:05Z07:00 ")
+	if l.journal {
+		prefix = fmt.Sprintf("<%d>", priority)
+	}
+	fmt.Fprintf(os.Stderr, prefix+format+"\n", args...)
+}
+
+// daemonConfig defines settings of the daemon command. Settings of the -config file override flags and are read
+// again on SIGHUP.
+type daemonConfig struct {
+	Interval time.Duration
+	Lookback time.Duration
+	Store    string
+}
+
+// load overrides the settings with the JSON file (ex. {"interval": "5m", "lookback": "48h", "store": "x.ndjson.gz"}).
+func (c daemonConfig) load(path string) (daemonConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	var file struct {
+		Interval string `json:"interval"`
+		Lookback string `json:"lookback"`
+		Store    string `json:"store"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, d := range []struct {
+		value string
+		dest  *time.Duration
+	}{{file.Interval, &c.Interval}, {file.Lookback, &c.Lookback}} {
+		if d.value == "" {
+			continue
+		}
+		if *d.dest, err = time.ParseDuration(d.value); err != nil {
+			return c, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if file.Store != "" {
+		c.Store = file.Store
+	}
+	if c.Interval <= 0 {
+		return c, fmt.Errorf("%s: interval must be positive", path)
+	}
+	return c, nil
+}
+
+func runDaemon(cred *credentials, args []string) error {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	cfg := daemonConfig{}
+	fs.DurationVar(&cfg.Interval, "interval", envDuration("NETATMO_INTERVAL", 10*time.Minute), "sync interval")
+	fs.DurationVar(&cfg.Lookback, "lookback", envDuration("NETATMO_LOOKBACK", 24*time.Hour),
+		"how far back the first sync of a module reaches")
+	fs.StringVar(&cfg.Store, "store", envOr("NETATMO_STORE", defaultStorePath), "local store file")
+	configPath := fs.String("config", envOr("NETATMO_CONFIG", ""), "JSON file overriding the flags, read again on SIGHUP")
+	execSink := fs.String("exec", envOr("NETATMO_EXEC", ""),
+		"program (with arguments) receiving new measures as NDJSON on its standard input")
+	listen := fs.String("listen", envOr("NETATMO_LISTEN", ""), "address serving /healthz and /readyz (ex. :8080)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	log := daemonLogger{journal: os.Getenv("JOURNAL_STREAM") != ""}
+	if *configPath != "" {
+		var err error
+		if cfg, err = cfg.load(*configPath); err != nil {
+			return err
+		}
+	}
+	client := mustClient(cred)
+	defer client.Close()
+	ctx, stop := signal.NotifyContext(commandContext(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	store, err := netatmo.OpenFileStore(ctx, cfg.Store)
+	if err != nil {
+		return err
+	}
+	var sink netatmo.Sink
+	if args := strings.Fields(*execSink); len(args) > 0 {
+		s := &netatmo.ExecSink{Command: args[0], Args: args[1:]}
+		defer func() {
+			if err := s.Close(); err != nil {
+				log.printf(priorityWarning, "exec sink exited error=%q", err)
+			}
+		}()
+		sink = s
+	}
+	health := &netatmo.HealthChecker{Client: client}
+	thresholds := func() { health.SetThresholds(2*cfg.Interval+time.Minute, 3*cfg.Interval+time.Minute) }
+	thresholds()
+	health.Start()
+	if *listen != "" {
+		server := &http.Server{Addr: *listen, Handler: health.Handler(), ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.printf(priorityError, "health server failed error=%q", err)
+				stop()
+			}
+		}()
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+	}
+	// The watchdog stops pinging when the loop does not come back before the deadline (Unix time).
+	var deadline atomic.Int64
+	extend := func() { deadline.Store(time.Now().Add(2*cfg.Interval + time.Minute).Unix()) }
+	extend()
+	go func() {
+		healthy := func() bool { return time.Now().Unix() < deadline.Load() }
+		if err := netatmo.SystemdWatchdog(ctx, healthy); err != nil && err != context.Canceled {
+			log.printf(priorityWarning, "watchdog stopped error=%q", err)
+		}
+	}()
+	notify := func(state string) {
+		if _, err := netatmo.SystemdNotify(state); err != nil {
+			log.printf(priorityWarning, "sd_notify failed error=%q", err)
+		}
+	}
+	log.printf(priorityInfo, "daemon started interval=%s store=%s", cfg.Interval, cfg.Store)
+	notify("READY=1")
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			notify("STOPPING=1")
+			log.printf(priorityInfo, "daemon stopped")
+			return nil
+		case <-hup:
+			notify("RELOADING=1")
+			if *configPath == "" {
+				log.printf(priorityInfo, "reload requested without -config, syncing now")
+			} else if next, err := cfg.load(*configPath); err != nil {
+				log.printf(priorityError, "reload failed error=%q", err)
+			} else {
+				if next.Store != cfg.Store {
+					if s, err := netatmo.OpenFileStore(ctx, next.Store); err != nil {
+						log.printf(priorityError, "reload failed error=%q", err)
+						next.Store = cfg.Store
+					} else {
+						store = s
+					}
+				}
+				cfg = next
+				extend()
+				thresholds()
+				log.printf(priorityInfo, "config reloaded interval=%s lookback=%s store=%s", cfg.Interval,
+					cfg.Lookback, cfg.Store)
+			}
+			notify("READY=1")
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(0)
+		case <-timer.C:
+			extend()
+			start := time.Now()
+			syncer := &netatmo.Syncer{Sources: []netatmo.Source{{Client: client}}, Store: store, Lookback: cfg.Lookback,
+				Sink: sink}
+			result, err := syncer.Sync(ctx)
+			if ctx.Err() != nil {
+				continue // Terminating, the canceled sync is not a failure
+			}
+			health.Observe(err)
+			if err != nil {
+				log.printf(priorityError, "sync failed error=%q", err)
+				notify("STATUS=sync failed: " + err.Error())
+			} else {
+				log.printf(priorityInfo, "sync ok devices=%d measures=%d duration=%s", result.Devices,
+					result.Measures, time.Since(start).Round(time.Millisecond))
+				notify(fmt.Sprintf("STATUS=synced %d measures at %s", result.Measures, start.Format(time.Kitchen)))
+			}
+			extend()
+			timer.Reset(cfg.Interval)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"os"
+
+	"github.com/mikan/netatmo-weather-go"
+)
+
+// locale defines output locale set by the -locale flag or the environment.
+var locale = netatmo.DefaultLocale
+
+// labels defines translations of the CLI labels keyed by the English label.
+var labels = map[string]netatmo.Catalog{
+	"fr": {
+		"User information":       "Informations utilisateur",
+		"Mail":                   "E-mail",
+		"Language":               "Langue",
+		"Display locale":         "Paramètres régionaux",
+		"Country":                "Pays",
+		"Unit":                   "Unité",
+		"Wind unit":              "Unité de vent",
+		"Pressure unit":          "Unité de pression",
+		"Feel like algorithm":    "Algorithme de température ressentie",
+		"Device %d of %d":        "Appareil %d sur %d",
+		"Device ID":              "ID de l'appareil",
+		"Module name":            "Nom du module",
+		"Station name":           "Nom de la station",
+		"Type":                   "Type",
+		"Data types":             "Types de données",
+		"Cipher ID":              "ID chiffré",
+		"Firmware":               "Micrologiciel",
+		"Wi-Fi status":           "État Wi-Fi",
+		"Reachable":              "Joignable",
+		"CO2 calibrating":        "Calibrage CO2",
+		"City":                   "Ville",
+		"Time zone":              "Fuseau horaire",
+		"Altitude":               "Altitude",
+		"Location":               "Position",
+		"Setup time":             "Date d'installation",
+		"Last setup time":        "Dernière installation",
+		"Last upgrade time":      "Dernière mise à jour",
+		"Last status store time": "Dernier enregistrement d'état",
+		"Module %d of %d":        "Module %d sur %d",
+		"Module ID":              "ID du module",
+		"RF status":              "État RF",
+		"Battery":                "Batterie",
+		"Last message time":      "Dernier message",
+		"Last seen time":         "Dernière activité",
+		"Dashboard data":         "Données actuelles",
+		"(no data)":              "(aucune donnée)",
+		"Time (UTC)":             "Heure (UTC)",
+		"Temperature":            "Température",
+		"Minimum temperature":    "Température minimale",
+		"Maximum temperature":    "Température maximale",
+		"Humidity":               "Humidité",
+		"Noise":                  "Bruit",
+		"Pressure":               "Pression",
+		"Absolute pressure":      "Pression absolue",
+		"Rain":                   "Pluie",
+		"Rain per hour":          "Pluie sur une heure",
+		"Rain per day":           "Pluie sur la journée",
+		"Wind":                   "Vent",
+		"Gust":                   "Rafale",
+		"trend":                  "tendance",
+		"at":                     "à",
+		"angle":                  "angle",
+		"Timestamp":              "Horodatage",
+		"No Data":                "Aucune donnée",
+		"every":                  "toutes les",
+		"to quit":                "pour quitter",
+		"to refresh":             "pour actualiser",
+		"signal":                 "signal",
+		"battery":                "batterie",
+		"unreachable":            "injoignable",
+	},
+	"de": {
+		"User information":       "Benutzerinformationen",
+		"Mail":                   "E-Mail",
+		"Language":               "Sprache",
+		"Display locale":         "Regionaleinstellung",
+		"Country":                "Land",
+		"Unit":                   "Einheit",
+		"Wind unit":              "Windeinheit",
+		"Pressure unit":          "Druckeinheit",
+		"Feel like algorithm":    "Algorithmus der gefühlten Temperatur",
+		"Device %d of %d":        "Gerät %d von %d",
+		"Device ID":              "Geräte-ID",
+		"Module name":            "Modulname",
+		"Station name":           "Stationsname",
+		"Type":                   "Typ",
+		"Data types":             "Datentypen",
+		"Cipher ID":              "Cipher-ID",
+		"Firmware":               "Firmware",
+		"Wi-Fi status":           "WLAN-Status",
+		"Reachable":              "Erreichbar",
+		"CO2 calibrating":        "CO2-Kalibrierung",
+		"City":                   "Stadt",
+		"Time zone":              "Zeitzone",
+		"Altitude":               "Höhe",
+		"Location":               "Standort",
+		"Setup time":             "Einrichtungszeit",
+		"Last setup time":        "Letzte Einrichtung",
+		"Last upgrade time":      "Letztes Update",
+		"Last status store time": "Letzte Statusspeicherung",
+		"Module %d of %d":        "Modul %d von %d",
+		"Module ID":              "Modul-ID",
+		"RF status":              "Funkstatus",
+		"Battery":                "Batterie",
+		"Last message time":      "Letzte Nachricht",
+		"Last seen time":         "Zuletzt gesehen",
+		"Dashboard data":         "Aktuelle Daten",
+		"(no data)":              "(keine Daten)",
+		"Time (UTC)":             "Zeit (UTC)",
+		"Temperature":            "Temperatur",
+		"Minimum temperature":    "Tiefsttemperatur",
+		"Maximum temperature":    "Höchsttemperatur",
+		"Humidity":               "Luftfeuchtigkeit",
+		"Noise":                  "Lärm",
+		"Pressure":               "Luftdruck",
+		"Absolute pressure":      "Absoluter Luftdruck",
+		"Rain":                   "Regen",
+		"Rain per hour":          "Regen pro Stunde",
+		"Rain per day":           "Regen pro Tag",
+		"Wind":                   "Wind",
+		"Gust":                   "Böe",
+		"trend":                  "Tendenz",
+		"at":                     "um",
+		"angle":                  "Winkel",
+		"Timestamp":              "Zeitstempel",
+		"No Data":                "Keine Daten",
+		"every":                  "alle",
+		"to quit":                "zum Beenden",
+		"to refresh":             "zum Aktualisieren",
+		"signal":                 "Signal",
+		"battery":                "Batterie",
+		"unreachable":            "nicht erreichbar",
+	},
+	"ja": {
+		"User information":       "ユーザー情報",
+		"Mail":                   "メール",
+		"Language":               "言語",
+		"Display locale":         "表示ロケール",
+		"Country":                "国",
+		"Unit":                   "単位",
+		"Wind unit":              "風速の単位",
+		"Pressure unit":          "気圧の単位",
+		"Feel like algorithm":    "体感温度の算出方法",
+		"Device %d of %d":        "デバイス %d / %d",
+		"Device ID":              "デバイス ID",
+		"Module name":            "モジュール名",
+		"Station name":           "ステーション名",
+		"Type":                   "種類",
+		"Data types":             "データ種別",
+		"Cipher ID":              "暗号化 ID",
+		"Firmware":               "ファームウェア",
+		"Wi-Fi status":           "Wi-Fi 状態",
+		"Reachable":              "到達可能",
+		"CO2 calibrating":        "CO2 校正中",
+		"City":                   "市区町村",
+		"Time zone":              "タイムゾーン",
+		"Altitude":               "標高",
+		"Location":               "位置",
+		"Setup time":             "設置日時",
+		"Last setup time":        "最終設置日時",
+		"Last upgrade time":      "最終更新日時",
+		"Last status store time": "最終状態保存日時",
+		"Module %d of %d":        "モジュール %d / %d",
+		"Module ID":              "モジュール ID",
+		"RF status":              "無線状態",
+		"Battery":                "バッテリー",
+		"Last message time":      "最終メッセージ日時",
+		"Last seen time":         "最終確認日時",
+		"Dashboard data":         "現在のデータ",
+		"(no data)":              "(データなし)",
+		"Time (UTC)":             "時刻 (UTC)",
+		"Temperature":            "気温",
+		"Minimum temperature":    "最低気温",
+		"Maximum temperature":    "最高気温",
+		"Humidity":               "湿度",
+		"Noise":                  "騒音",
+		"Pressure":               "気圧",
+		"Absolute pressure":      "現地気圧",
+		"Rain":                   "雨量",
+		"Rain per hour":          "1時間雨量",
+		"Rain per day":           "日雨量",
+		"Wind":                   "風",
+		"Gust":                   "突風",
+		"trend":                  "傾向",
+		"at":                     "時刻",
+		"angle":                  "角度",
+		"Timestamp":              "日時",
+		"No Data":                "データなし",
+		"every":                  "間隔",
+		"to quit":                "で終了",
+		"to refresh":             "で更新",
+		"signal":                 "電波",
+		"battery":                "電池",
+		"unreachable":            "接続なし",
+	},
+}
+
+func init() {
+	for lang, catalog := range labels {
+		netatmo.RegisterCatalog(lang, catalog)
+	}
+}
+
+// environmentLocale returns locale of messages from the environment (ex. LANG=ja_JP.UTF-8).
+func environmentLocale() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(key); v != "" && v != "C" && v != "POSIX" {
+			return v
+		}
+	}
+	return netatmo.DefaultLocale
+}
+
+// tr translates the English label into the output locale.
+func tr(label string, args ...interface{}) string {
+	return netatmo.Translate(locale, label, args...)
+}
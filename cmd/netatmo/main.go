@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mikan/netatmo-weather-go"
+)
+
+// credentials holds the global authentication flags.
+type credentials struct {
+	clientID     string
+	clientSecret string
+	username     string
+	password     string
+	refreshToken string
+	endpoint     netatmo.Endpoint
+}
+
+// command defines a subcommand. Commands receive arguments after the command name.
+type command struct {
+	description string
+	run         func(cred *credentials, args []string) error
+}
+
+var commands = map[string]command{
+	"sync":     {"fetch stations and new measures into the local store", runSync},
+	"coverage": {"report missing samples of a module in the local store", runCoverage},
+	"check":    {"compare dashboard data with the newest measure of a module", runCheck},
+	"backup":   {"write the local store into an archive (full or incremental)", runBackup},
+	"restore":  {"import archives into the local store", runRestore},
+	"query":    {"print stored series of a period at a fitting resolution", runQuery},
+	"report":   {"write an HTML report of the local store for a period", runReport},
+	"watch":    {"poll stations and print current values with temperature sparklines", runWatch},
+	"tui":      {"live dashboard of all modules in the terminal", runTUI},
+	"map":      {"public stations of a region as a table or GeoJSON", runMap},
+	"daemon":   {"sync periodically as a long-lived (systemd) service", runDaemon},
+	"run":      {"run a collection pipeline of a YAML configuration", runPipeline},
+	"auth":     {"obtain a refresh token with the authorization code flow", runAuth},
+}
+
+func main() {
+	clientID := flag.String("c", envOr("NETATMO_CLIENT_ID", ""), "netatmo client id")
+	clientSecret := flag.String("s", envOr("NETATMO_CLIENT_SECRET", ""), "netatmo client secret")
+	username := flag.String("u", envOr("NETATMO_USERNAME", ""), "netatmo user name")
+	password := flag.String("p", envOr("NETATMO_PASSWORD", ""), "netatmo password")
+	refreshToken := flag.String("r", envOr("NETATMO_REFRESH_TOKEN", ""), "netatmo refresh token, used instead of -u and -p")
+	deviceID := flag.String("d", "", "device id (MAC address)")
+	moduleID := flag.String("m", "", "module id (MAC address)")
+	minutes := flag.Int("a", -1, "how many minutes ago")
+	endpoint := flag.String("endpoint", envOr("NETATMO_ENDPOINT", "default"), "API endpoint profile (default, legacy) or base URL of a custom host")
+	secrets := flag.String("secrets", envOr("NETATMO_SECRETS", ""), "load missing credentials from vault:<mount>/<path> or aws:<secret id>")
+	flag.BoolVar(&dryRun, "dry-run", false, "describe what would be written to stores instead of writing")
+	flag.BoolVar(&markdown, "markdown", false, "print stations and measures as Markdown tables")
+	flag.BoolVar(&debug, "debug", false, "print API and token requests and responses on stderr, credentials masked")
+	flag.StringVar(&locale, "locale", environmentLocale(), "output locale (ex. fr, ja_JP), defaults to LC_ALL, LC_MESSAGES or LANG")
+	flag.Usage = usage
+	flag.Parse()
+	cred := &credentials{clientID: *clientID, clientSecret: *clientSecret, username: *username, password: *password,
+		refreshToken: *refreshToken}
+	var err error
+	if cred.endpoint, err = netatmo.LookupEndpoint(*endpoint); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := loadSecrets(*secrets, cred); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if flag.NArg() > 0 {
+		cmd, ok := commands[flag.Arg(0)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown command: %s\n", flag.Arg(0))
+			usage()
+			os.Exit(2)
+		}
+		if err := cmd.run(cred, flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	client := mustClient(cred)
+	if len(*deviceID) == 0 {
+		stations(client)
+		return
+	}
+	if len(*moduleID) == 0 {
+		moduleID = deviceID
+	}
+	if *minutes > 0 {
+		measureRange(client, *deviceID, *moduleID, *minutes)
+	} else {
+		measureNewest(client, *deviceID, *moduleID)
+	}
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [flags] [command [command flags]]\n\nFlags:\n", os.Args[0])
+	flag.PrintDefaults()
+	fmt.Fprintln(out, "\nCommands (without command, prints stations or measures):")
+	for _, name := range sortedCommandNames() {
+		fmt.Fprintf(out, "  %-10s%s\n", name, commands[name].description)
+	}
+}
+
+// mustClient creates client from the credentials (refresh token, or user name and password), or exits with usage if
+// any of them is missing.
+func mustClient(cred *credentials) *netatmo.Client {
+	if cred.clientID == "" || cred.clientSecret == "" ||
+		(cred.refreshToken == "" && (cred.username == "" || cred.password == "")) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	opts := []netatmo.Option{netatmo.WithEndpoint(cred.endpoint)}
+	if debug {
+		opts = append(opts, netatmo.WithDebug(os.Stderr))
+	}
+	var client *netatmo.Client
+	var err error
+	if cred.refreshToken != "" {
+		client, err = netatmo.NewClientWithRefreshToken(context.Background(), cred.clientID, cred.clientSecret,
+			cred.refreshToken, opts...)
+	} else {
+		client, err = netatmo.NewClient(context.Background(), cred.clientID, cred.clientSecret, cred.username,
+			cred.password, opts...)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// envOr returns the environment variable, or the default if unset. Flags take defaults from NETATMO_* variables,
+// the usual way to configure containers.
+func envOr(name, def string) string {
+	if v, ok := os.LookupEnv(name); ok {
+		return v
+	}
+	return def
+}
+
+// envDuration returns the environment variable as duration, or the default if unset or invalid.
+func envDuration(name string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(name)); err == nil {
+		return d
+	}
+	return def
+}
+
+// debug holds the -debug flag.
+var debug bool
+
+// dryRun holds the -dry-run flag.
+var dryRun bool
+
+// commandContext returns context of commands, describing writes on stderr instead of writing with -dry-run.
+func commandContext() context.Context {
+	if dryRun {
+		return netatmo.ContextWithDryRun(context.Background(), os.Stderr)
+	}
+	return context.Background()
+}
+
+// markdown holds the -markdown flag.
+var markdown bool
+
+// writeMeasures prints the measures as a table, or as a Markdown table with -markdown.
+func writeMeasures(values []netatmo.Measure, w io.Writer) error {
+	if markdown {
+		return netatmo.WriteMarkdownMeasures(w, values, nil, netatmo.FormatOptions{Locale: locale})
+	}
+	if err := printMeasures(values, w); err != nil {
+		return err
+	}
+	if len(values) > 1 {
+		printSparklines(values, w)
+	}
+	return nil
+}
+
+func stations(client *netatmo.Client) {
+	devices, user, err := client.GetStationsData()
+	if err != nil {
+		panic(err)
+	}
+	if markdown {
+		err = netatmo.WriteMarkdownStations(os.Stdout, devices, netatmo.FormatOptionsOf(&user.Administrative))
+	} else {
+		err = printStationsData(devices, *user, os.Stdout)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+func measureRange(client *netatmo.Client, device, module string, minutes int) {
+	end := time.Now().UTC()
+	begin := end.Add(-time.Duration(minutes) * time.Minute)
+	values, err := client.GetMeasureByTimeRange(device, module, begin.Unix(), end.Unix())
+	if err != nil {
+		panic(err)
+	}
+	if err := writeMeasures(values, os.Stdout); err != nil {
+		panic(err)
+	}
+}
+
+func measureNewest(client *netatmo.Client, device, module string) {
+	value, err := client.GetMeasureByNewest(device, module)
+	if err != nil {
+		panic(err)
+	}
+	if value != nil {
+		if err := writeMeasures([]netatmo.Measure{*value}, os.Stdout); err != nil {
+			panic(err)
+		}
+	} else {
+		fmt.Println(tr("No Data"))
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mikan/netatmo-weather-go"
+)
+
+// mapMetric defines a metric of public stations shown by the map command.
+type mapMetric struct {
+	required string // Required data of getpublicdata
+	unit     string
+	value    func(s *netatmo.PublicStation) *float64
+}
+
+var mapMetrics = map[string]mapMetric{
+	"temperature": {"temperature", "°C", func(s *netatmo.PublicStation) *float64 { return s.Temperature }},
+	"humidity":    {"humidity", "%", func(s *netatmo.PublicStation) *float64 { return intValue(s.Humidity) }},
+	"pressure":    {"pressure", "hPa", func(s *netatmo.PublicStation) *float64 { return s.Pressure }},
+	"rain":        {"rain", "mm/h", func(s *netatmo.PublicStation) *float64 { return s.Rain60Min }},
+	"wind":        {"wind", "km/h", func(s *netatmo.PublicStation) *float64 { return intValue(s.WindStrength) }},
+	"gust":        {"wind", "km/h", func(s *netatmo.PublicStation) *float64 { return intValue(s.GustStrength) }},
+}
+
+func runMap(cred *credentials, args []string) error {
+	fs := flag.NewFlagSet("map", flag.ExitOnError)
+	bbox := fs.String("bbox", "", "region as south-west and north-east corners: lat_sw,lon_sw,lat_ne,lon_ne")
+	near := fs.String("near", "", "region around a point: lat,lon (with -radius)")
+	radius := fs.Float64("radius", 10, "radius in km around -near")
+	metricName := fs.String("metric", "temperature", "temperature, humidity, pressure, rain, wind or gust")
+	geoJSON := fs.Bool("geojson", false, "print GeoJSON of the stations instead of a table")
+	n := fs.Int("n", 20, "maximum number of stations in the table, 0 for all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	metric, ok := mapMetrics[*metricName]
+	if !ok {
+		return fmt.Errorf("unknown metric: %s", *metricName)
+	}
+	var box netatmo.BoundingBox
+	var centerLat, centerLon float64
+	switch {
+	case *bbox != "":
+		v, err := parseFloats(*bbox, 4)
+		if err != nil {
+			return fmt.Errorf("bbox: %w", err)
+		}
+		box = netatmo.BoundingBox{SouthWestLat: v[0], SouthWestLon: v[1], NorthEastLat: v[2], NorthEastLon: v[3]}
+		centerLat, centerLon = (v[0]+v[2])/2, (v[1]+v[3])/2
+	case *near != "":
+		v, err := parseFloats(*near, 2)
+		if err != nil {
+			return fmt.Errorf("near: %w", err)
+		}
+		box = netatmo.BoundingBoxAround(v[0], v[1], *radius)
+		centerLat, centerLon = v[0], v[1]
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+	stations, err := mustClient(cred).GetPublicData(box, metric.required)
+	if err != nil {
+		return err
+	}
+	if *geoJSON {
+		data, err := netatmo.MarshalGeoJSON(nil, stations)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Println(string(data))
+		return err
+	}
+	var values []float64
+	var rows []netatmo.PublicStation
+	for _, s := range stations {
+		if v := metric.value(&s); v != nil {
+			s.Distance = netatmo.Distance(centerLat, centerLon, s.Latitude, s.Longitude)
+			rows = append(rows, s)
+			values = append(values, *v)
+		}
+	}
+	if len(rows) == 0 {
+		fmt.Println(tr("No Data"))
+		return nil
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Distance < rows[j].Distance })
+	sort.Float64s(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	tw := new(tabwriter.Writer).Init(os.Stdout, 0, 8, 1, '\t', 0)
+	must(fmt.Fprintf(tw, "Stations:\t%d\n", len(values)))
+	must(fmt.Fprintf(tw, "Min / median / mean / max:\t%.1f / %.1f / %.1f / %.1f %s\n", values[0],
+		values[len(values)/2], sum/float64(len(values)), values[len(values)-1], metric.unit))
+	must(fmt.Fprintln(tw))
+	must(fmt.Fprintf(tw, "Distance\tCity\tLocation\t%s\tTime\n", *metricName))
+	for i, s := range rows {
+		if *n > 0 && i == *n {
+			break
+		}
+		must(fmt.Fprintf(tw, "%.1f km\t%s\t%.4f, %.4f\t%.1f %s\t%s\n", s.Distance, s.City, s.Latitude, s.Longitude,
+			*metric.value(&s), metric.unit, formatTimestamp(s.Timestamp)))
+	}
+	return tw.Flush()
+}
+
+// parseFloats parses n comma separated numbers.
+func parseFloats(s string, n int) ([]float64, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != n {
+		return nil, errors.New("expected " + strconv.Itoa(n) + " comma separated numbers: " + s)
+	}
+	values := make([]float64, n)
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func intValue(v *int) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mikan/netatmo-weather-go"
+)
+
+func printStationsData(devices []netatmo.Device, user netatmo.User, w io.Writer) error {
+	tw := new(tabwriter.Writer).Init(w, 0, 8, 1, '\t', 0)
+	must(fmt.Fprintf(tw, "%s:\n", tr("User information")))
+	must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Mail"), user.Mail))
+	must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Language"), user.Administrative.Language))
+	must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Display locale"), user.Administrative.DisplayLocale))
+	must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Country"), user.Administrative.Country))
+	must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Unit"), user.Administrative.DescribeUnitIn(locale)))
+	must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Wind unit"), user.Administrative.DescribeWindUnitIn(locale)))
+	must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Pressure unit"), user.Administrative.DescribePressureUnitIn(locale)))
+	must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Feel like algorithm"), user.Administrative.DescribeFeelLikeAlgorithmIn(locale)))
+	for i := 0; i < len(devices); i++ {
+		d := devices[i]
+		must(fmt.Fprintln(tw))
+		must(fmt.Fprintf(tw, "%s:\n", tr("Device %d of %d", i+1, len(devices))))
+		must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Device ID"), d.ID))
+		must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Module name"), d.ModuleName))
+		must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Station name"), d.StationName))
+		must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Type"), d.Type))
+		must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Data types"), strings.Join(d.DataTypes, ", ")))
+		must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Cipher ID"), d.CipherID))
+		must(fmt.Fprintf(tw, "\t%s:\t%d\n", tr("Firmware"), d.Firmware))
+		must(fmt.Fprintf(tw, "\t%s:\t%d\n", tr("Wi-Fi status"), d.WiFiStatus))
+		must(fmt.Fprintf(tw, "\t%s:\t%t\n", tr("Reachable"), d.Reachable))
+		must(fmt.Fprintf(tw, "\t%s:\t%t\n", tr("CO2 calibrating"), d.CO2Calibrating))
+		must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Country"), d.Place.Country))
+		must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("City"), d.Place.City))
+		must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Time zone"), d.Place.Timezone))
+		must(fmt.Fprintf(tw, "\t%s:\t%d\n", tr("Altitude"), d.Place.Altitude))
+		must(fmt.Fprintf(tw, "\t%s:\t%f, %f\n", tr("Location"), d.Place.Latitude(), d.Place.Longitude()))
+		must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Setup time"), formatTimestamp(d.SetupTime)))
+		must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Last setup time"), formatTimestamp(d.LastSetupTime)))
+		must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Last upgrade time"), formatTimestamp(d.LastUpgradeTime)))
+		must(fmt.Fprintf(tw, "\t%s:\t%s\n", tr("Last status store time"), formatTimestamp(d.LastStatusStoreTime)))
+		printDashboardData("", tw, d.DashboardData, d.DataTypes)
+		for j := 0; j < len(d.Modules); j++ {
+			m := d.Modules[j]
+			must(fmt.Fprintln(tw))
+			must(fmt.Fprintf(tw, "\t%s:\n", tr("Module %d of %d", j+1, len(d.Modules))))
+			must(fmt.Fprintf(tw, "\t\t%s:\t%s\n", tr("Module ID"), m.ID))
+			must(fmt.Fprintf(tw, "\t\t%s:\t%s\n", tr("Module name"), m.ModuleName))
+			must(fmt.Fprintf(tw, "\t\t%s:\t%s\n", tr("Data types"), strings.Join(m.DataTypes, ", ")))
+			must(fmt.Fprintf(tw, "\t\t%s:\t%d\n", tr("Firmware"), m.Firmware))
+			must(fmt.Fprintf(tw, "\t\t%s:\t%d\n", tr("RF status"), m.RFStatus))
+			must(fmt.Fprintf(tw, "\t\t%s:\t%d %% (vp: %d)\n", tr("Battery"), m.BatteryPercent, m.BatteryVP))
+			must(fmt.Fprintf(tw, "\t\t%s:\t%t\n", tr("Reachable"), m.Reachable))
+			must(fmt.Fprintf(tw, "\t\t%s:\t%s\n", tr("Last setup time"), formatTimestamp(m.LastSetupTime)))
+			must(fmt.Fprintf(tw, "\t\t%s:\t%s\n", tr("Last message time"), formatTimestamp(m.LastMessageTime)))
+			must(fmt.Fprintf(tw, "\t\t%s:\t%s\n", tr("Last seen time"), formatTimestamp(m.LastSeenTime)))
+			printDashboardData("\t", tw, m.DashboardData, m.DataTypes)
+		}
+	}
+	return tw.Flush()
+}
+
+func printMeasures(values []netatmo.Measure, w io.Writer) error {
+	tw := new(tabwriter.Writer).Init(w, 0, 8, 1, '\t', 0)
+	must(fmt.Fprintln(tw, tr("Timestamp")+"\t"+strings.Join(netatmo.TargetMeasurements, "\t")))
+	for _, m := range values {
+		must(fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			time.Unix(m.Timestamp, 0).Format("2006/01/02 15:04:05"),
+			f64OrNull(m.Temperature),
+			intOrNull(m.CO2),
+			intOrNull(m.Humidity),
+			f64OrNull(m.Pressure),
+			intOrNull(m.Noise),
+			intOrNull(m.WindStrength),
+			intOrNull(m.WindAngle),
+			intOrNull(m.GustStrength),
+			intOrNull(m.GustAngle),
+			f64OrNull(m.Rain)))
+	}
+	return tw.Flush()
+}
+
+// sparklineWidth defines maximum number of characters of sparklines.
+const sparklineWidth = 48
+
+// printSparklines prints a sparkline of each measurement having data, with its range.
+func printSparklines(values []netatmo.Measure, w io.Writer) {
+	tw := new(tabwriter.Writer).Init(w, 0, 8, 1, '\t', 0)
+	must(fmt.Fprintln(tw))
+	for _, name := range netatmo.TargetMeasurements {
+		points := netatmo.PointsOf(values, name)
+		if len(points) < 2 {
+			continue
+		}
+		low, high := points[0].Value, points[0].Value
+		for _, p := range points {
+			low, high = math.Min(low, p.Value), math.Max(high, p.Value)
+		}
+		must(fmt.Fprintf(tw, "%s\t%s\t%g - %g\n", name, netatmo.SparklineOf(points, sparklineWidth), low, high))
+	}
+	must(0, tw.Flush())
+}
+
+func printDashboardData(prefix string, w io.Writer, data *netatmo.DashboardData, types []string) {
+	if data == nil {
+		must(fmt.Fprintf(w, prefix+"\t%s:\t%s\n", tr("Dashboard data"), tr("(no data)")))
+		return
+	}
+	must(fmt.Fprintf(w, prefix+"\t%s:\n", tr("Dashboard data")))
+	must(fmt.Fprintf(w, prefix+"\t\t%s:\t%s\n", tr("Time (UTC)"), formatTimestamp(data.UTCTime)))
+	if sliceContains(types, "Temperature") {
+		must(fmt.Fprintf(w, prefix+"\t\t%s:\t%.1f °C (%s: %s)\n", tr("Temperature"), *data.Temperature, tr("trend"),
+			netatmo.DescribeTrendIn(locale, *data.TemperatureTrend)))
+		must(fmt.Fprintf(w, prefix+"\t\t%s:\t%.1f °C (%s %s)\n", tr("Minimum temperature"), *data.MinTemperature, tr("at"),
+			formatTimestamp(*data.MinTemperatureTime)))
+		must(fmt.Fprintf(w, prefix+"\t\t%s:\t%.1f °C (%s %s)\n", tr("Maximum temperature"), *data.MaxTemperature, tr("at"),
+			formatTimestamp(*data.MaxTemperatureTime)))
+	}
+	if sliceContains(types, "CO2") {
+		must(fmt.Fprintf(w, prefix+"\t\t%s:\t%d ppm\n", tr("CO2"), *data.CO2))
+	}
+	if sliceContains(types, "Humidity") {
+		must(fmt.Fprintf(w, prefix+"\t\t%s:\t%d %%\n", tr("Humidity"), *data.Humidity))
+	}
+	if sliceContains(types, "Noise") {
+		must(fmt.Fprintf(w, prefix+"\t\t%s:\t%d db\n", tr("Noise"), *data.Noise))
+	}
+	if sliceContains(types, "Pressure") {
+		must(fmt.Fprintf(w, prefix+"\t\t%s:\t%.1f mb (%s: %s)\n", tr("Pressure"), *data.Pressure, tr("trend"),
+			netatmo.DescribeTrendIn(locale, *data.PressureTrend)))
+		must(fmt.Fprintf(w, prefix+"\t\t%s:\t%.1f mb\n", tr("Absolute pressure"), *data.AbsolutePressure))
+	}
+	if sliceContains(types, "Rain") {
+		must(fmt.Fprintf(w, prefix+"\t\t%s:\t%.1f mm\n", tr("Rain"), *data.Rain))
+		must(fmt.Fprintf(w, prefix+"\t\t%s:\t%.1f mm\n", tr("Rain per hour"), *data.RainPerHour))
+		must(fmt.Fprintf(w, prefix+"\t\t%s:\t%.1f mm\n", tr("Rain per day"), *data.RainPerDay))
+	}
+	if sliceContains(types, "Wind") {
+		must(fmt.Fprintf(w, prefix+"\t\t%s:\t%d km/h (%s: %d °)\n", tr("Wind"), *data.WindStrength, tr("angle"),
+			*data.WindAngle))
+		must(fmt.Fprintf(w, prefix+"\t\t%s:\t%d km/h (%s: %d °)\n", tr("Gust"), *data.GustStrength, tr("angle"),
+			*data.GustAngle))
+	}
+}
+
+func must(_ int, err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func formatTimestamp(timestamp int64) string {
+	return time.Unix(timestamp, 0).Format("2006-01-02 15:04:05")
+}
+
+func sliceContains(slice []string, value string) bool {
+	for _, item := range slice {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+func f64OrNull(v *float64) string {
+	if v == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%v", *v)
+}
+
+func intOrNull(v *int) string {
+	if v == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%v", *v)
+}
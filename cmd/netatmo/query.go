@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mikan/netatmo-weather-go"
+)
+
+func runQuery(_ *credentials, args []string) error {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	storePath := fs.String("store", defaultStorePath, "local store file")
+	account := fs.String("account", "", "account namespace, defaults to all accounts")
+	deviceID := fs.String("d", "", "device id (MAC address), defaults to all devices")
+	moduleID := fs.String("m", "", "module id (MAC address), defaults to all modules")
+	metrics := fs.String("metrics", "", "comma separated measurements (ex. Temperature,Humidity), defaults to all")
+	days := fs.Int("days", 7, "how many days ago")
+	resolution := fs.String("res", "", "resolution: raw, 1hour or 1day, defaults to the finest fitting -points")
+	points := fs.Int("points", 500, "maximum number of points per series choosing the resolution, 0 for raw")
+	aggregation := fs.String("agg", "mean", "statistic of aggregated resolutions: mean, min, max, sum or count")
+	asJSON := fs.Bool("json", false, "print series as JSON instead of a table")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	end := time.Now()
+	q := netatmo.Query{Account: *account, DeviceID: *deviceID, ModuleID: *moduleID,
+		Begin: end.AddDate(0, 0, -*days).Unix(), End: end.Unix(), Resolution: netatmo.Resolution(*resolution),
+		MaxPoints: *points, Aggregation: netatmo.Aggregation(*aggregation)}
+	if *metrics != "" {
+		q.Metrics = strings.Split(*metrics, ",")
+	}
+	ctx := context.Background()
+	store, err := netatmo.OpenFileStore(ctx, *storePath)
+	if err != nil {
+		return err
+	}
+	series, err := netatmo.QueryStore(ctx, store, q)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(series)
+	}
+	if len(series) == 0 {
+		fmt.Println(tr("No Data"))
+		return nil
+	}
+	tw := new(tabwriter.Writer).Init(os.Stdout, 0, 8, 1, '\t', 0)
+	for _, s := range series {
+		must(fmt.Fprintf(tw, "%s/%s\t%s\t%s %s\n", s.DeviceID, s.ModuleID, s.Metric, s.Resolution, s.Aggregation))
+		for _, p := range s.Points {
+			must(fmt.Fprintf(tw, "\t%s\t%g\n", formatTimestamp(p.Timestamp), p.Value))
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mikan/netatmo-weather-go"
+)
+
+func runReport(_ *credentials, args []string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	storePath := fs.String("store", defaultStorePath, "local store file")
+	period := fs.String("period", "week", "reported period before now: day, week, month or year")
+	output := fs.String("o", "", "output HTML file, defaults to standard output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	end := time.Now()
+	var begin time.Time
+	switch *period {
+	case "day":
+		begin = end.AddDate(0, 0, -1)
+	case "week":
+		begin = end.AddDate(0, 0, -7)
+	case "month":
+		begin = end.AddDate(0, -1, 0)
+	case "year":
+		begin = end.AddDate(-1, 0, 0)
+	default:
+		return fmt.Errorf("unknown period: %s", *period)
+	}
+	ctx := context.Background()
+	store, err := netatmo.OpenFileStore(ctx, *storePath)
+	if err != nil {
+		return err
+	}
+	report, err := netatmo.BuildReport(ctx, store, "Weather report ("+*period+")", begin, end)
+	if err != nil {
+		return err
+	}
+	w := os.Stdout
+	if *output != "" {
+		if w, err = os.Create(*output); err != nil {
+			return err
+		}
+		defer w.Close()
+	}
+	return netatmo.WriteHTMLReport(w, report)
+}
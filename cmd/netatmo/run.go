@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/mikan/netatmo-weather-go"
+)
+
+func runPipeline(_ *credentials, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	configPath := fs.String("config", envOr("NETATMO_PIPELINE", "pipeline.yaml"), "YAML pipeline configuration")
+	once := fs.Bool("once", false, "collect once and exit")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	config, err := netatmo.LoadPipelineConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(commandContext(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	pipeline, err := netatmo.NewPipeline(ctx, *config)
+	if err != nil {
+		return err
+	}
+	defer pipeline.Close()
+	log := daemonLogger{journal: os.Getenv("JOURNAL_STREAM") != ""}
+	if *once {
+		n, err := pipeline.RunOnce(ctx)
+		log.printf(priorityInfo, "pipeline run measures=%d", n)
+		return err
+	}
+	log.printf(priorityInfo, "pipeline started config=%s interval=%s", *configPath, config.Interval)
+	err = pipeline.Run(ctx, func(err error) { log.printf(priorityError, "pipeline run failed error=%q", err) })
+	if err == context.Canceled {
+		log.printf(priorityInfo, "pipeline stopped")
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mikan/netatmo-weather-go"
+)
+
+// secretProvider parses the -secrets flag: vault:<mount>/<path> (address and token from VAULT_ADDR and
+// VAULT_TOKEN) or aws:<secret id> (region and credentials from AWS_REGION and AWS_ACCESS_KEY_ID etc).
+func secretProvider(spec string) (netatmo.SecretProvider, error) {
+	kind, location, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "vault":
+		mount, path, ok := strings.Cut(location, "/")
+		if !ok {
+			return nil, fmt.Errorf("vault secret must be <mount>/<path>: %s", location)
+		}
+		return &netatmo.VaultSecrets{Mount: mount, Path: path}, nil
+	case "aws":
+		return &netatmo.AWSSecrets{SecretID: location}, nil
+	}
+	return nil, fmt.Errorf("unknown secret provider: %s", spec)
+}
+
+// loadSecrets fills credentials not given by flags from the secret provider of the -secrets flag.
+func loadSecrets(spec string, cred *credentials) error {
+	if spec == "" {
+		return nil
+	}
+	provider, err := secretProvider(spec)
+	if err != nil {
+		return err
+	}
+	c := netatmo.Credentials{ClientID: cred.clientID, ClientSecret: cred.clientSecret, Username: cred.username,
+		Password: cred.password, RefreshToken: cred.refreshToken}
+	if err := netatmo.LoadCredentials(context.Background(), provider, &c); err != nil {
+		return err
+	}
+	cred.clientID, cred.clientSecret, cred.username, cred.password = c.ClientID, c.ClientSecret, c.Username,
+		c.Password
+	cred.refreshToken = c.RefreshToken
+	return nil
+}
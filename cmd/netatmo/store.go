@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/mikan/netatmo-weather-go"
+)
+
+const defaultStorePath = "netatmo.ndjson.gz"
+
+func sortedCommandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func runSync(cred *credentials, args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	storePath := fs.String("store", defaultStorePath, "local store file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx := commandContext()
+	store, err := netatmo.OpenFileStore(ctx, *storePath)
+	if err != nil {
+		return err
+	}
+	syncer := &netatmo.Syncer{Sources: []netatmo.Source{{Client: mustClient(cred)}}, Store: store}
+	result, err := syncer.Sync(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Synced %d devices and %d measures into %s\n", result.Devices, result.Measures, store.Path())
+	return nil
+}
+
+func runBackup(_ *credentials, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	storePath := fs.String("store", defaultStorePath, "local store file")
+	output := fs.String("o", "", "output archive file (required)")
+	incremental := fs.String("incremental", "", "previous backup archive; only data newer than it is written")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *output == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+	ctx := context.Background()
+	store, err := netatmo.OpenFileStore(ctx, *storePath)
+	if err != nil {
+		return err
+	}
+	var previous *netatmo.ArchiveManifest
+	if *incremental != "" {
+		if previous, err = readManifest(*incremental); err != nil {
+			return err
+		}
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	var manifest *netatmo.ArchiveManifest
+	if previous != nil {
+		manifest, err = netatmo.ExportIncremental(ctx, f, store, previous)
+	} else {
+		manifest, err = netatmo.Export(ctx, f, store, netatmo.MeasureFilter{})
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(*output)
+		return err
+	}
+	fmt.Printf("Wrote %d devices, %d measures and %d aggregates into %s\n", manifest.Devices, manifest.Measures,
+		manifest.Aggregates, *output)
+	return nil
+}
+
+func runRestore(_ *credentials, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	storePath := fs.String("store", defaultStorePath, "local store file")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: restore [-store file] <full archive> [incremental archives...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	ctx := commandContext()
+	store, err := netatmo.OpenFileStore(ctx, *storePath)
+	if err != nil {
+		return err
+	}
+	for _, path := range fs.Args() {
+		manifest, err := importFile(ctx, path, store)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		fmt.Printf("Restored %d devices, %d measures and %d aggregates from %s\n", manifest.Devices,
+			manifest.Measures, manifest.Aggregates, path)
+	}
+	return nil
+}
+
+func readManifest(path string) (*netatmo.ArchiveManifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	manifest, err := netatmo.ReadArchiveManifest(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return manifest, nil
+}
+
+func importFile(ctx context.Context, path string, store netatmo.Store) (*netatmo.ArchiveManifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return netatmo.Import(ctx, f, store)
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mikan/netatmo-weather-go"
+	"golang.org/x/term"
+)
+
+// ANSI escape sequences of the dashboard.
+const (
+	ansiAltScreen  = "\x1b[?1049h\x1b[?25l"
+	ansiMainScreen = "\x1b[?25h\x1b[?1049l"
+	ansiHome       = "\x1b[H\x1b[2J"
+	ansiRed        = "\x1b[31m"
+	ansiReset      = "\x1b[0m"
+)
+
+// tuiPanelWidth defines outer width of module panels.
+const tuiPanelWidth = 44
+
+func runTUI(cred *credentials, args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	interval := fs.Duration("interval", 5*time.Minute, "polling interval")
+	window := fs.Duration("window", 24*time.Hour, "period of the temperature sparklines")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	client := mustClient(cred)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	fmt.Print(ansiAltScreen)
+	defer fmt.Print(ansiMainScreen)
+	refresh := make(chan struct{}, 1)
+	if restore, err := readTUIKeys(stop, refresh); err == nil {
+		defer restore()
+	}
+	history := make(watchHistory)
+	seeded := false
+	var panels [][]string
+	poller := &netatmo.Poller{
+		Client: client,
+		OnData: func(devices []netatmo.Device, user *netatmo.User) {
+			since := time.Now().Add(-*window).Unix()
+			if !seeded {
+				seedWatchHistory(client, devices, history, since)
+				seeded = true
+			}
+			opts := netatmo.FormatOptionsOf(&user.Administrative)
+			opts.Locale = locale
+			panels = nil
+			for _, d := range devices {
+				history.add(d.ID, d.DashboardData, since)
+				panels = append(panels, tuiPanel(d.StationName+" / "+d.ModuleName, d.DashboardData, history[d.ID],
+					opts, "", signalBars(d.WiFiStatus, 86, 56), d.Reachable))
+				for _, m := range d.Modules {
+					history.add(m.ID, m.DashboardData, since)
+					panels = append(panels, tuiPanel(d.StationName+" / "+m.ModuleName, m.DashboardData,
+						history[m.ID], opts, batteryGauge(m.BatteryPercent), signalBars(m.RFStatus, 90, 60),
+						m.Reachable))
+				}
+			}
+		},
+	}
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		err := poller.PollContext(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		drawTUI(panels, err, *interval)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-refresh:
+			ticker.Reset(*interval)
+		}
+	}
+}
+
+// readTUIKeys switches the terminal to raw mode and reads keys in the background: r refreshes, q and Ctrl-C quit.
+// The returned function restores the terminal; an error means stdin is not a terminal and keys are not read.
+func readTUIKeys(quit func(), refresh chan<- struct{}) (func(), error) {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		key := make([]byte, 1)
+		for {
+			if _, err := os.Stdin.Read(key); err != nil {
+				return
+			}
+			switch key[0] {
+			case 'q', 'Q', 3: // Ctrl-C does not raise SIGINT in raw mode
+				quit()
+				return
+			case 'r', 'R':
+				select {
+				case refresh <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return func() { _ = term.Restore(fd, state) }, nil
+}
+
+// drawTUI redraws the screen with the panels of the last successful poll laid out in as many columns as the terminal
+// width allows, and the error of the last poll if it failed.
+func drawTUI(panels [][]string, lastErr error, interval time.Duration) {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width = tuiPanelWidth
+	}
+	columns := max(1, width/(tuiPanelWidth+1))
+	var b strings.Builder
+	b.WriteString(ansiHome)
+	fmt.Fprintf(&b, "netatmo  %s  (%s %s, r %s, q %s)\n\n", time.Now().Format("2006-01-02 15:04:05"), tr("every"),
+		interval, tr("to refresh"), tr("to quit"))
+	for i := 0; i < len(panels); i += columns {
+		row := panels[i:min(i+columns, len(panels))]
+		for line := range row[0] {
+			for _, panel := range row {
+				b.WriteString(panel[line])
+				b.WriteString(" ")
+			}
+			b.WriteString("\n")
+		}
+	}
+	if lastErr != nil {
+		fmt.Fprintf(&b, "\n%s%v%s\n", ansiRed, lastErr, ansiReset)
+	}
+	fmt.Print(strings.ReplaceAll(b.String(), "\n", "\r\n")) // Raw mode does not return the carriage
+}
+
+// tuiPanel renders a boxed panel of a module.
+func tuiPanel(name string, data *netatmo.DashboardData, points []netatmo.Point, opts netatmo.FormatOptions,
+	battery string, signal int, reachable bool) []string {
+	inner := tuiPanelWidth - 4
+	values := tr("(no data)")
+	trend := ""
+	if data != nil {
+		values = netatmo.FormatDashboardData(data, opts)
+		if data.TemperatureTrend != nil {
+			trend = map[string]string{"up": "↑", "down": "↓", "stable": "→"}[*data.TemperatureTrend]
+		}
+	}
+	status := fmt.Sprintf("%s %s", tr("signal"), strings.Repeat("▮", signal)+strings.Repeat("▯", 4-signal))
+	if battery != "" {
+		status = fmt.Sprintf("%s %s  %s", tr("battery"), battery, status)
+	}
+	if !reachable {
+		status = ansiRed + tr("unreachable") + ansiReset
+	}
+	lines := []string{
+		"┌─ " + fit(name, inner-1) + " " + strings.Repeat("─", max(0, inner-1-displayWidth(fit(name, inner-1)))) + "┐",
+	}
+	for _, text := range wrap(values, inner) {
+		lines = append(lines, "│ "+pad(text, inner)+" │")
+	}
+	for len(lines) < 3 {
+		lines = append(lines, "│ "+pad("", inner)+" │")
+	}
+	lines = append(lines,
+		"│ "+pad(netatmo.SparklineOf(points, inner-2)+" "+trend, inner)+" │",
+		"│ "+pad(status, inner)+" │",
+		"└"+strings.Repeat("─", inner+2)+"┘",
+	)
+	return lines
+}
+
+// batteryGauge renders battery percent as a 5 cells gauge with the percent.
+func batteryGauge(percent int) string {
+	cells := max(0, min(5, (percent+10)/20))
+	gauge := strings.Repeat("█", cells) + strings.Repeat("░", 5-cells) + fmt.Sprintf(" %d%%", percent)
+	if percent <= netatmo.LowBatteryPercent {
+		return ansiRed + gauge + ansiReset
+	}
+	return gauge
+}
+
+// signalBars converts a Wi-Fi or RF status (lower is stronger) to 0 to 4 bars between the worst and best values.
+func signalBars(status, worst, best int) int {
+	return max(0, min(4, (worst-status)*4/(worst-best)))
+}
+
+// wrap splits comma separated values into lines of at most the width, keeping only 2 lines.
+func wrap(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, part := range strings.Split(text, ", ") {
+		switch {
+		case line == "":
+			line = part
+		case displayWidth(line)+2+displayWidth(part) <= width:
+			line += ", " + part
+		default:
+			lines = append(lines, line)
+			line = part
+		}
+	}
+	lines = append(lines, line)
+	if len(lines) > 2 {
+		lines = lines[:2]
+	}
+	for i := range lines {
+		lines[i] = fit(lines[i], width)
+	}
+	return lines
+}
+
+// fit truncates the text to the width.
+func fit(text string, width int) string {
+	if displayWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:width-1]) + "…"
+}
+
+// pad pads the text with spaces to the width.
+func pad(text string, width int) string {
+	return text + strings.Repeat(" ", max(0, width-displayWidth(text)))
+}
+
+// displayWidth returns number of terminal cells of the text, ignoring ANSI color sequences.
+func displayWidth(text string) int {
+	for _, seq := range []string{ansiRed, ansiReset} {
+		text = strings.Replace(text, seq, "", -1)
+	}
+	return utf8.RuneCountInString(text)
+}
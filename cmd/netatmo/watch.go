@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mikan/netatmo-weather-go"
+)
+
+// watchHistory holds recent temperatures of modules keyed by module ID.
+type watchHistory map[string][]netatmo.Point
+
+// add appends the temperature of the dashboard data if newer than the latest point and drops points before since.
+func (h watchHistory) add(moduleID string, data *netatmo.DashboardData, since int64) {
+	points := h[moduleID]
+	if data != nil && data.Temperature != nil && (len(points) == 0 || points[len(points)-1].Timestamp < data.UTCTime) {
+		points = append(points, netatmo.Point{Timestamp: data.UTCTime, Value: *data.Temperature})
+	}
+	for len(points) > 0 && points[0].Timestamp < since {
+		points = points[1:]
+	}
+	h[moduleID] = points
+}
+
+func runWatch(cred *credentials, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	interval := fs.Duration("interval", 10*time.Minute, "polling interval")
+	window := fs.Duration("window", 24*time.Hour, "period of the temperature sparklines")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	client := mustClient(cred)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	history := make(watchHistory)
+	seeded := false
+	poller := &netatmo.Poller{
+		Client:   client,
+		Interval: *interval,
+		OnData: func(devices []netatmo.Device, user *netatmo.User) {
+			since := time.Now().Add(-*window).Unix()
+			if !seeded {
+				seedWatchHistory(client, devices, history, since)
+				seeded = true
+			}
+			opts := netatmo.FormatOptionsOf(&user.Administrative)
+			opts.Locale = locale
+			tw := new(tabwriter.Writer).Init(os.Stdout, 0, 8, 1, '\t', 0)
+			must(fmt.Fprintf(tw, "\n%s\n", time.Now().Format("2006-01-02 15:04:05")))
+			for _, d := range devices {
+				history.add(d.ID, d.DashboardData, since)
+				printWatchLine(tw, d.StationName+" / "+d.ModuleName, d.DashboardData, history[d.ID], opts)
+				for _, m := range d.Modules {
+					history.add(m.ID, m.DashboardData, since)
+					printWatchLine(tw, d.StationName+" / "+m.ModuleName, m.DashboardData, history[m.ID], opts)
+				}
+			}
+			must(0, tw.Flush())
+		},
+		OnError: func(err error) { fmt.Fprintln(os.Stderr, err) },
+	}
+	if err := poller.Run(ctx); err != context.Canceled {
+		return err
+	}
+	return nil
+}
+
+// seedWatchHistory fetches temperatures of the window of each module measuring temperature.
+func seedWatchHistory(client *netatmo.Client, devices []netatmo.Device, history watchHistory, since int64) {
+	seed := func(deviceID, moduleID string, types []string) {
+		if !sliceContains(types, "Temperature") {
+			return
+		}
+		measures, err := client.GetMeasureSince(deviceID, moduleID, since, netatmo.WithTypes("Temperature"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		history[moduleID] = netatmo.SeriesOf[netatmo.Temperature](measures)
+	}
+	for _, d := range devices {
+		seed(d.ID, d.ID, d.DataTypes)
+		for _, m := range d.Modules {
+			seed(d.ID, m.ID, m.DataTypes)
+		}
+	}
+}
+
+func printWatchLine(tw *tabwriter.Writer, name string, data *netatmo.DashboardData, points []netatmo.Point,
+	opts netatmo.FormatOptions) {
+	values := tr("(no data)")
+	if data != nil {
+		values = netatmo.FormatDashboardData(data, opts)
+	}
+	must(fmt.Fprintf(tw, "%s\t%s\t%s\n", name, netatmo.SparklineOf(points, sparklineWidth), values))
+}
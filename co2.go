@@ -0,0 +1,30 @@
+package netatmo
+
+// CO2CalibrationPolicy defines handling of CO2 values gathered while the device calibrates its CO2 sensor.
+type CO2CalibrationPolicy int
+
+// Supported policies.
+const (
+	CO2CalibrationKeep     CO2CalibrationPolicy = iota // Keep values as they are
+	CO2CalibrationMark                                 // Keep values and set Measure.CO2Calibrating
+	CO2CalibrationSuppress                             // Set CO2 to null and Measure.CO2Calibrating
+)
+
+// ApplyCO2Calibration applies the policy to measures of the device having CO2 values, if the device is calibrating.
+// The flag of station data reflects the current state, so pass measures fetched together with it (ex. an
+// incremental sync).
+func ApplyCO2Calibration(measures []Measure, device *Device, policy CO2CalibrationPolicy) {
+	if policy == CO2CalibrationKeep || !device.CO2Calibrating {
+		return
+	}
+	for i := range measures {
+		m := &measures[i]
+		if m.DeviceID != device.ID || m.CO2 == nil {
+			continue
+		}
+		m.CO2Calibrating = true
+		if policy == CO2CalibrationSuppress {
+			m.CO2 = nil
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package netatmo
+
+import "math"
+
+// ThermalSensation defines classification of temperature against a comfort band.
+type ThermalSensation string
+
+// Supported thermal sensations.
+const (
+	SensationCold        ThermalSensation = "cold"
+	SensationCool        ThermalSensation = "cool"
+	SensationComfortable ThermalSensation = "comfortable"
+	SensationWarm        ThermalSensation = "warm"
+	SensationHot         ThermalSensation = "hot"
+)
+
+// HumidityLevel defines classification of relative humidity against a comfort band.
+type HumidityLevel string
+
+// Supported humidity levels.
+const (
+	HumidityDry         HumidityLevel = "dry"
+	HumidityComfortable HumidityLevel = "comfortable"
+	HumidityHumid       HumidityLevel = "humid"
+)
+
+// ComfortBand defines comfortable indoor conditions.
+type ComfortBand struct {
+	MinTemperature float64 // °C
+	MaxTemperature float64 // °C
+	MinHumidity    float64 // %
+	MaxHumidity    float64 // %
+	Margin         float64 // °C outside the band still classified as cool or warm rather than cold or hot
+}
+
+// DefaultComfortBand defines commonly recommended indoor conditions for living rooms.
+var DefaultComfortBand = ComfortBand{MinTemperature: 20, MaxTemperature: 24, MinHumidity: 40, MaxHumidity: 60, Margin: 2}
+
+// Sensation classifies the temperature in °C.
+func (b ComfortBand) Sensation(temperature float64) ThermalSensation {
+	switch {
+	case temperature < b.MinTemperature-b.Margin:
+		return SensationCold
+	case temperature < b.MinTemperature:
+		return SensationCool
+	case temperature > b.MaxTemperature+b.Margin:
+		return SensationHot
+	case temperature > b.MaxTemperature:
+		return SensationWarm
+	default:
+		return SensationComfortable
+	}
+}
+
+// HumidityLevel classifies the relative humidity in %.
+func (b ComfortBand) HumidityLevel(humidity float64) HumidityLevel {
+	switch {
+	case humidity < b.MinHumidity:
+		return HumidityDry
+	case humidity > b.MaxHumidity:
+		return HumidityHumid
+	default:
+		return HumidityComfortable
+	}
+}
+
+// Comfort defines comfort classification of an indoor module.
+type Comfort struct {
+	DeviceID   string
+	ModuleID   string // Base station ID for the main room
+	ModuleName string
+	Sensation  ThermalSensation // Empty if temperature is unknown
+	Humidity   HumidityLevel    // Empty if humidity is unknown
+	Humidex    *float64         // Nullable, perceived temperature in °C
+}
+
+// Comfortable reports whether both temperature and humidity are within the band.
+func (c *Comfort) Comfortable() bool {
+	return c.Sensation == SensationComfortable && c.Humidity == HumidityComfortable
+}
+
+// Humidex computes perceived temperature in °C from temperature in °C and relative humidity in %, as defined by
+// Environment Canada.
+func Humidex(temperature, humidity float64) float64 {
+	dewPoint := DewPoint(temperature, humidity) + 273.15
+	vapourPressure := 6.11 * math.Exp(5417.7530*(1/273.16-1/dewPoint))
+	return temperature + 0.5555*(vapourPressure-10)
+}
+
+// IndoorComfort classifies each indoor module (base station and additional indoor modules) against the band using
+// dashboard data.
+func IndoorComfort(devices []Device, band ComfortBand) []Comfort {
+	var comforts []Comfort
+	for _, d := range devices {
+		comforts = append(comforts, comfortOf(d.ID, d.ID, d.ModuleName, d.DashboardData, band))
+		for _, m := range d.Modules {
+			if m.Type == TypeIndoor {
+				comforts = append(comforts, comfortOf(d.ID, m.ID, m.ModuleName, m.DashboardData, band))
+			}
+		}
+	}
+	return comforts
+}
+
+func comfortOf(deviceID, moduleID, name string, data *DashboardData, band ComfortBand) Comfort {
+	comfort := Comfort{DeviceID: deviceID, ModuleID: moduleID, ModuleName: name}
+	if data == nil {
+		return comfort
+	}
+	if data.Temperature != nil {
+		comfort.Sensation = band.Sensation(*data.Temperature)
+	}
+	if data.Humidity != nil {
+		comfort.Humidity = band.HumidityLevel(float64(*data.Humidity))
+	}
+	if data.Temperature != nil && data.Humidity != nil && *data.Humidity > 0 {
+		v := Humidex(*data.Temperature, float64(*data.Humidity))
+		comfort.Humidex = &v
+	}
+	return comfort
+}
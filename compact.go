@@ -0,0 +1,335 @@
+package netatmo
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+)
+
+// Resolution defines time resolution of stored data.
+type Resolution string
+
+// Supported resolutions.
+const (
+	ResolutionRaw    Resolution = "raw"
+	ResolutionHourly Resolution = "1hour"
+	ResolutionDaily  Resolution = "1day"
+)
+
+// Duration returns bucket length of the resolution, or 0 for raw data.
+func (r Resolution) Duration() time.Duration {
+	switch r {
+	case ResolutionHourly:
+		return time.Hour
+	case ResolutionDaily:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// Stats defines statistics of a measurement within an aggregation bucket.
+type Stats struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Sum   float64 `json:"sum"`
+}
+
+// Mean returns arithmetic mean of the values.
+func (s *Stats) Mean() float64 {
+	if s.Count == 0 {
+		return math.NaN()
+	}
+	return s.Sum / float64(s.Count)
+}
+
+// Add adds single value to the statistics.
+func (s *Stats) Add(v float64) {
+	if s.Count == 0 || v < s.Min {
+		s.Min = v
+	}
+	if s.Count == 0 || v > s.Max {
+		s.Max = v
+	}
+	s.Count++
+	s.Sum += v
+}
+
+// Merge merges other statistics into the statistics.
+func (s *Stats) Merge(o Stats) {
+	if o.Count == 0 {
+		return
+	}
+	if s.Count == 0 || o.Min < s.Min {
+		s.Min = o.Min
+	}
+	if s.Count == 0 || o.Max > s.Max {
+		s.Max = o.Max
+	}
+	s.Count += o.Count
+	s.Sum += o.Sum
+}
+
+// Aggregate defines aggregated measures of a module within a time bucket.
+type Aggregate struct {
+	Account    string           `json:"account,omitempty"`
+	DeviceID   string           `json:"device_id"`
+	ModuleID   string           `json:"module_id"`
+	Resolution Resolution       `json:"resolution"`
+	Timestamp  int64            `json:"timestamp"` // Bucket start, aligned to the resolution in UTC
+	Metrics    map[string]Stats `json:"metrics"`   // Keyed by measurement name (one of TargetMeasurements)
+}
+
+// Measure returns measure of bucket means, with the bucket start as timestamp. Rain is the bucket total instead,
+// set to both Rain and SumRain.
+func (a *Aggregate) Measure() Measure {
+	m := Measure{Account: a.Account, DeviceID: a.DeviceID, ModuleID: a.ModuleID, Timestamp: a.Timestamp}
+	for name, stats := range a.Metrics {
+		switch {
+		case stats.Count == 0:
+		case name == "Rain":
+			m.Rain, m.SumRain = floatPtr(stats.Sum), floatPtr(stats.Sum)
+		default:
+			m.SetValue(name, stats.Mean())
+		}
+	}
+	return m
+}
+
+// CompactionResult defines number of aggregates written by a compaction run.
+type CompactionResult struct {
+	Hourly int
+	Daily  int
+}
+
+// Compact rolls raw measures into hourly aggregates and hourly aggregates into daily aggregates, for the whole
+// buckets within [begin, end). Existing aggregates of the same buckets are replaced, so compacting the same
+// window again is safe.
+func Compact(ctx context.Context, store Store, begin, end time.Time) (*CompactionResult, error) {
+	result := &CompactionResult{}
+	hBegin, hEnd := alignBuckets(begin, end, time.Hour)
+	if hBegin < hEnd {
+		measures, err := store.Measures(ctx, MeasureFilter{Begin: hBegin, End: hEnd - 1})
+		if err != nil {
+			return nil, err
+		}
+		hourly := aggregateMeasures(measures, ResolutionHourly)
+		if err := store.WriteAggregates(ctx, hourly); err != nil {
+			return nil, err
+		}
+		result.Hourly = len(hourly)
+	}
+	dBegin, dEnd := alignBuckets(begin, end, 24*time.Hour)
+	if dBegin < dEnd {
+		hourly, err := store.Aggregates(ctx, ResolutionHourly, MeasureFilter{Begin: dBegin, End: dEnd - 1})
+		if err != nil {
+			return nil, err
+		}
+		daily := mergeAggregates(hourly, ResolutionDaily)
+		if err := store.WriteAggregates(ctx, daily); err != nil {
+			return nil, err
+		}
+		result.Daily = len(daily)
+	}
+	return result, nil
+}
+
+// RunCompaction compacts the lookback window before now immediately and then every interval until the context is
+// done. The window is extended back to the start of its first day (UTC), so the day just completed is compacted
+// even with lookbacks under a day. Failed runs are reported to onError (if not nil). It blocks, so start it with a
+// go statement.
+func RunCompaction(ctx context.Context, store Store, interval, lookback time.Duration, onError func(error)) error {
+	clock := ClockFromContext(ctx)
+	ticker := clock.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		now := clock.Now()
+		begin := now.Add(-lookback).UTC().Truncate(ResolutionDaily.Duration())
+		if _, err := Compact(ctx, store, begin, now); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
+
+// ResolutionFor chooses the finest resolution returning at most maxPoints samples per module for the time range.
+func ResolutionFor(begin, end int64, maxPoints int) Resolution {
+	span := time.Duration(end-begin) * time.Second
+	if maxPoints <= 0 || span <= 0 {
+		return ResolutionRaw
+	}
+	// Netatmo modules report every 5 minutes
+	if span/(5*time.Minute) <= time.Duration(maxPoints) {
+		return ResolutionRaw
+	}
+	if span/time.Hour <= time.Duration(maxPoints) {
+		return ResolutionHourly
+	}
+	return ResolutionDaily
+}
+
+// LoadMeasures returns stored measures matching the filter at the finest resolution fitting maxPoints samples per
+// module. Aggregated resolutions are returned as bucket means. Ranges not compacted yet (the buckets after the
+// newest aggregate of each module, or the whole range of modules without aggregates) are aggregated from raw
+// measures. A zero maxPoints always returns raw measures.
+func LoadMeasures(ctx context.Context, store Store, filter MeasureFilter, maxPoints int) ([]Measure, Resolution, error) {
+	res := loadResolution(ctx, filter, maxPoints)
+	if res == ResolutionRaw {
+		measures, err := store.Measures(ctx, filter)
+		return measures, res, err
+	}
+	aggregates, err := loadAggregates(ctx, store, res, filter)
+	if err != nil {
+		return nil, res, err
+	}
+	measures := make([]Measure, 0, len(aggregates))
+	for i := range aggregates {
+		measures = append(measures, aggregates[i].Measure())
+	}
+	return measures, res, nil
+}
+
+// loadResolution returns ResolutionFor the filter, whose zero end means now.
+func loadResolution(ctx context.Context, filter MeasureFilter, maxPoints int) Resolution {
+	end := filter.End
+	if end == 0 {
+		end = ClockFromContext(ctx).Now().Unix()
+	}
+	return ResolutionFor(filter.Begin, end, maxPoints)
+}
+
+// loadAggregates returns stored aggregates of the resolution matching the filter, completed with aggregates of raw
+// measures for ranges not compacted yet, ordered by account, device, module and timestamp.
+func loadAggregates(ctx context.Context, store Store, res Resolution, filter MeasureFilter) ([]Aggregate, error) {
+	aggregates, err := store.Aggregates(ctx, res, filter)
+	if err != nil {
+		return nil, err
+	}
+	uncovered, err := uncoveredAggregates(ctx, store, res, filter, aggregates)
+	if err != nil {
+		return nil, err
+	}
+	aggregates = append(aggregates, uncovered...)
+	sort.Slice(aggregates, func(i, j int) bool {
+		a, b := &aggregates[i], &aggregates[j]
+		if a.Account != b.Account {
+			return a.Account < b.Account
+		}
+		if a.DeviceID != b.DeviceID {
+			return a.DeviceID < b.DeviceID
+		}
+		if a.ModuleID != b.ModuleID {
+			return a.ModuleID < b.ModuleID
+		}
+		return a.Timestamp < b.Timestamp
+	})
+	return aggregates, nil
+}
+
+// uncoveredAggregates aggregates raw measures of the filter after the newest stored aggregate of each module, known
+// from the aggregates and the stored devices.
+func uncoveredAggregates(ctx context.Context, store Store, res Resolution, filter MeasureFilter,
+	aggregates []Aggregate) ([]Aggregate, error) {
+	size := int64(res.Duration() / time.Second)
+	covered := make(map[seriesKey]int64) // End of the newest aggregate of each module
+	for i := range aggregates {
+		a := &aggregates[i]
+		k := seriesKey{Account: a.Account, DeviceID: a.DeviceID, ModuleID: a.ModuleID}
+		covered[k] = max(covered[k], a.Timestamp+size)
+	}
+	devices, err := store.Devices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range devices {
+		d := &devices[i]
+		moduleIDs := []string{d.ID}
+		for _, m := range d.Modules {
+			moduleIDs = append(moduleIDs, m.ID)
+		}
+		for _, moduleID := range moduleIDs {
+			k := seriesKey{Account: d.Account, DeviceID: d.ID, ModuleID: moduleID}
+			if _, ok := covered[k]; !ok && filter.Match(&Measure{Account: k.Account, DeviceID: k.DeviceID,
+				ModuleID: k.ModuleID, Timestamp: filter.Begin}) {
+				covered[k] = 0
+			}
+		}
+	}
+	var uncovered []Aggregate
+	for k, coveredEnd := range covered {
+		f := MeasureFilter{Account: k.Account, DeviceID: k.DeviceID, ModuleID: k.ModuleID,
+			Begin: max(filter.Begin, coveredEnd), End: filter.End}
+		measures, err := store.Measures(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		uncovered = append(uncovered, aggregateMeasures(measures, res)...)
+	}
+	return uncovered, nil
+}
+
+// alignBuckets returns unix times of the first bucket start at or after begin and the last bucket end at or before
+// end.
+func alignBuckets(begin, end time.Time, size time.Duration) (int64, int64) {
+	b := begin.UTC().Truncate(size)
+	if b.Before(begin) {
+		b = b.Add(size)
+	}
+	return b.Unix(), end.UTC().Truncate(size).Unix()
+}
+
+func aggregateMeasures(measures []Measure, res Resolution) []Aggregate {
+	size := int64(res.Duration() / time.Second)
+	var aggregates []Aggregate
+	index := make(map[aggregateKey]int)
+	for i := range measures {
+		m := &measures[i]
+		k := aggregateKey{Account: m.Account, DeviceID: m.DeviceID, ModuleID: m.ModuleID, Resolution: res,
+			Timestamp: m.Timestamp - m.Timestamp%size}
+		j, ok := index[k]
+		if !ok {
+			j = len(aggregates)
+			index[k] = j
+			aggregates = append(aggregates, Aggregate{Account: k.Account, DeviceID: k.DeviceID, ModuleID: k.ModuleID,
+				Resolution: res, Timestamp: k.Timestamp, Metrics: make(map[string]Stats)})
+		}
+		for _, name := range TargetMeasurements {
+			if v, ok := m.Value(name); ok {
+				stats := aggregates[j].Metrics[name]
+				stats.Add(v)
+				aggregates[j].Metrics[name] = stats
+			}
+		}
+	}
+	return aggregates
+}
+
+func mergeAggregates(source []Aggregate, res Resolution) []Aggregate {
+	size := int64(res.Duration() / time.Second)
+	var aggregates []Aggregate
+	index := make(map[aggregateKey]int)
+	for i := range source {
+		a := &source[i]
+		k := aggregateKey{Account: a.Account, DeviceID: a.DeviceID, ModuleID: a.ModuleID, Resolution: res,
+			Timestamp: a.Timestamp - a.Timestamp%size}
+		j, ok := index[k]
+		if !ok {
+			j = len(aggregates)
+			index[k] = j
+			aggregates = append(aggregates, Aggregate{Account: k.Account, DeviceID: k.DeviceID, ModuleID: k.ModuleID,
+				Resolution: res, Timestamp: k.Timestamp, Metrics: make(map[string]Stats)})
+		}
+		for name, s := range a.Metrics {
+			stats := aggregates[j].Metrics[name]
+			stats.Merge(s)
+			aggregates[j].Metrics[name] = stats
+		}
+	}
+	return aggregates
+}
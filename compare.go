@@ -0,0 +1,91 @@
+package netatmo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// SeriesComparison defines per-sample differences between two series of the same metric (ex. two indoor rooms, or
+// the same sensor before and after relocation). Deltas are first series minus second series.
+type SeriesComparison struct {
+	Metric       string
+	Samples      int     // Number of aligned sample pairs
+	MeanA        float64 // Mean of the first series over aligned samples
+	MeanB        float64 // Mean of the second series over aligned samples
+	MeanDelta    float64
+	MinDelta     float64
+	MaxDelta     float64
+	MeanAbsDelta float64 // Mean absolute difference
+	RMSDelta     float64 // Root mean square difference
+	StdDevDelta  float64 // Population standard deviation of the differences
+	Deltas       []Point // Difference at each aligned timestamp of the first series
+}
+
+// PointsOf extracts the measurement name (ex. Temperature) of the measures as points, skipping measures without it.
+func PointsOf(measures []Measure, metric string) []Point {
+	var points []Point
+	for i := range measures {
+		if v, ok := measures[i].Value(metric); ok {
+			points = append(points, Point{Timestamp: measures[i].Timestamp, Value: v})
+		}
+	}
+	return points
+}
+
+// CompareSeries aligns two series ordered by timestamp, pairing samples whose timestamps differ at most tolerance
+// seconds, and computes per-sample deltas and summary statistics. Compare series of different periods (ex. before
+// and after relocation) by shifting timestamps of one series first.
+func CompareSeries(metric string, a, b []Point, tolerance int64) (*SeriesComparison, error) {
+	pairs := alignPoints(a, b, tolerance)
+	if len(pairs) == 0 {
+		return nil, errors.New("no aligned samples")
+	}
+	c := &SeriesComparison{Metric: metric, Samples: len(pairs), MinDelta: math.Inf(1), MaxDelta: math.Inf(-1),
+		Deltas: make([]Point, len(pairs))}
+	var sumA, sumB, sum, sumAbs, sumSquares float64
+	for i, p := range pairs {
+		d := p.A - p.B
+		c.Deltas[i] = Point{Timestamp: p.Timestamp, Value: d}
+		sumA += p.A
+		sumB += p.B
+		sum += d
+		sumAbs += math.Abs(d)
+		sumSquares += d * d
+		c.MinDelta = math.Min(c.MinDelta, d)
+		c.MaxDelta = math.Max(c.MaxDelta, d)
+	}
+	n := float64(len(pairs))
+	c.MeanA, c.MeanB, c.MeanDelta = sumA/n, sumB/n, sum/n
+	c.MeanAbsDelta = sumAbs / n
+	c.RMSDelta = math.Sqrt(sumSquares / n)
+	c.StdDevDelta = math.Sqrt(math.Max(sumSquares/n-c.MeanDelta*c.MeanDelta, 0))
+	return c, nil
+}
+
+// CompareModules fetches the metric of two modules over the periods of the filters and compares them, pairing
+// samples within half the scale of the options (ScaleMax by default).
+func (c *Client) CompareModules(metric string, a, b MeasureFilter, opts ...MeasureOption) (*SeriesComparison,
+	error) {
+	return c.CompareModulesContext(context.Background(), metric, a, b, opts...)
+}
+
+// CompareModulesContext is CompareModules with a context canceling the requests.
+func (c *Client) CompareModulesContext(ctx context.Context, metric string, a, b MeasureFilter,
+	opts ...MeasureOption) (*SeriesComparison, error) {
+	opts = append(append([]MeasureOption{}, opts...), WithTypes(metric), WithOrder(OrderAscending))
+	req, err := newMeasureRequest(opts)
+	if err != nil {
+		return nil, err
+	}
+	measuresA, err := c.getMeasurePages(ctx, a.DeviceID, a.ModuleID, a.Begin, a.End, opts)
+	if err != nil {
+		return nil, err
+	}
+	measuresB, err := c.getMeasurePages(ctx, b.DeviceID, b.ModuleID, b.Begin, b.End, opts)
+	if err != nil {
+		return nil, err
+	}
+	return CompareSeries(metric, PointsOf(measuresA, metric), PointsOf(measuresB, metric), int64(req.scale.step()/time.Second)/2)
+}
@@ -0,0 +1,125 @@
+package netatmo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// consistencyTolerance defines the largest value difference treated as rounding.
+const consistencyTolerance = 0.05
+
+// maxConsistencySkew defines the largest timestamp difference treated as the same sample.
+const maxConsistencySkew = time.Minute
+
+// DiscrepancyKind defines kind of difference between dashboard data and getmeasure.
+type DiscrepancyKind string
+
+// Supported discrepancy kinds.
+const (
+	DiscrepancyTimestampSkew DiscrepancyKind = "timestamp_skew" // Samples are taken at different times
+	DiscrepancyValueMismatch DiscrepancyKind = "value_mismatch" // Values differ beyond rounding
+	DiscrepancyNulledZero    DiscrepancyKind = "nulled_zero"    // Dashboard shows 0 while the measure is null
+	DiscrepancyMissing       DiscrepancyKind = "missing"        // Only one side has the value
+)
+
+// Discrepancy defines a difference between dashboard data and the newest measure of a module.
+type Discrepancy struct {
+	Kind      DiscrepancyKind
+	Metric    string   // Empty for timestamp skew
+	Dashboard *float64 // Nullable
+	Measure   *float64 // Nullable
+	Message   string
+}
+
+// ConsistencyReport defines result of a dashboard-vs-measure comparison.
+type ConsistencyReport struct {
+	DeviceID      string
+	ModuleID      string
+	DashboardTime int64
+	MeasureTime   int64
+	Skew          time.Duration // Measure time minus dashboard time
+	Discrepancies []Discrepancy
+}
+
+// Consistent reports whether no discrepancy was found.
+func (r *ConsistencyReport) Consistent() bool {
+	return len(r.Discrepancies) == 0
+}
+
+// CheckConsistency fetches the dashboard data and the newest getmeasure sample of the module and reports their
+// differences. It explains the common "the CLI shows different numbers than the app" confusion: the app shows
+// dashboard data, while measures may be older, rounded or have zeros turned into nulls.
+func (c *Client) CheckConsistency(deviceID, moduleID string) (*ConsistencyReport, error) {
+	return c.CheckConsistencyContext(context.Background(), deviceID, moduleID)
+}
+
+// CheckConsistencyContext is CheckConsistency with a context canceling the requests.
+func (c *Client) CheckConsistencyContext(ctx context.Context, deviceID, moduleID string) (*ConsistencyReport, error) {
+	device, err := c.getDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	dashboard := device.DashboardData
+	if moduleID != deviceID {
+		module := findModule(device.Modules, moduleID)
+		if module == nil {
+			return nil, fmt.Errorf("module not found: %s", moduleID)
+		}
+		dashboard = module.DashboardData
+	}
+	measure, err := c.GetMeasureByNewestContext(ctx, deviceID, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	return CompareDashboard(deviceID, moduleID, dashboard, measure), nil
+}
+
+// CompareDashboard compares dashboard data with a measure of the same module. Both may be nil.
+func CompareDashboard(deviceID, moduleID string, dashboard *DashboardData, measure *Measure) *ConsistencyReport {
+	report := &ConsistencyReport{DeviceID: deviceID, ModuleID: moduleID}
+	if dashboard == nil || measure == nil {
+		report.Discrepancies = append(report.Discrepancies, Discrepancy{Kind: DiscrepancyMissing,
+			Message: fmt.Sprintf("dashboard data present: %t, measure present: %t", dashboard != nil, measure != nil)})
+		return report
+	}
+	report.DashboardTime = dashboard.UTCTime
+	report.MeasureTime = measure.Timestamp
+	report.Skew = time.Duration(measure.Timestamp-dashboard.UTCTime) * time.Second
+	if math.Abs(report.Skew.Seconds()) > maxConsistencySkew.Seconds() {
+		report.Discrepancies = append(report.Discrepancies, Discrepancy{Kind: DiscrepancyTimestampSkew,
+			Message: fmt.Sprintf("newest measure is %s apart from dashboard data", report.Skew)})
+	}
+	for _, name := range TargetMeasurements {
+		dv, dok := dashboard.Value(name)
+		mv, mok := measure.Value(name)
+		d := Discrepancy{Metric: name}
+		if dok {
+			d.Dashboard = &dv
+		}
+		if mok {
+			d.Measure = &mv
+		}
+		switch {
+		case !dok && !mok:
+			continue
+		case dok && !mok && dv == 0:
+			d.Kind = DiscrepancyNulledZero
+			d.Message = fmt.Sprintf("%s is 0 on dashboard but null in measure (exact zeros are treated as null)", name)
+		case dok && !mok:
+			d.Kind = DiscrepancyMissing
+			d.Message = fmt.Sprintf("%s is present only in dashboard", name)
+		case !dok && mok:
+			d.Kind = DiscrepancyMissing
+			d.Message = fmt.Sprintf("%s is present only in measure", name)
+		case math.Abs(dv-mv) > consistencyTolerance:
+			d.Kind = DiscrepancyValueMismatch
+			d.Message = fmt.Sprintf("%s is %v on dashboard but %v in measure", name, dv, mv)
+		default:
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies, d)
+	}
+	return report
+}
@@ -0,0 +1,134 @@
+package netatmo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// CorrelationMethod defines coefficient computed by Correlate.
+type CorrelationMethod string
+
+// Supported correlation methods.
+const (
+	CorrelationPearson  CorrelationMethod = "pearson"  // Linear relationship of values
+	CorrelationSpearman CorrelationMethod = "spearman" // Monotonic relationship, Pearson of ranks
+)
+
+// Correlation defines correlation between two series.
+type Correlation struct {
+	Method      CorrelationMethod
+	Lag         time.Duration // Positive if the second series follows the first one (ex. indoor after outdoor)
+	Samples     int           // Number of aligned sample pairs
+	Coefficient float64       // -1 to 1, NaN if a series is constant
+}
+
+// Correlate computes the coefficient between two series ordered by timestamp, pairing samples whose timestamps
+// differ at most tolerance seconds (ex. outdoor temperature and indoor CO2 from PointsOf or QueryStore).
+func Correlate(method CorrelationMethod, a, b []Point, tolerance int64) (*Correlation, error) {
+	return correlateLagged(method, a, b, tolerance, 0)
+}
+
+// CorrelateLags computes coefficients with the second series shifted by every multiple of the step from -maxLag to
+// maxLag, ordered by lag. Lags without aligned samples are skipped. Use StrongestCorrelation to find the lag with the
+// strongest relationship.
+func CorrelateLags(method CorrelationMethod, a, b []Point, tolerance int64, maxLag, step time.Duration) (
+	[]Correlation, error) {
+	if step <= 0 {
+		return nil, errors.New("lag step must be positive")
+	}
+	var results []Correlation
+	for lag := -maxLag / step * step; lag <= maxLag; lag += step {
+		c, err := correlateLagged(method, a, b, tolerance, lag)
+		if err != nil {
+			continue
+		}
+		results = append(results, *c)
+	}
+	if len(results) == 0 {
+		return nil, errors.New("not enough aligned samples")
+	}
+	return results, nil
+}
+
+// StrongestCorrelation returns the correlation with the largest absolute coefficient, or nil if none is defined.
+func StrongestCorrelation(correlations []Correlation) *Correlation {
+	var strongest *Correlation
+	for i := range correlations {
+		c := &correlations[i]
+		if math.IsNaN(c.Coefficient) {
+			continue
+		}
+		if strongest == nil || math.Abs(c.Coefficient) > math.Abs(strongest.Coefficient) {
+			strongest = c
+		}
+	}
+	return strongest
+}
+
+func correlateLagged(method CorrelationMethod, a, b []Point, tolerance int64, lag time.Duration) (*Correlation,
+	error) {
+	shift := int64(lag / time.Second)
+	if shift != 0 {
+		shifted := make([]Point, len(b))
+		for i, p := range b {
+			shifted[i] = Point{Timestamp: p.Timestamp - shift, Value: p.Value}
+		}
+		b = shifted
+	}
+	pairs := alignPoints(a, b, tolerance)
+	if len(pairs) < 3 {
+		return nil, errors.New("not enough aligned samples")
+	}
+	x, y := make([]float64, len(pairs)), make([]float64, len(pairs))
+	for i, p := range pairs {
+		x[i], y[i] = p.A, p.B
+	}
+	switch method {
+	case CorrelationPearson:
+	case CorrelationSpearman:
+		x, y = ranks(x), ranks(y)
+	default:
+		return nil, fmt.Errorf("unknown correlation method: %s", method)
+	}
+	return &Correlation{Method: method, Lag: lag, Samples: len(pairs), Coefficient: pearson(x, y)}, nil
+}
+
+func pearson(x, y []float64) float64 {
+	meanX, meanY := mean(x), mean(y)
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// ranks returns 1-based ranks of the values, averaging ranks of ties.
+func ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return values[order[i]] < values[order[j]] })
+	result := make([]float64, len(values))
+	for i := 0; i < len(order); {
+		j := i
+		for j+1 < len(order) && values[order[j+1]] == values[order[i]] {
+			j++
+		}
+		rank := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			result[order[k]] = rank
+		}
+		i = j + 1
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+package netatmo
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// DefaultSampleInterval defines the interval Netatmo modules report measures at.
+const DefaultSampleInterval = 5 * time.Minute
+
+// Gap defines a period without samples.
+type Gap struct {
+	Begin   int64 // Unix time of the last sample before the gap, or the range beginning
+	End     int64 // Unix time of the first sample after the gap, or the range end
+	Missing int   // Estimated number of missing samples
+}
+
+// MetricCoverage defines coverage of a single measurement.
+type MetricCoverage struct {
+	Metric  string
+	Actual  int     // Number of samples with non-null value
+	Percent float64 // Actual / expected samples in percent
+}
+
+// CoverageReport defines completeness of measures of a module over a time range.
+type CoverageReport struct {
+	DeviceID string
+	ModuleID string
+	Begin    int64
+	End      int64
+	Interval time.Duration
+	Expected int     // Number of samples expected at the interval
+	Actual   int     // Number of samples present
+	Percent  float64 // Actual / Expected in percent, capped at 100
+	Metrics  []MetricCoverage
+	Gaps     []Gap
+}
+
+// Coverage computes completeness of measures of a single module ordered by timestamp within [begin, end]. Metrics
+// default to TargetMeasurements; measurements the module never reported are omitted from Metrics.
+func Coverage(measures []Measure, begin, end int64, interval time.Duration, metrics ...string) *CoverageReport {
+	if interval <= 0 {
+		interval = DefaultSampleInterval
+	}
+	if len(metrics) == 0 {
+		metrics = TargetMeasurements
+	}
+	step := int64(interval / time.Second)
+	report := &CoverageReport{Begin: begin, End: end, Interval: interval, Expected: int((end - begin) / step)}
+	if len(measures) > 0 {
+		report.DeviceID, report.ModuleID = measures[0].DeviceID, measures[0].ModuleID
+	}
+	counts := make(map[string]int)
+	previous := begin
+	for i := range measures {
+		m := &measures[i]
+		if m.Timestamp < begin || m.Timestamp > end {
+			continue
+		}
+		report.Actual++
+		for _, name := range metrics {
+			if _, ok := m.Value(name); ok {
+				counts[name]++
+			}
+		}
+		report.addGap(previous, m.Timestamp, step)
+		previous = m.Timestamp
+	}
+	report.addGap(previous, end, step)
+	report.Percent = percentOf(report.Actual, report.Expected)
+	for _, name := range metrics {
+		if counts[name] > 0 {
+			report.Metrics = append(report.Metrics, MetricCoverage{Metric: name, Actual: counts[name],
+				Percent: percentOf(counts[name], report.Expected)})
+		}
+	}
+	return report
+}
+
+// StoreCoverage computes coverage of stored raw measures of the module selected by the filter, which must set
+// Begin and End.
+func StoreCoverage(ctx context.Context, store Store, filter MeasureFilter, interval time.Duration) (*CoverageReport, error) {
+	measures, err := store.Measures(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	report := Coverage(measures, filter.Begin, filter.End, interval)
+	report.DeviceID, report.ModuleID = filter.DeviceID, filter.ModuleID
+	return report, nil
+}
+
+// addGap records a gap if the samples are more than 1.5 intervals apart.
+func (r *CoverageReport) addGap(from, to, step int64) {
+	if to-from <= step*3/2 {
+		return
+	}
+	missing := int(math.Round(float64(to-from)/float64(step))) - 1
+	r.Gaps = append(r.Gaps, Gap{Begin: from, End: to, Missing: missing})
+}
+
+func percentOf(actual, expected int) float64 {
+	if expected <= 0 {
+		return 100
+	}
+	return math.Min(100, float64(actual)*100/float64(expected))
+}
@@ -0,0 +1,142 @@
+package netatmo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"time"
+)
+
+// defaultCWOPServer defines APRS-IS server of the Citizen Weather Observer Program.
+const defaultCWOPServer = "cwop.aprs.net:14580"
+
+// CWOPUploader submits observations as APRS weather packets to CWOP via APRS-IS.
+// Reference: http://www.wxqa.com/faq.html
+type CWOPUploader struct {
+	Callsign string        // Amateur radio callsign, or CWOP ID (ex. CW1234)
+	Passcode string        // APRS-IS passcode, "-1" for CWOP IDs
+	Server   string        // Host and port, defaults to cwop.aprs.net:14580
+	Comment  string        // Optional text appended to packets
+	Timeout  time.Duration // Timeout of a submission, defaults to 30 seconds
+	tracker  uploadTracker
+}
+
+// Upload connects to the server, logs in and sends the observation. Observations not newer than the last uploaded one
+// of the device are skipped.
+func (u *CWOPUploader) Upload(ctx context.Context, o Observation) error {
+	if !u.tracker.isNew(&o) {
+		return nil
+	}
+	server := u.Server
+	if server == "" {
+		server = defaultCWOPServer
+	}
+	if dryRun(ctx, "cwop", "observation of "+u.Callsign+" to "+server, APRSWeatherPacket(u.Callsign, o, u.Comment)) {
+		return nil
+	}
+	timeout := u.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	reader := bufio.NewReader(conn)
+	if _, err := reader.ReadString('\n'); err != nil { // Server banner
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "user %s pass %s vers netatmo-weather-go 1.0\r\n", u.Callsign, u.Passcode); err != nil {
+		return err
+	}
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(response, "logresp") {
+		return fmt.Errorf("aprs-is login: %s", strings.TrimSpace(response))
+	}
+	if _, err := fmt.Fprintf(conn, "%s\r\n", APRSWeatherPacket(u.Callsign, o, u.Comment)); err != nil {
+		return err
+	}
+	u.tracker.uploaded(&o)
+	return nil
+}
+
+// APRSWeatherPacket formats the observation as an APRS positional weather report with timestamp
+// (ex. "CW1234>APRS,TCPIP*:@171230z3540.95N/13945.17E_270/006g014t045r000p...P000h80b10132netatmo").
+// Unavailable values are rendered as dots.
+func APRSWeatherPacket(callsign string, o Observation, comment string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToUpper(callsign))
+	b.WriteString(">APRS,TCPIP*:@")
+	b.WriteString(time.Unix(o.Time, 0).UTC().Format("021504"))
+	b.WriteString("z")
+	b.WriteString(aprsCoordinate(o.Latitude, 2, "N", "S"))
+	b.WriteString("/")
+	b.WriteString(aprsCoordinate(o.Longitude, 3, "E", "W"))
+	b.WriteString("_")
+	b.WriteString(aprsValue(o.WindAngle, nil, 3))
+	b.WriteString("/")
+	b.WriteString(aprsValue(o.WindSpeed, kmhToMph, 3))
+	b.WriteString("g" + aprsValue(o.GustSpeed, kmhToMph, 3))
+	b.WriteString("t" + aprsValue(o.Temperature, celsiusToFahrenheit, 3))
+	b.WriteString("r" + aprsValue(o.RainLastHour, hundredthsInch, 3))
+	b.WriteString("p...") // Rain over the last 24 hours is not available from station data
+	b.WriteString("P" + aprsValue(o.RainToday, hundredthsInch, 3))
+	if o.Humidity != nil {
+		h := int(math.Round(*o.Humidity)) % 100 // 100% is encoded as 00
+		b.WriteString(fmt.Sprintf("h%02d", h))
+	}
+	if o.Pressure != nil {
+		b.WriteString(fmt.Sprintf("b%05d", int(math.Round(*o.Pressure*10))))
+	}
+	b.WriteString(comment)
+	return b.String()
+}
+
+// aprsCoordinate formats degrees as degrees and decimal minutes (ex. 35.6825 -> 3540.95N).
+func aprsCoordinate(v float64, width int, positive, negative string) string {
+	hemisphere := positive
+	if v < 0 {
+		v, hemisphere = -v, negative
+	}
+	degrees := math.Floor(v)
+	minutes := (v - degrees) * 60
+	if math.Round(minutes*100) >= 6000 {
+		degrees, minutes = degrees+1, 0
+	}
+	return fmt.Sprintf("%0*d%05.2f%s", width, int(degrees), minutes, hemisphere)
+}
+
+// aprsValue formats the converted value rounded to the width, negative with a minus sign, or dots if unavailable.
+func aprsValue(v *float64, convert func(float64) float64, width int) string {
+	if v == nil {
+		return strings.Repeat(".", width)
+	}
+	value := *v
+	if convert != nil {
+		value = convert(value)
+	}
+	r := int(math.Round(value))
+	if r < 0 {
+		return fmt.Sprintf("-%0*d", width-1, -r)
+	}
+	return fmt.Sprintf("%0*d", width, r)
+}
+
+func hundredthsInch(mm float64) float64 {
+	return mmToInch(mm) * 100
+}
@@ -0,0 +1,141 @@
+package netatmo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithDebug writes API and token requests with their responses to w (ex. os.Stderr) to troubleshoot rejected
+// requests and unexpected responses. Credentials and tokens in URLs, headers, forms and token responses are masked.
+func WithDebug(w io.Writer) Option {
+	return func(o *clientOptions) {
+		o.debug = w
+	}
+}
+
+// debugMask replaces masked values in debug output.
+const debugMask = "REDACTED"
+
+// debugSecrets defines names of query and form parameters masked by the debug transport.
+var debugSecrets = []string{"access_token", "refresh_token", "client_secret", "password", "code"}
+
+// debugTokenPattern matches tokens of JSON token responses.
+var debugTokenPattern = regexp.MustCompile(`"(access_token|refresh_token)"\s*:\s*"[^"]*"`)
+
+// debugTransport dumps requests and responses of the base transport.
+type debugTransport struct {
+	base http.RoundTripper
+	mu   sync.Mutex
+	w    io.Writer
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--> %s %s\n", req.Method, maskURL(req.URL))
+	writeDebugHeader(&b, req.Header)
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			if len(data) > 0 {
+				fmt.Fprintf(&b, "%s\n", maskForm(string(data)))
+			}
+		}
+	}
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(&b, "<-- %s %s failed (%s): %v\n", req.Method, maskURL(req.URL), elapsed, err)
+		t.write(b.String())
+		return nil, err
+	}
+	fmt.Fprintf(&b, "<-- %s %s (%s)\n", resp.Status, maskURL(req.URL), elapsed)
+	writeDebugHeader(&b, resp.Header)
+	// The body is replayed to the caller, including a read failure
+	data, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	var replay io.Reader = bytes.NewReader(data)
+	if readErr != nil {
+		replay = io.MultiReader(replay, errReader{readErr})
+	}
+	resp.Body = io.NopCloser(replay)
+	if len(data) > 0 {
+		fmt.Fprintf(&b, "%s\n", debugTokenPattern.ReplaceAllString(string(data), `"$1":"`+debugMask+`"`))
+	}
+	t.write(b.String())
+	return resp, nil
+}
+
+// CloseIdleConnections closes idle connections of the base transport, used by Client.Close.
+func (t *debugTransport) CloseIdleConnections() {
+	if c, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+func (t *debugTransport) write(s string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = io.WriteString(t.w, s)
+}
+
+// writeDebugHeader writes the header sorted by name, masking credentials.
+func writeDebugHeader(b *strings.Builder, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		switch http.CanonicalHeaderKey(name) {
+		case "Authorization", "Cookie", "Set-Cookie":
+			value = debugMask
+		}
+		fmt.Fprintf(b, "    %s: %s\n", name, value)
+	}
+}
+
+// maskURL returns the URL with secret query parameters masked.
+func maskURL(u *url.URL) string {
+	masked := *u
+	masked.RawQuery = maskForm(u.RawQuery)
+	return masked.String()
+}
+
+// maskForm returns the URL-encoded form with secret parameters masked, or the text as is if not a form.
+func maskForm(s string) string {
+	values, err := url.ParseQuery(s)
+	if err != nil || s == "" {
+		return s
+	}
+	masked := false
+	for _, name := range debugSecrets {
+		if values.Has(name) {
+			values.Set(name, debugMask)
+			masked = true
+		}
+	}
+	if !masked {
+		return s
+	}
+	return values.Encode()
+}
+
+// errReader fails with the error.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
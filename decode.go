@@ -0,0 +1,211 @@
+package netatmo
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of FieldWarning.
+const (
+	FieldUnknown = "unknown" // Response field not mapped by this package, possibly added upstream
+	FieldMissing = "missing" // Required response field absent, possibly removed or renamed upstream
+)
+
+// FieldWarning defines a difference between an API response and the fields this package expects.
+type FieldWarning struct {
+	Endpoint string
+	Kind     string // FieldUnknown or FieldMissing
+	Path     string // JSON path of the field (ex. body.devices[0].home_id)
+}
+
+func (w FieldWarning) String() string {
+	return w.Endpoint + ": " + w.Kind + " field " + w.Path
+}
+
+// DecodeError is returned with WithStrictDecoding when responses have unknown or missing required fields.
+type DecodeError struct {
+	Endpoint string
+	Warnings []FieldWarning
+}
+
+func (e *DecodeError) Error() string {
+	paths := make([]string, 0, len(e.Warnings))
+	for _, w := range e.Warnings {
+		paths = append(paths, w.Kind+" "+w.Path)
+	}
+	return fmt.Sprintf("%s: strict decoding: %s", e.Endpoint, strings.Join(paths, ", "))
+}
+
+// WithStrictDecoding makes API calls fail with DecodeError when responses have fields unknown to this package (like
+// json.Decoder.DisallowUnknownFields) or lack required fields. It is meant for development and CI, to detect
+// upstream schema changes early; by default responses are decoded tolerantly.
+func WithStrictDecoding() Option {
+	return func(o *clientOptions) {
+		o.strictDecoding = true
+	}
+}
+
+// WithFieldWarningHook calls the function with unknown and missing required fields of each response, also without
+// strict decoding, so production clients can log schema changes without failing.
+func WithFieldWarningHook(fn func(w FieldWarning)) Option {
+	return func(o *clientOptions) {
+		o.fieldWarningHooks = append(o.fieldWarningHooks, fn)
+	}
+}
+
+// requiredFields defines JSON paths of response fields the client relies on, keyed by endpoint. "[]" matches every
+// element of an array.
+var requiredFields = map[string][]string{
+	"getstationsdata": {"status", "body.devices", "body.devices[]._id", "body.devices[].type",
+		"body.devices[].modules[]._id", "body.devices[].modules[].type", "body.user"},
+	"getmeasure":    {"status", "body", "body[].beg_time", "body[].value"},
+	"getpublicdata": {"status", "body", "body[]._id", "body[].place.location", "body[].measures"},
+}
+
+// decode unmarshals the response of the endpoint into v, checking fields if strict decoding or warning hooks are
+// enabled.
+func (c *Client) decode(endpoint string, data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	if !c.options.strictDecoding && len(c.options.fieldWarningHooks) == 0 {
+		return nil
+	}
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var warnings []FieldWarning
+	for _, path := range unknownFields(raw, reflect.TypeOf(v), "") {
+		warnings = append(warnings, FieldWarning{Endpoint: endpoint, Kind: FieldUnknown, Path: path})
+	}
+	for _, required := range requiredFields[endpoint] {
+		for _, path := range missingFields(raw, strings.Split(required, "."), "") {
+			warnings = append(warnings, FieldWarning{Endpoint: endpoint, Kind: FieldMissing, Path: path})
+		}
+	}
+	for _, w := range warnings {
+		for _, hook := range c.options.fieldWarningHooks {
+			hook(w)
+		}
+	}
+	if c.options.strictDecoding && len(warnings) > 0 {
+		return &DecodeError{Endpoint: endpoint, Warnings: warnings}
+	}
+	return nil
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unknownFields returns paths of object keys of the raw JSON value not mapped by the type, matching names
+// case-insensitively as encoding/json does.
+func unknownFields(raw interface{}, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+	var unknown []string
+	switch value := raw.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(t)
+			for _, key := range sortedKeys(value) {
+				field, ok := fields[strings.ToLower(key)]
+				if !ok {
+					unknown = append(unknown, joinPath(path, key))
+					continue
+				}
+				unknown = append(unknown, unknownFields(value[key], field.Type, joinPath(path, key))...)
+			}
+		case reflect.Map:
+			for _, key := range sortedKeys(value) {
+				unknown = append(unknown, unknownFields(value[key], t.Elem(), joinPath(path, key))...)
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, v := range value {
+				unknown = append(unknown, unknownFields(v, t.Elem(), path+"["+strconv.Itoa(i)+"]")...)
+			}
+		}
+	}
+	return unknown
+}
+
+// jsonFields returns exported fields of the struct keyed by lower-case JSON name, including promoted fields of
+// embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for k, v := range jsonFields(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f
+	}
+	return fields
+}
+
+// missingFields returns paths where the remaining segments of a required path are absent from the raw JSON value.
+func missingFields(raw interface{}, segments []string, path string) []string {
+	if len(segments) == 0 {
+		return nil
+	}
+	name := segments[0]
+	each := strings.HasSuffix(name, "[]")
+	name = strings.TrimSuffix(name, "[]")
+	object, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil // Type mismatches are reported by json.Unmarshal
+	}
+	value, ok := object[name]
+	if !ok || value == nil {
+		return []string{joinPath(path, name)}
+	}
+	if !each {
+		return missingFields(value, segments[1:], joinPath(path, name))
+	}
+	array, _ := value.([]interface{})
+	var missing []string
+	for i, v := range array {
+		missing = append(missing, missingFields(v, segments[1:], joinPath(path, name)+"["+strconv.Itoa(i)+"]")...)
+	}
+	return missing
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
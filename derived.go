@@ -0,0 +1,56 @@
+package netatmo
+
+import (
+	"math"
+	"strings"
+)
+
+// Module type identifiers.
+const (
+	TypeMain    = "NAMain"    // Base station (indoor)
+	TypeOutdoor = "NAModule1" // Outdoor module
+	TypeWind    = "NAModule2" // Anemometer
+	TypeRain    = "NAModule3" // Rain gauge
+	TypeIndoor  = "NAModule4" // Additional indoor module
+)
+
+// DerivedMeasurements defines list of measurement types computed from other measurements by
+// ApplyDerivedMeasurements, not available from the API.
+var DerivedMeasurements = []string{"dew_point", "humidex"}
+
+// ApplyDerivedMeasurements sets dew point and humidex of the measures having temperature and humidity.
+func ApplyDerivedMeasurements(measures []Measure) {
+	for i := range measures {
+		m := &measures[i]
+		if m.DewPoint = dewPointOf(m.Temperature, m.Humidity); m.DewPoint != nil {
+			humidex := Humidex(*m.Temperature, float64(*m.Humidity))
+			m.Humidex = &humidex
+		}
+	}
+}
+
+// isDerivedMeasurement reports whether the measurement type is computed by ApplyDerivedMeasurements.
+func isDerivedMeasurement(name string) bool {
+	for _, t := range DerivedMeasurements {
+		if strings.EqualFold(t, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// DewPoint computes dew point in °C from temperature in °C and relative humidity in %, using the Magnus formula.
+func DewPoint(temperature, humidity float64) float64 {
+	const a, b = 17.62, 243.12
+	gamma := math.Log(humidity/100) + a*temperature/(b+temperature)
+	return b * gamma / (a - gamma)
+}
+
+// dewPointOf computes dew point from nullable dashboard values.
+func dewPointOf(temperature *float64, humidity *int) *float64 {
+	if temperature == nil || humidity == nil || *humidity <= 0 {
+		return nil
+	}
+	v := DewPoint(*temperature, float64(*humidity))
+	return &v
+}
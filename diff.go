@@ -0,0 +1,140 @@
+package netatmo
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Event types reported by snapshot diff detectors.
+const (
+	EventModuleAdded         EventType = "module_added"
+	EventModuleRemoved       EventType = "module_removed"
+	EventModuleRenamed       EventType = "module_renamed"
+	EventReachabilityChanged EventType = "reachability_changed"
+	EventBatteryDropped      EventType = "battery_dropped"
+)
+
+// DefaultBatteryDrop defines battery percent points DiffDetectors require to report a battery drop.
+const DefaultBatteryDrop = 10
+
+// DiffDetectors defines detectors used by DiffStations.
+var DiffDetectors = []Detector{DetectModuleChange, DetectFirmwareChange, DetectReachabilityChange,
+	BatteryDropDetector(DefaultBatteryDrop)}
+
+// DiffStations compares two GetStationsData results with DiffDetectors and reports the changes (ex. for a change
+// log). Previous may be nil, reporting nothing.
+func DiffStations(previous, current []Device, now int64) []Event {
+	if previous == nil {
+		return nil
+	}
+	var events []Event
+	for _, detect := range DiffDetectors {
+		events = append(events, detect(previous, current, now)...)
+	}
+	return events
+}
+
+// DetectModuleChange reports added, removed and renamed devices and modules.
+func DetectModuleChange(previous, current []Device, now int64) []Event {
+	if previous == nil {
+		return nil
+	}
+	var events []Event
+	for _, d := range current {
+		old := findDevice(previous, d.ID)
+		if old == nil {
+			events = append(events, Event{Type: EventModuleAdded, Time: now, DeviceID: d.ID, ModuleID: d.ID,
+				New: d.ModuleName})
+			continue
+		}
+		if old.ModuleName != d.ModuleName {
+			events = append(events, Event{Type: EventModuleRenamed, Time: now, DeviceID: d.ID, ModuleID: d.ID,
+				Old: old.ModuleName, New: d.ModuleName})
+		}
+		for _, m := range d.Modules {
+			oldModule := findModule(old.Modules, m.ID)
+			if oldModule == nil {
+				events = append(events, Event{Type: EventModuleAdded, Time: now, DeviceID: d.ID, ModuleID: m.ID,
+					New: m.ModuleName})
+			} else if oldModule.ModuleName != m.ModuleName {
+				events = append(events, Event{Type: EventModuleRenamed, Time: now, DeviceID: d.ID, ModuleID: m.ID,
+					Old: oldModule.ModuleName, New: m.ModuleName})
+			}
+		}
+	}
+	for _, d := range previous {
+		kept := findDevice(current, d.ID)
+		if kept == nil {
+			events = append(events, Event{Type: EventModuleRemoved, Time: now, DeviceID: d.ID, ModuleID: d.ID,
+				Old: d.ModuleName})
+			continue
+		}
+		for _, m := range d.Modules {
+			if findModule(kept.Modules, m.ID) == nil {
+				events = append(events, Event{Type: EventModuleRemoved, Time: now, DeviceID: d.ID, ModuleID: m.ID,
+					Old: m.ModuleName})
+			}
+		}
+	}
+	return events
+}
+
+// DetectReachabilityChange reports devices and modules becoming reachable or unreachable.
+func DetectReachabilityChange(previous, current []Device, now int64) []Event {
+	var events []Event
+	for _, d := range current {
+		old := findDevice(previous, d.ID)
+		if old == nil {
+			continue
+		}
+		if old.Reachable != d.Reachable {
+			events = append(events, reachabilityEvent(now, d.ID, d.ID, old.Reachable, d.Reachable))
+		}
+		for _, m := range d.Modules {
+			oldModule := findModule(old.Modules, m.ID)
+			if oldModule != nil && oldModule.Reachable != m.Reachable {
+				events = append(events, reachabilityEvent(now, d.ID, m.ID, oldModule.Reachable, m.Reachable))
+			}
+		}
+	}
+	return events
+}
+
+// BatteryDropDetector returns a detector reporting battery percent of modules decreased by at least the points.
+func BatteryDropDetector(points int) Detector {
+	return func(previous, current []Device, now int64) []Event {
+		var events []Event
+		for _, d := range current {
+			old := findDevice(previous, d.ID)
+			if old == nil {
+				continue
+			}
+			for _, m := range d.Modules {
+				oldModule := findModule(old.Modules, m.ID)
+				if oldModule != nil && m.BatteryPercent < oldModule.BatteryPercent &&
+					oldModule.BatteryPercent-m.BatteryPercent >= points {
+					events = append(events, Event{Type: EventBatteryDropped, Time: now, DeviceID: d.ID, ModuleID: m.ID,
+						Old: strconv.Itoa(oldModule.BatteryPercent), New: strconv.Itoa(m.BatteryPercent)})
+				}
+			}
+		}
+		return events
+	}
+}
+
+// Alert converts the event into an alert for a Notifier. Removals and modules becoming unreachable are warnings,
+// other events are informational.
+func (e Event) Alert() Alert {
+	severity := SeverityInfo
+	if e.Type == EventModuleRemoved || (e.Type == EventReachabilityChanged && e.New == "false") {
+		severity = SeverityWarning
+	}
+	message := fmt.Sprintf("%s of %s: %s -> %s", e.Type, e.ModuleID, e.Old, e.New)
+	return Alert{Time: e.Time, Severity: severity, Kind: string(e.Type), DeviceID: e.DeviceID, ModuleID: e.ModuleID,
+		Message: message}
+}
+
+func reachabilityEvent(now int64, deviceID, moduleID string, old, new bool) Event {
+	return Event{Type: EventReachabilityChanged, Time: now, DeviceID: deviceID, ModuleID: moduleID,
+		Old: strconv.FormatBool(old), New: strconv.FormatBool(new)}
+}
@@ -0,0 +1,139 @@
+package netatmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// DriftReport defines gradual change of the difference between two sensors measuring the same quantity.
+// Offsets are first sensor minus second sensor.
+type DriftReport struct {
+	Metric      string
+	Samples     int     // Number of aligned sample pairs
+	Begin       int64   // Unix time of the first aligned pair
+	End         int64   // Unix time of the last aligned pair
+	MeanOffset  float64 // Mean difference over the period
+	StartOffset float64 // Fitted difference at Begin
+	EndOffset   float64 // Fitted difference at End, the estimated current offset to correct
+	DriftPerDay float64 // Slope of the fitted difference
+	R2          float64 // Coefficient of determination of the linear fit, near 1 means steady drift
+}
+
+// Drift returns total fitted change of the offset over the period.
+func (r *DriftReport) Drift() float64 {
+	return r.EndOffset - r.StartOffset
+}
+
+// Drifting reports whether the offset changed more than the tolerance over the period.
+func (r *DriftReport) Drifting(tolerance float64) bool {
+	return math.Abs(r.Drift()) > tolerance
+}
+
+// alignedPair defines values of two series at the same time.
+type alignedPair struct {
+	Timestamp int64
+	A         float64
+	B         float64
+}
+
+// alignPoints pairs points of two series ordered by timestamp whose timestamps differ at most tolerance seconds.
+func alignPoints(a, b []Point, tolerance int64) []alignedPair {
+	var pairs []alignedPair
+	j := 0
+	for _, p := range a {
+		for j < len(b) && b[j].Timestamp < p.Timestamp-tolerance {
+			j++
+		}
+		if j == len(b) {
+			break
+		}
+		best := j
+		if j+1 < len(b) && abs64(b[j+1].Timestamp-p.Timestamp) < abs64(b[j].Timestamp-p.Timestamp) {
+			best = j + 1
+		}
+		if abs64(b[best].Timestamp-p.Timestamp) <= tolerance {
+			pairs = append(pairs, alignedPair{Timestamp: p.Timestamp, A: p.Value, B: b[best].Value})
+		}
+	}
+	return pairs
+}
+
+// DetectDrift fits a line over differences of two series of the same metric, paired by timestamps within the
+// tolerance in seconds. Use long periods (weeks or months) of hourly or daily means for meaningful baselines.
+func DetectDrift(metric string, a, b []Point, tolerance int64) (*DriftReport, error) {
+	pairs := alignPoints(a, b, tolerance)
+	if len(pairs) < 2 {
+		return nil, errors.New("not enough aligned samples")
+	}
+	// Least squares fit of diff = intercept + slope * days since the first pair
+	t0 := pairs[0].Timestamp
+	var sumX, sumY, sumXX, sumXY float64
+	n := float64(len(pairs))
+	for _, p := range pairs {
+		x := float64(p.Timestamp-t0) / 86400
+		y := p.A - p.B
+		sumX += x
+		sumY += y
+		sumXX += x * x
+		sumXY += x * y
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return nil, errors.New("samples do not span time")
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+	meanY := sumY / n
+	var ssTotal, ssResidual float64
+	for _, p := range pairs {
+		x := float64(p.Timestamp-t0) / 86400
+		y := p.A - p.B
+		ssTotal += (y - meanY) * (y - meanY)
+		ssResidual += (y - intercept - slope*x) * (y - intercept - slope*x)
+	}
+	r2 := 0.0
+	if ssTotal > 0 {
+		r2 = 1 - ssResidual/ssTotal
+	}
+	last := pairs[len(pairs)-1].Timestamp
+	return &DriftReport{
+		Metric:      metric,
+		Samples:     len(pairs),
+		Begin:       t0,
+		End:         last,
+		MeanOffset:  meanY,
+		StartOffset: intercept,
+		EndOffset:   intercept + slope*float64(last-t0)/86400,
+		DriftPerDay: slope,
+		R2:          r2,
+	}, nil
+}
+
+// DetectStoredDrift compares hourly means of the metric between two modules (ex. two indoor modules in the same
+// room) from the store. Aggregates must have been compacted (see Compact).
+func DetectStoredDrift(ctx context.Context, store Store, metric string, a, b MeasureFilter) (*DriftReport, error) {
+	seriesA, err := QueryStore(ctx, store, Query{Account: a.Account, DeviceID: a.DeviceID, ModuleID: a.ModuleID,
+		Metrics: []string{metric}, Begin: a.Begin, End: a.End, Resolution: ResolutionHourly})
+	if err != nil {
+		return nil, err
+	}
+	seriesB, err := QueryStore(ctx, store, Query{Account: b.Account, DeviceID: b.DeviceID, ModuleID: b.ModuleID,
+		Metrics: []string{metric}, Begin: b.Begin, End: b.End, Resolution: ResolutionHourly})
+	if err != nil {
+		return nil, err
+	}
+	if len(seriesA) != 1 || len(seriesB) != 1 {
+		return nil, fmt.Errorf("filters must select exactly one module with %s data", metric)
+	}
+	return DetectDrift(metric, seriesA[0].Points, seriesB[0].Points, int64(time.Hour/time.Second)/2)
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,71 @@
+package netatmo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// DryRunSampleLines defines number of payload lines written by dry runs.
+const DryRunSampleLines = 3
+
+type dryRunKey struct{}
+
+// dryRunLog serializes dry run descriptions of concurrent writers.
+type dryRunLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// ContextWithDryRun returns context making sinks, uploaders and file stores of this package describe what they
+// would send (target, counts and the first DryRunSampleLines lines of the payload) to the writer instead of
+// sending it. Secrets in payloads, such as station keys, are masked.
+func ContextWithDryRun(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, &dryRunLog{w: w})
+}
+
+// IsDryRun reports whether the context is made by ContextWithDryRun.
+func IsDryRun(ctx context.Context) bool {
+	_, ok := ctx.Value(dryRunKey{}).(*dryRunLog)
+	return ok
+}
+
+// dryRun describes the payload if the context is a dry run, and reports whether it is.
+func dryRun(ctx context.Context, target, summary, payload string) bool {
+	log, ok := ctx.Value(dryRunKey{}).(*dryRunLog)
+	if !ok {
+		return false
+	}
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	fmt.Fprintf(log.w, "dry run: %s: %s\n", target, summary)
+	lines := strings.Split(strings.TrimSuffix(payload, "\n"), "\n")
+	if payload == "" {
+		lines = nil
+	}
+	for i, line := range lines {
+		if i == DryRunSampleLines {
+			fmt.Fprintf(log.w, "  ... %d more lines\n", len(lines)-i)
+			break
+		}
+		fmt.Fprintf(log.w, "  %s\n", line)
+	}
+	return true
+}
+
+// maskQuery returns the encoded query with values of the keys replaced by asterisks.
+func maskQuery(query url.Values, keys ...string) string {
+	masked := url.Values{}
+	for k, v := range query {
+		masked[k] = v
+	}
+	for _, k := range keys {
+		if masked.Get(k) != "" {
+			masked.Set(k, "***")
+		}
+	}
+	return strings.Replace(masked.Encode(), "%2A%2A%2A", "***", -1)
+}
@@ -0,0 +1,113 @@
+package netatmo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ElasticsearchSink bulk-indexes measures into Elasticsearch or OpenSearch, one document per measure in an index
+// per month (ex. netatmo-2024.01). Document IDs are derived from device, module and timestamp, so rewriting the same
+// measures does not duplicate documents.
+type ElasticsearchSink struct {
+	URL         string // Base URL (ex. http://localhost:9200)
+	IndexPrefix string // Defaults to "netatmo-"
+	Username    string // Basic authentication, optional
+	Password    string
+	APIKey      string       // Encoded API key, optional, used instead of basic authentication
+	HTTPClient  *http.Client // Defaults to http.DefaultClient
+}
+
+// Write indexes the measures with a single bulk request.
+func (s *ElasticsearchSink) Write(ctx context.Context, measures []Measure) error {
+	if len(measures) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for i := range measures {
+		m := &measures[i]
+		action := map[string]interface{}{"index": map[string]string{"_index": s.index(m.Timestamp),
+			"_id": m.DeviceID + "/" + m.ModuleID + "/" + fmt.Sprint(m.Timestamp)}}
+		if err := encoder.Encode(action); err != nil {
+			return err
+		}
+		if err := encoder.Encode(elasticsearchDocument(m)); err != nil {
+			return err
+		}
+	}
+	if dryRun(ctx, "elasticsearch", fmt.Sprintf("%d documents to %s", len(measures), s.URL), body.String()) {
+		return nil
+	}
+	data, err := sinkPost(ctx, s.HTTPClient, strings.TrimSuffix(s.URL, "/")+"/_bulk", "application/x-ndjson",
+		&body, s.authorize)
+	if err != nil {
+		return fmt.Errorf("elasticsearch: %w", err)
+	}
+	var resp struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int             `json:"status"`
+			Error  json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("elasticsearch: %w", err)
+	}
+	if !resp.Errors {
+		return nil
+	}
+	failed, first := 0, ""
+	for _, item := range resp.Items {
+		for _, result := range item {
+			if result.Status/100 != 2 {
+				if failed == 0 {
+					first = string(result.Error)
+				}
+				failed++
+			}
+		}
+	}
+	return fmt.Errorf("elasticsearch: %d of %d documents failed, first error: %s", failed, len(measures), first)
+}
+
+// index returns name of the monthly index of the unix time.
+func (s *ElasticsearchSink) index(timestamp int64) string {
+	prefix := s.IndexPrefix
+	if prefix == "" {
+		prefix = "netatmo-"
+	}
+	return prefix + time.Unix(timestamp, 0).UTC().Format("2006.01")
+}
+
+func (s *ElasticsearchSink) authorize(req *http.Request) {
+	switch {
+	case s.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+s.APIKey)
+	case s.Username != "":
+		req.SetBasicAuth(s.Username, s.Password)
+	}
+}
+
+// elasticsearchDocument returns the document of the measure with field names following the Elastic Common Schema
+// where applicable (@timestamp, observer.*, event.*); measurements are under netatmo.* in snake case
+// (ex. netatmo.wind_strength, netatmo.min_temp).
+func elasticsearchDocument(m *Measure) map[string]interface{} {
+	values := map[string]interface{}{"device_id": m.DeviceID, "module_id": m.ModuleID}
+	if m.Account != "" {
+		values["account"] = m.Account
+	}
+	for _, v := range sinkValues(m) {
+		values[snakeCase(v.Name)] = v.Value
+	}
+	return map[string]interface{}{
+		"@timestamp": time.Unix(m.Timestamp, 0).UTC().Format(time.RFC3339),
+		"event":      map[string]string{"kind": "metric", "module": "netatmo", "dataset": "netatmo.measure"},
+		"observer":   map[string]string{"type": "weather-station", "vendor": "Netatmo", "serial_number": m.DeviceID},
+		"netatmo":    values,
+	}
+}
@@ -0,0 +1,94 @@
+package netatmo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Endpoint defines URLs of the Netatmo API and its OAuth2 server.
+type Endpoint struct {
+	APIURL   string // Base URL of API methods, ending with a slash (ex. https://api.netatmo.com/api/)
+	AuthURL  string // Authorization URL of the authorization code flow
+	TokenURL string
+	// Token revocation URL (RFC 7009), empty if the server has none; Netatmo documents none, so Client.Revoke only
+	// discards the token unless set
+	RevokeURL string
+}
+
+// Predefined endpoints.
+var (
+	// EndpointDefault is the current Netatmo API host.
+	EndpointDefault = EndpointAt("https://api.netatmo.com")
+	// EndpointLegacy is the former host, still answering for older applications.
+	EndpointLegacy = EndpointAt("https://api.netatmo.net")
+)
+
+// EndpointProfiles defines endpoints selectable by name (see LookupEndpoint).
+var EndpointProfiles = map[string]Endpoint{
+	"default": EndpointDefault,
+	"legacy":  EndpointLegacy,
+}
+
+// EndpointAt returns endpoint of a host serving the Netatmo API paths, such as a mirror or a reverse proxy
+// (ex. https://netatmo.example.com -> https://netatmo.example.com/api/ and https://netatmo.example.com/oauth2/token).
+func EndpointAt(baseURL string) Endpoint {
+	base := strings.TrimSuffix(baseURL, "/")
+	return Endpoint{
+		APIURL:   base + "/api/",
+		AuthURL:  base + "/oauth2/authorize",
+		TokenURL: base + "/oauth2/token",
+	}
+}
+
+// LookupEndpoint returns endpoint of a profile name (default, legacy) or of a custom base URL (see EndpointAt).
+func LookupEndpoint(profile string) (Endpoint, error) {
+	if e, ok := EndpointProfiles[profile]; ok {
+		return e, nil
+	}
+	if strings.HasPrefix(profile, "https://") || strings.HasPrefix(profile, "http://") {
+		return EndpointAt(profile), nil
+	}
+	return Endpoint{}, fmt.Errorf("unknown endpoint profile: %s", profile)
+}
+
+// WithEndpoint sends API and token requests to the endpoint instead of EndpointDefault. Empty URLs of the endpoint
+// fall back to EndpointDefault.
+func WithEndpoint(e Endpoint) Option {
+	return func(o *clientOptions) {
+		o.endpoint = e
+	}
+}
+
+// WithBaseURL sends API requests to the base URL of API methods (ex. https://gateway.example.com/netatmo/api/), such
+// as an API gateway or a test server, keeping the token URL of the endpoint. Overrides APIURL of WithEndpoint if
+// passed after it.
+func WithBaseURL(apiURL string) Option {
+	return func(o *clientOptions) {
+		o.endpoint.APIURL = apiURL
+	}
+}
+
+// WithTokenURL sends token requests to the URL (ex. https://gateway.example.com/netatmo/oauth2/token). Overrides
+// TokenURL of WithEndpoint if passed after it.
+func WithTokenURL(tokenURL string) Option {
+	return func(o *clientOptions) {
+		o.endpoint.TokenURL = tokenURL
+	}
+}
+
+// withDefaults fills empty URLs of the endpoint from EndpointDefault.
+func (e Endpoint) withDefaults() Endpoint {
+	if e.APIURL == "" {
+		e.APIURL = EndpointDefault.APIURL
+	}
+	if !strings.HasSuffix(e.APIURL, "/") {
+		e.APIURL += "/"
+	}
+	if e.AuthURL == "" {
+		e.AuthURL = EndpointDefault.AuthURL
+	}
+	if e.TokenURL == "" {
+		e.TokenURL = EndpointDefault.TokenURL
+	}
+	return e
+}
@@ -0,0 +1,147 @@
+package netatmo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExecSink streams measures as NDJSON (one JSON encoded Measure per line) to the standard input of an external
+// program, so proprietary systems can be integrated without modifying this package. The program is started on the
+// first write and restarted on the next write after it exits; it should read lines until EOF.
+type ExecSink struct {
+	Command string
+	Args    []string
+	Env     []string  // Added to the environment of the current process (ex. "API_KEY=...")
+	Dir     string    // Working directory, defaults to the current one
+	Stdout  io.Writer // Output of the program, discarded if nil
+	Stderr  io.Writer // Errors of the program, defaults to os.Stderr
+
+	mu   sync.Mutex
+	proc *execProcess // Nil until started
+}
+
+// execStopTimeout defines how long a program failing to read its input is given to exit before it is killed.
+const execStopTimeout = 5 * time.Second
+
+// execProcess defines a started program of ExecSink.
+type execProcess struct {
+	stdin   io.WriteCloser
+	process *os.Process
+	done    chan struct{} // Closed when the program exits, after err is set
+	err     error         // Exit error
+}
+
+// stop closes the standard input and waits for the program to exit, killing it after execStopTimeout, and returns
+// the exit error.
+func (p *execProcess) stop() error {
+	_ = p.stdin.Close()
+	select {
+	case <-p.done:
+	case <-time.After(execStopTimeout):
+		_ = p.process.Kill()
+		<-p.done
+	}
+	return p.err
+}
+
+// exited reports whether the program exited.
+func (p *execProcess) exited() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Write sends the measures to the program, starting it if not running.
+func (s *ExecSink) Write(ctx context.Context, measures []Measure) error {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	for i := range measures {
+		if err := enc.Encode(&measures[i]); err != nil {
+			return err
+		}
+	}
+	if dryRun(ctx, "exec", fmt.Sprintf("%d measures to %s", len(measures), s.commandLine()), b.String()) {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.proc == nil || s.proc.exited() {
+		proc, err := s.start()
+		if err != nil {
+			return fmt.Errorf("exec %s: %w", s.Command, err)
+		}
+		s.proc = proc
+	}
+	if _, err := s.proc.stdin.Write(b.Bytes()); err != nil {
+		// Not reused: the next write starts the program again
+		proc := s.proc
+		s.proc = nil
+		if exitErr := proc.stop(); exitErr != nil {
+			return fmt.Errorf("exec %s: %w (%w)", s.Command, err, exitErr)
+		}
+		return fmt.Errorf("exec %s: %w", s.Command, err)
+	}
+	return nil
+}
+
+// Close closes the standard input of the program and waits for it to exit.
+func (s *ExecSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	proc := s.proc
+	if proc == nil {
+		return nil
+	}
+	s.proc = nil
+	_ = proc.stdin.Close()
+	<-proc.done
+	if proc.err != nil {
+		return fmt.Errorf("exec %s: %w", s.Command, proc.err)
+	}
+	return nil
+}
+
+func (s *ExecSink) start() (*execProcess, error) {
+	if s.Command == "" {
+		return nil, errors.New("no command")
+	}
+	cmd := exec.Command(s.Command, s.Args...)
+	cmd.Dir = s.Dir
+	if len(s.Env) > 0 {
+		cmd.Env = append(os.Environ(), s.Env...)
+	}
+	cmd.Stdout = s.Stdout
+	cmd.Stderr = s.Stderr
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	proc := &execProcess{stdin: stdin, process: cmd.Process, done: make(chan struct{})}
+	go func() {
+		proc.err = cmd.Wait()
+		close(proc.done)
+	}()
+	return proc, nil
+}
+
+func (s *ExecSink) commandLine() string {
+	return strings.Join(append([]string{s.Command}, s.Args...), " ")
+}
@@ -0,0 +1,168 @@
+package netatmo
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// extremeMinFields and extremeMaxFields map measurements to aggregate fields holding their minimum and maximum over
+// a step, considered in addition to the measurement itself.
+var (
+	extremeMinFields = map[string]string{"Temperature": "min_temp", "Humidity": "min_hum",
+		"Pressure": "min_pressure", "Noise": "min_noise"}
+	extremeMaxFields = map[string]string{"Temperature": "max_temp", "Humidity": "max_hum",
+		"Pressure": "max_pressure", "Noise": "max_noise"}
+)
+
+// Extreme defines a record value of a measurement and when it was measured.
+type Extreme struct {
+	Value     float64 `json:"value"`
+	Timestamp int64   `json:"timestamp"` // Unix time of the measure, or of the gust for aggregated gusts
+}
+
+// Extremes defines minimum and maximum values of each measurement of a module over a window.
+type Extremes struct {
+	Account  string             `json:"account,omitempty"`
+	DeviceID string             `json:"device_id"`
+	ModuleID string             `json:"module_id"`
+	Begin    int64              `json:"begin"` // Inclusive unix time
+	End      int64              `json:"end"`   // Inclusive unix time
+	Min      map[string]Extreme `json:"min"`   // Keyed by measurement name (ex. Temperature), absent without values
+	Max      map[string]Extreme `json:"max"`
+	SumRain  *float64           `json:"sum_rain,omitempty"` // Nullable, total rain in mm
+}
+
+// MinTemperature returns the lowest temperature.
+func (e *Extremes) MinTemperature() (Extreme, bool) {
+	x, ok := e.Min["Temperature"]
+	return x, ok
+}
+
+// MaxTemperature returns the highest temperature.
+func (e *Extremes) MaxTemperature() (Extreme, bool) {
+	x, ok := e.Max["Temperature"]
+	return x, ok
+}
+
+// MaxGust returns the strongest gust.
+func (e *Extremes) MaxGust() (Extreme, bool) {
+	x, ok := e.Max["GustStrength"]
+	return x, ok
+}
+
+// add takes values of the measure into account.
+func (e *Extremes) add(m *Measure) {
+	for _, metric := range TargetMeasurements {
+		if v, ok := m.Value(metric); ok {
+			e.record(metric, v, m.Timestamp)
+		}
+		if v, ok := m.Value(extremeMinFields[metric]); ok {
+			e.record(metric, v, m.Timestamp)
+		}
+		if v, ok := m.Value(extremeMaxFields[metric]); ok {
+			e.record(metric, v, m.Timestamp)
+		}
+	}
+	if m.GustStrength != nil && m.MaxGustTime != nil {
+		if x := e.Max["GustStrength"]; x.Value == float64(*m.GustStrength) && x.Timestamp == m.Timestamp {
+			e.Max["GustStrength"] = Extreme{Value: x.Value, Timestamp: *m.MaxGustTime}
+		}
+	}
+	if v := m.rainAmount(); v != nil {
+		total := *v
+		if e.SumRain != nil {
+			total += *e.SumRain
+		}
+		e.SumRain = &total
+	}
+}
+
+// record keeps the value if it is a new minimum or maximum. Ties keep the earliest time.
+func (e *Extremes) record(metric string, v float64, timestamp int64) {
+	if x, ok := e.Min[metric]; !ok || v < x.Value || (v == x.Value && timestamp < x.Timestamp) {
+		e.Min[metric] = Extreme{Value: v, Timestamp: timestamp}
+	}
+	if x, ok := e.Max[metric]; !ok || v > x.Value || (v == x.Value && timestamp < x.Timestamp) {
+		e.Max[metric] = Extreme{Value: v, Timestamp: timestamp}
+	}
+}
+
+// ComputeExtremes returns extremes of each module of the measures, ordered by account, device and module. Windows
+// span the first and the last measure of the module. Aggregate values (ex. min_temp) are taken into account, and
+// strongest gusts of aggregated measures are timed by date_max_gust.
+func ComputeExtremes(measures []Measure) []Extremes {
+	groups := make(map[extremesKey]*Extremes)
+	for i := range measures {
+		m := &measures[i]
+		k := extremesKey{account: m.Account, deviceID: m.DeviceID, moduleID: m.ModuleID}
+		e := groups[k]
+		if e == nil {
+			e = &Extremes{Account: m.Account, DeviceID: m.DeviceID, ModuleID: m.ModuleID, Begin: m.Timestamp,
+				End: m.Timestamp, Min: make(map[string]Extreme), Max: make(map[string]Extreme)}
+			groups[k] = e
+		}
+		e.Begin, e.End = min(e.Begin, m.Timestamp), max(e.End, m.Timestamp)
+		e.add(m)
+	}
+	return sortedExtremes(groups)
+}
+
+// extremesKey identifies a module and the start of a period.
+type extremesKey struct {
+	account, deviceID, moduleID string
+	start                       int64
+}
+
+// ExtremesByPeriod returns extremes of each module and calendar period (ex. monthly or annual records) of the
+// measures in the location, ordered by account, device, module and period. Windows span the whole periods.
+func ExtremesByPeriod(measures []Measure, period Period, location *time.Location) ([]Extremes, error) {
+	groups := make(map[extremesKey]*Extremes)
+	for i := range measures {
+		m := &measures[i]
+		start, err := period.Start(time.Unix(m.Timestamp, 0).In(location))
+		if err != nil {
+			return nil, err
+		}
+		k := extremesKey{account: m.Account, deviceID: m.DeviceID, moduleID: m.ModuleID, start: start.Unix()}
+		e := groups[k]
+		if e == nil {
+			e = &Extremes{Account: m.Account, DeviceID: m.DeviceID, ModuleID: m.ModuleID, Begin: start.Unix(),
+				End: period.Add(start, 1).Unix() - 1, Min: make(map[string]Extreme), Max: make(map[string]Extreme)}
+			groups[k] = e
+		}
+		e.add(m)
+	}
+	return sortedExtremes(groups), nil
+}
+
+// QueryExtremes returns extremes of each module of stored measures matching the filter (see ComputeExtremes).
+func QueryExtremes(ctx context.Context, store Store, filter MeasureFilter) ([]Extremes, error) {
+	measures, err := store.Measures(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return ComputeExtremes(measures), nil
+}
+
+// sortedExtremes returns the extremes ordered by account, device, module and window.
+func sortedExtremes(groups map[extremesKey]*Extremes) []Extremes {
+	extremes := make([]Extremes, 0, len(groups))
+	for _, e := range groups {
+		extremes = append(extremes, *e)
+	}
+	sort.Slice(extremes, func(i, j int) bool {
+		a, b := &extremes[i], &extremes[j]
+		if a.Account != b.Account {
+			return a.Account < b.Account
+		}
+		if a.DeviceID != b.DeviceID {
+			return a.DeviceID < b.DeviceID
+		}
+		if a.ModuleID != b.ModuleID {
+			return a.ModuleID < b.ModuleID
+		}
+		return a.Begin < b.Begin
+	})
+	return extremes
+}
@@ -0,0 +1,142 @@
+package netatmo
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// FileStore implements Store on a single archive file. The whole data set is kept in memory and the file is
+// rewritten in the archive format after each modification, so it suits a single station's history.
+type FileStore struct {
+	path string
+	mu   sync.Mutex // Serializes modifications and file writes
+	mem  *MemoryStore
+}
+
+// OpenFileStore opens the store backed by the file. A missing file is created on first modification.
+func OpenFileStore(ctx context.Context, path string) (*FileStore, error) {
+	s := &FileStore{path: path, mem: NewMemoryStore()}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := Import(ctx, f, s.mem); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Path returns the backing file path.
+func (s *FileStore) Path() string {
+	return s.path
+}
+
+// Write inserts measures, replacing existing ones with the same account, device, module and timestamp.
+func (s *FileStore) Write(ctx context.Context, measures []Measure) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.mem.Write(ctx, measures); err != nil {
+		return err
+	}
+	return s.save(ctx)
+}
+
+// Measures returns measures matching the filter, ordered by account, device, module and timestamp.
+func (s *FileStore) Measures(ctx context.Context, filter MeasureFilter) ([]Measure, error) {
+	return s.mem.Measures(ctx, filter)
+}
+
+// NewestMeasure returns the newest measure matching the filter, nil if none.
+func (s *FileStore) NewestMeasure(ctx context.Context, filter MeasureFilter) (*Measure, error) {
+	return s.mem.NewestMeasure(ctx, filter)
+}
+
+// DeleteMeasures deletes measures matching the filter and returns number of deleted measures.
+func (s *FileStore) DeleteMeasures(ctx context.Context, filter MeasureFilter) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted, err := s.mem.DeleteMeasures(ctx, filter)
+	if err != nil || deleted == 0 {
+		return deleted, err
+	}
+	return deleted, s.save(ctx)
+}
+
+// WriteAggregates inserts aggregates, replacing existing ones with the same account, device, module,
+// resolution and timestamp.
+func (s *FileStore) WriteAggregates(ctx context.Context, aggregates []Aggregate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.mem.WriteAggregates(ctx, aggregates); err != nil {
+		return err
+	}
+	return s.save(ctx)
+}
+
+// Aggregates returns aggregates of the resolution matching the filter, ordered by account, device, module and
+// timestamp.
+func (s *FileStore) Aggregates(ctx context.Context, res Resolution, filter MeasureFilter) ([]Aggregate, error) {
+	return s.mem.Aggregates(ctx, res, filter)
+}
+
+// DeleteAggregates deletes aggregates of the resolution matching the filter and returns number of deleted
+// aggregates.
+func (s *FileStore) DeleteAggregates(ctx context.Context, res Resolution, filter MeasureFilter) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted, err := s.mem.DeleteAggregates(ctx, res, filter)
+	if err != nil || deleted == 0 {
+		return deleted, err
+	}
+	return deleted, s.save(ctx)
+}
+
+// PutDevices inserts devices, replacing existing ones with the same account and ID.
+func (s *FileStore) PutDevices(ctx context.Context, devices []Device) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.mem.PutDevices(ctx, devices); err != nil {
+		return err
+	}
+	return s.save(ctx)
+}
+
+// Devices returns all stored devices ordered by account and ID.
+func (s *FileStore) Devices(ctx context.Context) ([]Device, error) {
+	return s.mem.Devices(ctx)
+}
+
+// save writes the whole data set into a temporary file and renames it over the backing file.
+func (s *FileStore) save(ctx context.Context) error {
+	if IsDryRun(ctx) {
+		manifest, err := Export(ctx, ioutil.Discard, s.mem, MeasureFilter{})
+		if err != nil {
+			return err
+		}
+		dryRun(ctx, "file store", fmt.Sprintf("%d devices, %d measures and %d aggregates to %s", manifest.Devices,
+			manifest.Measures, manifest.Aggregates, s.path), "")
+		return nil
+	}
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := Export(ctx, f, s.mem, MeasureFilter{}); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
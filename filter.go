@@ -0,0 +1,196 @@
+package netatmo
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxSpikeInterval defines longest interval between samples compared by spike detection.
+const maxSpikeInterval = 15 * time.Minute
+
+// Filter reasons.
+const (
+	FilterReasonRange = "range" // Physically impossible value
+	FilterReasonSpike = "spike" // Jump from the previous sample
+	FilterReasonStuck = "stuck" // Same value for too many samples
+)
+
+// FilterAction defines handling of values failing a check.
+type FilterAction int
+
+// Supported actions.
+const (
+	FilterDrop FilterAction = iota // Set the value to null
+	FilterFlag                     // Keep the value and record the reason in Measure.Flagged
+)
+
+// Range defines inclusive range of valid values.
+type Range struct {
+	Min float64
+	Max float64
+}
+
+// DefaultValidRanges defines physically possible values of measurements.
+var DefaultValidRanges = map[string]Range{
+	"Temperature":  {Min: -60, Max: 60},
+	"CO2":          {Min: 0, Max: 10000},
+	"Humidity":     {Min: 0, Max: 100},
+	"Pressure":     {Min: 800, Max: 1100},
+	"Noise":        {Min: 0, Max: 140},
+	"WindStrength": {Min: 0, Max: 300},
+	"WindAngle":    {Min: 0, Max: 360},
+	"GustStrength": {Min: 0, Max: 300},
+	"GustAngle":    {Min: 0, Max: 360},
+	"Rain":         {Min: 0, Max: 150},
+}
+
+// DefaultSpikeThresholds defines largest plausible changes between consecutive samples.
+var DefaultSpikeThresholds = map[string]float64{
+	"Temperature": 5,
+	"Humidity":    20,
+	"Pressure":    5,
+	"CO2":         1500,
+}
+
+// DefaultStuckMetrics defines measurements expected to vary, checked for stuck sensors.
+var DefaultStuckMetrics = []string{"Temperature", "Humidity", "Pressure"}
+
+// FilterConfig defines checks of FilterSink. Nil maps and slices use the defaults; empty ones disable the check.
+type FilterConfig struct {
+	Action          FilterAction
+	ValidRanges     map[string]Range   // Defaults to DefaultValidRanges
+	SpikeThresholds map[string]float64 // Defaults to DefaultSpikeThresholds
+	StuckMetrics    []string           // Defaults to DefaultStuckMetrics
+	StuckSamples    int                // Identical consecutive samples treated as stuck, defaults to 36 (3 hours)
+}
+
+// FilterStats defines statistics of FilterSink.
+type FilterStats struct {
+	Checked  int64            // Number of measures checked
+	Dropped  int64            // Number of values set to null
+	Flagged  int64            // Number of values flagged
+	ByReason map[string]int64 // Number of failed values by reason
+}
+
+type filterState struct {
+	last      float64 // Last accepted value
+	lastTime  int64
+	hasLast   bool
+	repeated  int // Number of consecutive samples equal to last
+	lastValue float64
+}
+
+// FilterSink checks measures for junk values before forwarding them to the underlying sink.
+type FilterSink struct {
+	sink   Sink
+	config FilterConfig
+	mu     sync.Mutex
+	states map[string]*filterState // Keyed by module and measurement
+	stats  FilterStats
+}
+
+// NewFilterSink creates filtering sink in front of the specified sink.
+func NewFilterSink(sink Sink, config FilterConfig) *FilterSink {
+	if config.ValidRanges == nil {
+		config.ValidRanges = DefaultValidRanges
+	}
+	if config.SpikeThresholds == nil {
+		config.SpikeThresholds = DefaultSpikeThresholds
+	}
+	if config.StuckMetrics == nil {
+		config.StuckMetrics = DefaultStuckMetrics
+	}
+	if config.StuckSamples <= 0 {
+		config.StuckSamples = 36
+	}
+	return &FilterSink{sink: sink, config: config, states: make(map[string]*filterState),
+		stats: FilterStats{ByReason: make(map[string]int64)}}
+}
+
+// Write checks the measures and forwards them. The measures are not modified; checked copies are forwarded.
+func (f *FilterSink) Write(ctx context.Context, measures []Measure) error {
+	f.mu.Lock()
+	checked := append([]Measure{}, measures...)
+	sort.SliceStable(checked, func(i, j int) bool {
+		if checked[i].ModuleID != checked[j].ModuleID {
+			return checked[i].ModuleID < checked[j].ModuleID
+		}
+		return checked[i].Timestamp < checked[j].Timestamp
+	})
+	for i := range checked {
+		f.check(&checked[i])
+	}
+	f.mu.Unlock()
+	return f.sink.Write(ctx, checked)
+}
+
+// Stats returns snapshot of the filter statistics.
+func (f *FilterSink) Stats() FilterStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stats := f.stats
+	stats.ByReason = make(map[string]int64, len(f.stats.ByReason))
+	for k, v := range f.stats.ByReason {
+		stats.ByReason[k] = v
+	}
+	return stats
+}
+
+func (f *FilterSink) check(m *Measure) {
+	f.stats.Checked++
+	for _, name := range TargetMeasurements {
+		v, ok := m.Value(name)
+		if !ok {
+			continue
+		}
+		key := m.DeviceID + "\x00" + m.ModuleID + "\x00" + name
+		state := f.states[key]
+		if state == nil {
+			state = &filterState{}
+			f.states[key] = state
+		}
+		reason := f.reason(name, v, m.Timestamp, state)
+		if reason == "" {
+			state.last, state.lastTime, state.hasLast = v, m.Timestamp, true
+			continue
+		}
+		f.stats.ByReason[reason]++
+		if f.config.Action == FilterFlag {
+			if m.Flagged == nil {
+				m.Flagged = make(map[string]string)
+			}
+			m.Flagged[name] = reason
+			f.stats.Flagged++
+		} else {
+			m.clearValue(name)
+			f.stats.Dropped++
+		}
+	}
+}
+
+// reason returns why the value fails the checks, or empty string if it passes.
+func (f *FilterSink) reason(name string, v float64, timestamp int64, state *filterState) string {
+	if r, ok := f.config.ValidRanges[name]; ok && (v < r.Min || v > r.Max) {
+		return FilterReasonRange
+	}
+	if sliceContains(f.config.StuckMetrics, name) {
+		if state.repeated > 0 && v == state.lastValue {
+			state.repeated++
+		} else {
+			state.repeated, state.lastValue = 1, v
+		}
+		if state.repeated >= f.config.StuckSamples {
+			return FilterReasonStuck
+		}
+	}
+	if threshold, ok := f.config.SpikeThresholds[name]; ok && state.hasLast {
+		interval := time.Duration(timestamp-state.lastTime) * time.Second
+		if interval <= maxSpikeInterval && math.Abs(v-state.last) > threshold {
+			return FilterReasonSpike
+		}
+	}
+	return ""
+}
@@ -0,0 +1,156 @@
+package netatmo
+
+import (
+	"strconv"
+	"strings"
+)
+
+// beaufortLimits defines upper wind speeds in km/h of Beaufort numbers 0 to 11.
+var beaufortLimits = []float64{1, 6, 12, 20, 29, 39, 50, 62, 75, 89, 103, 118}
+
+// FormatOptions defines units and locale of formatted values. The zero value formats metric units in DefaultLocale.
+type FormatOptions struct {
+	Unit         int    // 0 -> metric (°C, mm), 1 -> imperial (°F, in), as Administrative.Unit
+	WindUnit     int    // 0 -> km/h, 1 -> mph, 2 -> m/s, 3 -> Beaufort, 4 -> knots, as Administrative.WindUnit
+	PressureUnit int    // 0 -> hPa (mbar), 1 -> inHg, 2 -> mmHg, as Administrative.PressureUnit
+	Locale       string // Decides the decimal separator (ex. "21,4 °C" in fr), DefaultLocale if empty
+}
+
+// FormatOptionsOf returns options following the user regional preferences.
+func FormatOptionsOf(a *Administrative) FormatOptions {
+	return FormatOptions{Unit: a.Unit, WindUnit: a.WindUnit, PressureUnit: a.PressureUnit, Locale: a.Locale()}
+}
+
+// FormatMeasure formats available values of the measure (ex. "21.4 °C, 48 %, 612 ppm, 1013.2 hPa").
+func FormatMeasure(m *Measure, opts FormatOptions) string {
+	return opts.join(
+		opts.temperature(m.Temperature),
+		opts.integer(m.Humidity, "%"),
+		opts.integer(m.CO2, "ppm"),
+		opts.pressure(m.Pressure),
+		opts.integer(m.Noise, "dB"),
+		opts.rain(m.rainAmount()),
+		opts.wind(m.WindStrength, m.WindAngle),
+		opts.gust(m.GustStrength, m.GustAngle),
+	)
+}
+
+// FormatDashboardData formats available current values of the dashboard data (ex. "21.4 °C, 48 %, 612 ppm,
+// 1013.2 hPa").
+func FormatDashboardData(d *DashboardData, opts FormatOptions) string {
+	return opts.join(
+		opts.temperature(d.Temperature),
+		opts.integer(d.Humidity, "%"),
+		opts.integer(d.CO2, "ppm"),
+		opts.pressure(d.Pressure),
+		opts.integer(d.Noise, "dB"),
+		opts.rain(d.Rain),
+		opts.wind(d.WindStrength, d.WindAngle),
+		opts.gust(d.GustStrength, d.GustAngle),
+	)
+}
+
+func (o FormatOptions) join(values ...string) string {
+	var parts []string
+	for _, v := range values {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// number formats the value with the decimals and the decimal separator of the locale.
+func (o FormatOptions) number(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	switch strings.SplitN(normalizeLocale(o.Locale), "-", 2)[0] {
+	case "fr", "de", "es", "it", "pt", "nl", "ru", "pl", "sv", "da", "nb", "fi", "cs":
+		s = strings.Replace(s, ".", ",", 1)
+	}
+	return s
+}
+
+func (o FormatOptions) temperature(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	if o.Unit == 1 {
+		return o.number(celsiusToFahrenheit(*v), 1) + " °F"
+	}
+	return o.number(*v, 1) + " °C"
+}
+
+func (o FormatOptions) integer(v *int, unit string) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v) + " " + unit
+}
+
+func (o FormatOptions) pressure(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	switch o.PressureUnit {
+	case 1:
+		return o.number(hPaToInHg(*v), 2) + " inHg"
+	case 2:
+		return o.number(*v*0.750062, 0) + " mmHg"
+	default:
+		return o.number(*v, 1) + " hPa"
+	}
+}
+
+func (o FormatOptions) rain(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	if o.Unit == 1 {
+		return o.number(mmToInch(*v), 2) + " in"
+	}
+	return o.number(*v, 1) + " mm"
+}
+
+func (o FormatOptions) speed(kmh int) string {
+	v := float64(kmh)
+	switch o.WindUnit {
+	case 1:
+		return o.number(kmhToMph(v), 0) + " mph"
+	case 2:
+		return o.number(kmhToMs(v), 1) + " m/s"
+	case 3:
+		return strconv.Itoa(beaufort(v)) + " Bft"
+	case 4:
+		return strconv.Itoa(knots(kmh)) + " kn"
+	default:
+		return strconv.Itoa(kmh) + " km/h"
+	}
+}
+
+func (o FormatOptions) wind(strength, angle *int) string {
+	if strength == nil {
+		return ""
+	}
+	s := o.speed(*strength)
+	if angle != nil && *strength > 0 {
+		s += " " + CompassDirection(*angle)
+	}
+	return s
+}
+
+func (o FormatOptions) gust(strength, angle *int) string {
+	if strength == nil {
+		return ""
+	}
+	return Translate(o.Locale, "format.gust", o.wind(strength, angle))
+}
+
+// beaufort converts wind speed in km/h to Beaufort number.
+func beaufort(kmh float64) int {
+	for i, limit := range beaufortLimits {
+		if kmh < limit {
+			return i
+		}
+	}
+	return 12
+}
@@ -0,0 +1,82 @@
+package netatmo
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// geoJSONProperty defines an observation value rendered as a GeoJSON feature property.
+type geoJSONProperty struct {
+	name  string // Property name with unit suffix (ex. temperature_c)
+	value func(o *Observation) *float64
+}
+
+var geoJSONProperties = []geoJSONProperty{
+	{"temperature_c", func(o *Observation) *float64 { return o.Temperature }},
+	{"humidity_percent", func(o *Observation) *float64 { return o.Humidity }},
+	{"dew_point_c", func(o *Observation) *float64 { return o.DewPoint }},
+	{"pressure_hpa", func(o *Observation) *float64 { return o.Pressure }},
+	{"wind_speed_kmh", func(o *Observation) *float64 { return o.WindSpeed }},
+	{"wind_angle_deg", func(o *Observation) *float64 { return o.WindAngle }},
+	{"gust_speed_kmh", func(o *Observation) *float64 { return o.GustSpeed }},
+	{"gust_angle_deg", func(o *Observation) *float64 { return o.GustAngle }},
+	{"rain_1h_mm", func(o *Observation) *float64 { return o.RainLastHour }},
+	{"rain_today_mm", func(o *Observation) *float64 { return o.RainToday }},
+	{"indoor_temperature_c", func(o *Observation) *float64 { return o.IndoorTemperature }},
+	{"indoor_humidity_percent", func(o *Observation) *float64 { return o.IndoorHumidity }},
+	{"indoor_co2_ppm", func(o *Observation) *float64 { return o.IndoorCO2 }},
+	{"indoor_noise_db", func(o *Observation) *float64 { return o.IndoorNoise }},
+}
+
+// MarshalGeoJSON renders the owned devices and the public stations (either may be nil) as a GeoJSON
+// FeatureCollection of points with the latest readings as properties, ready for Leaflet or Mapbox layers. Properties
+// are "kind" (owned or public), "id", "name", "time" (RFC 3339) and available values with unit suffixes
+// (ex. temperature_c, rain_1h_mm). Stations without location are omitted.
+func MarshalGeoJSON(devices []Device, public []PublicStation) ([]byte, error) {
+	features := []map[string]interface{}{}
+	for i := range devices {
+		d := &devices[i]
+		if len(d.Place.Location) != 2 {
+			continue
+		}
+		o := NewObservation(d)
+		properties := map[string]interface{}{"kind": "owned", "id": d.ID, "name": d.StationName}
+		for _, p := range geoJSONProperties {
+			if v := p.value(&o); v != nil {
+				properties[p.name] = *v
+			}
+		}
+		features = append(features, geoJSONFeature(o.Longitude, o.Latitude, o.Altitude, o.Time, properties))
+	}
+	for _, s := range public {
+		o := Observation{Temperature: s.Temperature, Pressure: s.Pressure, RainLastHour: s.Rain60Min,
+			WindSpeed: intToFloat(s.WindStrength), WindAngle: intToFloat(s.WindAngle),
+			GustSpeed: intToFloat(s.GustStrength), GustAngle: intToFloat(s.GustAngle), Humidity: intToFloat(s.Humidity),
+			DewPoint: dewPointOf(s.Temperature, s.Humidity)}
+		properties := map[string]interface{}{"kind": "public", "id": s.ID, "name": s.City}
+		for _, p := range geoJSONProperties {
+			if v := p.value(&o); v != nil {
+				properties[p.name] = *v
+			}
+		}
+		if s.Rain24H != nil {
+			properties["rain_24h_mm"] = *s.Rain24H
+		}
+		features = append(features, geoJSONFeature(s.Longitude, s.Latitude, s.Altitude, s.Timestamp, properties))
+	}
+	return json.Marshal(map[string]interface{}{"type": "FeatureCollection", "features": features})
+}
+
+// geoJSONFeature returns a point feature with the properties and the time.
+func geoJSONFeature(lon, lat float64, alt int, timestamp int64,
+	properties map[string]interface{}) map[string]interface{} {
+	if timestamp != 0 {
+		properties["time"] = time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+	}
+	return map[string]interface{}{
+		"type":       "Feature",
+		"id":         properties["id"],
+		"geometry":   map[string]interface{}{"type": "Point", "coordinates": []float64{lon, lat, float64(alt)}},
+		"properties": properties,
+	}
+}
@@ -1,5 +1,27 @@
 module github.com/mikan/netatmo-weather-go
 
-go 1.13
+go 1.21
 
-require golang.org/x/oauth2 v0.5.0
+require (
+	github.com/prometheus/client_golang v1.18.0
+	github.com/prometheus/common v0.45.0
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+	golang.org/x/image v0.15.0
+	golang.org/x/oauth2 v0.12.0
+	golang.org/x/term v0.15.0
+	google.golang.org/protobuf v1.31.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/matttproud/golang_protobuf_extensions/v2 v2.0.0 // indirect
+	github.com/prometheus/client_model v0.5.0 // indirect
+	github.com/prometheus/procfs v0.12.0 // indirect
+	golang.org/x/net v0.17.0 // indirect
+	golang.org/x/sys v0.16.0 // indirect
+	google.golang.org/appengine v1.6.7 // indirect
+)
@@ -0,0 +1,148 @@
+package netatmo
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Default thresholds of HealthChecker.
+const (
+	DefaultMaxFetchAge = 30 * time.Minute
+	DefaultLiveTimeout = time.Hour
+)
+
+// HealthStatus defines state of a long-running agent reported by HealthChecker.
+type HealthStatus struct {
+	Live                bool      `json:"live"`
+	Ready               bool      `json:"ready"`
+	Reasons             []string  `json:"reasons,omitempty"` // Why the agent is not live or ready
+	LastAttempt         time.Time `json:"last_attempt"`
+	LastSuccess         time.Time `json:"last_success"`
+	LastError           string    `json:"last_error,omitempty"`
+	FetchAge            float64   `json:"fetch_age_seconds,omitempty"` // Seconds since the last successful fetch
+	TokenValid          bool      `json:"token_valid"`
+	TokenExpiry         time.Time `json:"token_expiry"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+}
+
+// HealthChecker tracks fetches of a poller or a sync loop and serves Kubernetes style health endpoints: the agent is
+// live while its loop keeps attempting fetches, and ready once a fetch succeeded recently with a valid token.
+type HealthChecker struct {
+	Client      *Client       // Optional, checks for readiness that its token is valid or refreshable, without refreshing
+	MaxFetchAge time.Duration // Readiness fails when the last successful fetch is older, defaults to DefaultMaxFetchAge
+	LiveTimeout time.Duration // Liveness fails when no fetch was attempted for longer, defaults to DefaultLiveTimeout
+	Clock       Clock         // Defaults to SystemClock
+
+	mu          sync.Mutex
+	started     time.Time
+	lastAttempt time.Time
+	lastSuccess time.Time
+	lastError   error
+	failures    int
+}
+
+// Observe records the outcome of a fetch (ex. Poller.OnError or the error of Syncer.Sync).
+func (h *HealthChecker) Observe(err error) {
+	now := orSystemClock(h.Clock).Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastAttempt = now
+	h.lastError = err
+	if err != nil {
+		h.failures++
+		return
+	}
+	h.lastSuccess = now
+	h.failures = 0
+}
+
+// SetThresholds changes MaxFetchAge and LiveTimeout while the health endpoints are served (ex. when the interval of
+// the agent is reloaded).
+func (h *HealthChecker) SetThresholds(maxFetchAge, liveTimeout time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.MaxFetchAge, h.LiveTimeout = maxFetchAge, liveTimeout
+}
+
+// Start marks the start of the agent, from which LiveTimeout is counted until the first fetch.
+func (h *HealthChecker) Start() {
+	now := orSystemClock(h.Clock).Now()
+	h.mu.Lock()
+	h.started = now
+	h.mu.Unlock()
+}
+
+// Status returns the current health of the agent.
+func (h *HealthChecker) Status() HealthStatus {
+	now := orSystemClock(h.Clock).Now()
+	h.mu.Lock()
+	s := HealthStatus{LastAttempt: h.lastAttempt, LastSuccess: h.lastSuccess, ConsecutiveFailures: h.failures}
+	if h.lastError != nil {
+		s.LastError = h.lastError.Error()
+	}
+	heartbeat := h.lastAttempt
+	if heartbeat.IsZero() {
+		heartbeat = h.started
+	}
+	liveTimeout, maxAge := h.LiveTimeout, h.MaxFetchAge
+	h.mu.Unlock()
+
+	if liveTimeout <= 0 {
+		liveTimeout = DefaultLiveTimeout
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultMaxFetchAge
+	}
+	s.Live = heartbeat.IsZero() || now.Sub(heartbeat) <= liveTimeout
+	if !s.Live {
+		s.Reasons = append(s.Reasons, "no fetch attempted since "+heartbeat.UTC().Format(time.RFC3339))
+	}
+	s.Ready = s.Live
+	switch {
+	case s.LastSuccess.IsZero():
+		s.Ready = false
+		s.Reasons = append(s.Reasons, "no successful fetch yet")
+	case now.Sub(s.LastSuccess) > maxAge:
+		s.Ready = false
+		s.Reasons = append(s.Reasons, "last successful fetch is older than "+maxAge.String())
+	}
+	if !s.LastSuccess.IsZero() {
+		s.FetchAge = now.Sub(s.LastSuccess).Seconds()
+	}
+	if h.Client != nil {
+		// The cached token only: probes must not refresh it, an expired one is refreshed by the next fetch
+		token := h.Client.cachedToken()
+		if token != nil {
+			s.TokenValid, s.TokenExpiry = token.Valid(), token.Expiry
+		}
+		if !s.TokenValid && (token == nil || token.RefreshToken == "") {
+			s.Ready = false
+			s.Reasons = append(s.Reasons, "no valid or refreshable token")
+		}
+	} else {
+		s.TokenValid = true
+	}
+	return s
+}
+
+// Handler serves /healthz (liveness) and /readyz (readiness) with the status as JSON, responding with 503 Service
+// Unavailable when failing.
+func (h *HealthChecker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	serve := func(ok func(s HealthStatus) bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			s := h.Status()
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Cache-Control", "no-store")
+			if !ok(s) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			_ = json.NewEncoder(w).Encode(s)
+		}
+	}
+	mux.Handle("/healthz", serve(func(s HealthStatus) bool { return s.Live }))
+	mux.Handle("/readyz", serve(func(s HealthStatus) bool { return s.Ready }))
+	return mux
+}
@@ -0,0 +1,59 @@
+package netatmo
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// MetricsHook observes API requests, for instrumentation with any metrics library.
+type MetricsHook interface {
+	// ObserveRequest is called after each API request with the endpoint (ex. getstationsdata), the duration, the
+	// response status (0 if no response) and the error, if any.
+	ObserveRequest(endpoint string, duration time.Duration, status int, err error)
+}
+
+// MetricsHookFunc adapts an ordinary function to the MetricsHook interface.
+type MetricsHookFunc func(endpoint string, duration time.Duration, status int, err error)
+
+// ObserveRequest calls f(endpoint, duration, status, err).
+func (f MetricsHookFunc) ObserveRequest(endpoint string, duration time.Duration, status int, err error) {
+	f(endpoint, duration, status, err)
+}
+
+// WithMetricsHook calls the hook for every API request.
+func WithMetricsHook(hook MetricsHook) Option {
+	return func(o *clientOptions) {
+		o.metricsHooks = append(o.metricsHooks, hook)
+	}
+}
+
+// RequestTracer traces API requests, for instrumentation with any tracing library (see package netatmootel for
+// OpenTelemetry).
+type RequestTracer interface {
+	// StartRequest is called before each API request with the endpoint (ex. getmeasure) and its query (ex. device_id
+	// and module_id). The request is sent with the returned context, and the returned function is called after it
+	// with the response status (0 if no response) and the error, if any.
+	StartRequest(ctx context.Context, endpoint string, query url.Values) (context.Context, func(status int, err error))
+}
+
+// WithRequestTracer traces every API request with the tracer.
+func WithRequestTracer(tracer RequestTracer) Option {
+	return func(o *clientOptions) {
+		o.requestTracers = append(o.requestTracers, tracer)
+	}
+}
+
+// startRequest starts tracing the API request. The returned function ends the traces in reverse order.
+func (c *Client) startRequest(ctx context.Context, endpoint string, query url.Values) (context.Context,
+	func(status int, err error)) {
+	ends := make([]func(int, error), len(c.options.requestTracers))
+	for i, tracer := range c.options.requestTracers {
+		ctx, ends[i] = tracer.StartRequest(ctx, endpoint, query)
+	}
+	return ctx, func(status int, err error) {
+		for i := len(ends) - 1; i >= 0; i-- {
+			ends[i](status, err)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package netatmo
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale defines locale used when no catalog matches.
+const DefaultLocale = "en"
+
+// Catalog defines translated messages keyed by message ID. Messages are fmt format strings.
+type Catalog map[string]string
+
+var (
+	catalogsMu sync.RWMutex
+	catalogs   = map[string]Catalog{
+		"en": {
+			"unit.0":                    "metric system",
+			"unit.1":                    "imperial system",
+			"unit.unknown":              "unknown unit: %d",
+			"windunit.0":                "kph",
+			"windunit.1":                "mph",
+			"windunit.2":                "ms",
+			"windunit.3":                "beaufort",
+			"windunit.4":                "knot",
+			"windunit.unknown":          "unknown wind unit: %d",
+			"pressureunit.0":            "mbar",
+			"pressureunit.1":            "inHg",
+			"pressureunit.2":            "mmHg",
+			"pressureunit.unknown":      "unknown pressure unit: %d",
+			"feellikealgo.0":            "humidex",
+			"feellikealgo.1":            "heat-index",
+			"feellikealgo.unknown":      "unknown feel like algorithm: %d",
+			"trend.up":                  "up",
+			"trend.down":                "down",
+			"trend.stable":              "stable",
+			"sensation.cold":            "cold",
+			"sensation.cool":            "cool",
+			"sensation.comfortable":     "comfortable",
+			"sensation.warm":            "warm",
+			"sensation.hot":             "hot",
+			"humiditylevel.dry":         "dry",
+			"humiditylevel.comfortable": "comfortable",
+			"humiditylevel.humid":       "humid",
+			"format.gust":               "gusts %s",
+		},
+		"fr": {
+			"unit.0":                    "système métrique",
+			"unit.1":                    "système impérial",
+			"unit.unknown":              "unité inconnue : %d",
+			"windunit.0":                "km/h",
+			"windunit.1":                "mph",
+			"windunit.2":                "m/s",
+			"windunit.3":                "beaufort",
+			"windunit.4":                "nœud",
+			"windunit.unknown":          "unité de vent inconnue : %d",
+			"pressureunit.0":            "mbar",
+			"pressureunit.1":            "inHg",
+			"pressureunit.2":            "mmHg",
+			"pressureunit.unknown":      "unité de pression inconnue : %d",
+			"feellikealgo.0":            "humidex",
+			"feellikealgo.1":            "indice de chaleur",
+			"feellikealgo.unknown":      "algorithme de température ressentie inconnu : %d",
+			"trend.up":                  "hausse",
+			"trend.down":                "baisse",
+			"trend.stable":              "stable",
+			"sensation.cold":            "froid",
+			"sensation.cool":            "frais",
+			"sensation.comfortable":     "confortable",
+			"sensation.warm":            "tiède",
+			"sensation.hot":             "chaud",
+			"humiditylevel.dry":         "sec",
+			"humiditylevel.comfortable": "confortable",
+			"humiditylevel.humid":       "humide",
+			"format.gust":               "rafales %s",
+		},
+		"de": {
+			"unit.0":                    "metrisches System",
+			"unit.1":                    "imperiales System",
+			"unit.unknown":              "unbekannte Einheit: %d",
+			"windunit.0":                "km/h",
+			"windunit.1":                "mph",
+			"windunit.2":                "m/s",
+			"windunit.3":                "Beaufort",
+			"windunit.4":                "Knoten",
+			"windunit.unknown":          "unbekannte Windeinheit: %d",
+			"pressureunit.0":            "mbar",
+			"pressureunit.1":            "inHg",
+			"pressureunit.2":            "mmHg",
+			"pressureunit.unknown":      "unbekannte Druckeinheit: %d",
+			"feellikealgo.0":            "Humidex",
+			"feellikealgo.1":            "Hitzeindex",
+			"feellikealgo.unknown":      "unbekannter Algorithmus der gefühlten Temperatur: %d",
+			"trend.up":                  "steigend",
+			"trend.down":                "fallend",
+			"trend.stable":              "stabil",
+			"sensation.cold":            "kalt",
+			"sensation.cool":            "kühl",
+			"sensation.comfortable":     "angenehm",
+			"sensation.warm":            "warm",
+			"sensation.hot":             "heiß",
+			"humiditylevel.dry":         "trocken",
+			"humiditylevel.comfortable": "angenehm",
+			"humiditylevel.humid":       "feucht",
+			"format.gust":               "Böen %s",
+		},
+		"ja": {
+			"unit.0":                    "メートル法",
+			"unit.1":                    "ヤード・ポンド法",
+			"unit.unknown":              "不明な単位: %d",
+			"windunit.0":                "km/h",
+			"windunit.1":                "mph",
+			"windunit.2":                "m/s",
+			"windunit.3":                "ビューフォート",
+			"windunit.4":                "ノット",
+			"windunit.unknown":          "不明な風速の単位: %d",
+			"pressureunit.0":            "mbar",
+			"pressureunit.1":            "inHg",
+			"pressureunit.2":            "mmHg",
+			"pressureunit.unknown":      "不明な気圧の単位: %d",
+			"feellikealgo.0":            "ヒューミデックス",
+			"feellikealgo.1":            "暑さ指数",
+			"feellikealgo.unknown":      "不明な体感温度の算出方法: %d",
+			"trend.up":                  "上昇",
+			"trend.down":                "下降",
+			"trend.stable":              "横ばい",
+			"sensation.cold":            "寒い",
+			"sensation.cool":            "涼しい",
+			"sensation.comfortable":     "快適",
+			"sensation.warm":            "暖かい",
+			"sensation.hot":             "暑い",
+			"humiditylevel.dry":         "乾燥",
+			"humiditylevel.comfortable": "快適",
+			"humiditylevel.humid":       "多湿",
+			"format.gust":               "最大瞬間 %s",
+		},
+	}
+)
+
+// RegisterCatalog adds messages of the locale (ex. "en", "pt-BR"), overriding existing ones with the same ID.
+func RegisterCatalog(locale string, catalog Catalog) {
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+	key := normalizeLocale(locale)
+	if catalogs[key] == nil {
+		catalogs[key] = make(Catalog)
+	}
+	for id, message := range catalog {
+		catalogs[key][id] = message
+	}
+}
+
+// Translate formats the message of the locale with the arguments. Locales fall back to their language
+// (ex. "fr_CA" -> "fr") and then to DefaultLocale; unknown message IDs are returned as is.
+func Translate(locale, id string, args ...interface{}) string {
+	catalogsMu.RLock()
+	message, ok := lookupMessage(locale, id)
+	catalogsMu.RUnlock()
+	if !ok {
+		message = id
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+func lookupMessage(locale, id string) (string, bool) {
+	key := normalizeLocale(locale)
+	for _, candidate := range []string{key, strings.SplitN(key, "-", 2)[0], DefaultLocale} {
+		if message, ok := catalogs[candidate][id]; ok {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// normalizeLocale converts locale identifiers to lower-case language and upper-case region joined by a hyphen
+// (ex. "fr_fr.UTF-8" -> "fr-FR").
+func normalizeLocale(locale string) string {
+	locale = strings.SplitN(locale, ".", 2)[0]
+	parts := strings.SplitN(strings.Replace(locale, "_", "-", -1), "-", 2)
+	key := strings.ToLower(parts[0])
+	if len(parts) == 2 {
+		key += "-" + strings.ToUpper(parts[1])
+	}
+	return key
+}
+
+// Locale returns the user regional preferences, falling back to the user locale.
+func (a *Administrative) Locale() string {
+	if a.DisplayLocale != "" {
+		return a.DisplayLocale
+	}
+	return a.Language
+}
+
+// DescribeUnitIn describes unit identifier in the locale (ex. a.DescribeUnitIn(a.Locale())).
+func (a *Administrative) DescribeUnitIn(locale string) string {
+	return describeIn(locale, "unit", a.Unit, 1)
+}
+
+// DescribeWindUnitIn describes wind unit identifier in the locale.
+func (a *Administrative) DescribeWindUnitIn(locale string) string {
+	return describeIn(locale, "windunit", a.WindUnit, 4)
+}
+
+// DescribePressureUnitIn describes pressure unit identifier in the locale.
+func (a *Administrative) DescribePressureUnitIn(locale string) string {
+	return describeIn(locale, "pressureunit", a.PressureUnit, 2)
+}
+
+// DescribeFeelLikeAlgorithmIn describes identifier of algorithm used to compute feel like temperature in the locale.
+func (a *Administrative) DescribeFeelLikeAlgorithmIn(locale string) string {
+	return describeIn(locale, "feellikealgo", a.FeelLikeAlgorithm, 1)
+}
+
+// DescribeTrendIn describes temperature or pressure trend of dashboard data (up, down or stable) in the locale.
+// Unknown trends are returned as is.
+func DescribeTrendIn(locale, trend string) string {
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+	if message, ok := lookupMessage(locale, "trend."+trend); ok {
+		return message
+	}
+	return trend
+}
+
+// DescribeIn describes the thermal sensation in the locale.
+func (s ThermalSensation) DescribeIn(locale string) string {
+	return Translate(locale, "sensation."+string(s))
+}
+
+// DescribeIn describes the humidity level in the locale.
+func (l HumidityLevel) DescribeIn(locale string) string {
+	return Translate(locale, "humiditylevel."+string(l))
+}
+
+// describeIn translates identifier values 0 to max of the group, or the unknown message of the group.
+func describeIn(locale, group string, value, max int) string {
+	if value < 0 || value > max {
+		return Translate(locale, group+".unknown", value)
+	}
+	return Translate(locale, fmt.Sprintf("%s.%d", group, value))
+}
@@ -0,0 +1,75 @@
+package netatmo
+
+// RoomComparison defines current differences between an indoor module and the outdoor module of the same station.
+// Deltas are indoor minus outdoor.
+type RoomComparison struct {
+	DeviceID         string
+	ModuleID         string // Base station ID for the main room
+	ModuleName       string
+	OutdoorModuleID  string
+	TemperatureDelta *float64 // Nullable, °C
+	HumidityDelta    *int     // Nullable, %
+	DewPoint         *float64 // Nullable, indoor dew point in °C
+	OutdoorDewPoint  *float64 // Nullable, °C
+	DewPointDelta    *float64 // Nullable, °C
+}
+
+// OutdoorDrier reports whether outdoor air holds less moisture than the room, i.e. airing the room lowers its
+// humidity. The second result is false if dew points are unknown.
+func (r *RoomComparison) OutdoorDrier() (bool, bool) {
+	if r.DewPointDelta == nil {
+		return false, false
+	}
+	return *r.DewPointDelta > 0, true
+}
+
+// CompareIndoor pairs each indoor module (base station and additional indoor modules) with the outdoor module of the
+// same station using dashboard data. Stations without outdoor module are skipped.
+func CompareIndoor(devices []Device) []RoomComparison {
+	var rooms []RoomComparison
+	for _, d := range devices {
+		outdoor := findModuleByType(&d, TypeOutdoor)
+		if outdoor == nil {
+			continue
+		}
+		rooms = append(rooms, compareRoom(d.ID, d.ID, d.ModuleName, d.DashboardData, outdoor))
+		for _, m := range d.Modules {
+			if m.Type == TypeIndoor {
+				rooms = append(rooms, compareRoom(d.ID, m.ID, m.ModuleName, m.DashboardData, outdoor))
+			}
+		}
+	}
+	return rooms
+}
+
+func compareRoom(deviceID, moduleID, name string, indoor *DashboardData, outdoor *Module) RoomComparison {
+	room := RoomComparison{DeviceID: deviceID, ModuleID: moduleID, ModuleName: name, OutdoorModuleID: outdoor.ID}
+	out := outdoor.DashboardData
+	if indoor == nil || out == nil {
+		return room
+	}
+	if indoor.Temperature != nil && out.Temperature != nil {
+		v := *indoor.Temperature - *out.Temperature
+		room.TemperatureDelta = &v
+	}
+	if indoor.Humidity != nil && out.Humidity != nil {
+		v := *indoor.Humidity - *out.Humidity
+		room.HumidityDelta = &v
+	}
+	room.DewPoint = dewPointOf(indoor.Temperature, indoor.Humidity)
+	room.OutdoorDewPoint = dewPointOf(out.Temperature, out.Humidity)
+	if room.DewPoint != nil && room.OutdoorDewPoint != nil {
+		v := *room.DewPoint - *room.OutdoorDewPoint
+		room.DewPointDelta = &v
+	}
+	return room
+}
+
+func findModuleByType(d *Device, moduleType string) *Module {
+	for i := range d.Modules {
+		if d.Modules[i].Type == moduleType {
+			return &d.Modules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package netatmo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// DefaultInfluxDBNaming defines measurement and tag naming of InfluxDBSink without explicit naming: a single
+// "netatmo" measurement with account, device and module tags and a field per measurement.
+var DefaultInfluxDBNaming = MetricNaming{Name: "netatmo"}
+
+// InfluxDBSink writes measures to InfluxDB 2.x with the line protocol, or to InfluxDB 1.8 through its 2.x
+// compatible API. Measurements come from the name of Namer and tags from its labels, so measurements of a measure
+// with the same name are written as fields of one point (snake case, ex. wind_strength).
+type InfluxDBSink struct {
+	URL        string       // Server URL (ex. http://localhost:8086)
+	Org        string       // Organization, ignored by 1.8
+	Bucket     string       // Bucket, or database/retention-policy with 1.8
+	Token      string       // API token, or username:password with 1.8
+	Namer      *MetricNamer // Measurements and tags, defaults to DefaultInfluxDBNaming
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+	mu         sync.Mutex
+}
+
+// Write sends points of the measures in a single request.
+func (s *InfluxDBSink) Write(ctx context.Context, measures []Measure) error {
+	if len(measures) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Namer == nil {
+		namer, err := NewMetricNamer(DefaultInfluxDBNaming)
+		if err != nil {
+			return fmt.Errorf("influxdb: %w", err)
+		}
+		s.Namer = namer
+	}
+	var body bytes.Buffer
+	for i := range measures {
+		if err := writeILP(&body, s.Namer, &measures[i]); err != nil {
+			return fmt.Errorf("influxdb: %w", err)
+		}
+	}
+	if dryRun(ctx, "influxdb", fmt.Sprintf("%d measures to %s", len(measures), s.Bucket), body.String()) {
+		return nil
+	}
+	query := url.Values{}
+	query.Set("bucket", s.Bucket)
+	if s.Org != "" {
+		query.Set("org", s.Org)
+	}
+	query.Set("precision", "ns")
+	endpoint := strings.TrimSuffix(s.URL, "/") + "/api/v2/write?" + query.Encode()
+	if _, err := sinkPost(ctx, s.HTTPClient, endpoint, "text/plain; charset=utf-8", &body, s.authorize); err != nil {
+		return fmt.Errorf("influxdb: %w", err)
+	}
+	return nil
+}
+
+func (s *InfluxDBSink) authorize(req *http.Request) {
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Token "+s.Token)
+	}
+}
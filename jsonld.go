@@ -0,0 +1,60 @@
+package netatmo
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// jsonLDProperty defines an observation value rendered as schema.org Observation.
+type jsonLDProperty struct {
+	name     string // Measured property (ex. temperature)
+	value    func(o *Observation) *float64
+	unitCode string // UN/CEFACT common code
+	unitText string
+}
+
+var jsonLDProperties = []jsonLDProperty{
+	{"temperature", func(o *Observation) *float64 { return o.Temperature }, "CEL", "°C"},
+	{"relativeHumidity", func(o *Observation) *float64 { return o.Humidity }, "P1", "%"},
+	{"dewPoint", func(o *Observation) *float64 { return o.DewPoint }, "CEL", "°C"},
+	{"atmosphericPressure", func(o *Observation) *float64 { return o.Pressure }, "A97", "hPa"},
+	{"windSpeed", func(o *Observation) *float64 { return o.WindSpeed }, "KMH", "km/h"},
+	{"windDirection", func(o *Observation) *float64 { return o.WindAngle }, "DD", "°"},
+	{"windGust", func(o *Observation) *float64 { return o.GustSpeed }, "KMH", "km/h"},
+	{"precipitationLastHour", func(o *Observation) *float64 { return o.RainLastHour }, "MMT", "mm"},
+	{"precipitationToday", func(o *Observation) *float64 { return o.RainToday }, "MMT", "mm"},
+}
+
+// MarshalJSONLD renders the observation as a schema.org JSON-LD graph of Observation entities, one per available
+// value, about a Place with the name and the station location. The result can be embedded in a web page within
+// <script type="application/ld+json">.
+func MarshalJSONLD(o Observation, name string) ([]byte, error) {
+	place := map[string]interface{}{
+		"@type": "Place",
+		"name":  name,
+		"geo": map[string]interface{}{
+			"@type":     "GeoCoordinates",
+			"latitude":  o.Latitude,
+			"longitude": o.Longitude,
+			"elevation": o.Altitude,
+		},
+	}
+	date := time.Unix(o.Time, 0).UTC().Format(time.RFC3339)
+	graph := []map[string]interface{}{}
+	for _, p := range jsonLDProperties {
+		v := p.value(&o)
+		if v == nil {
+			continue
+		}
+		graph = append(graph, map[string]interface{}{
+			"@type":            "Observation",
+			"observationAbout": place,
+			"observationDate":  date,
+			"measuredProperty": map[string]string{"@type": "Property", "name": p.name},
+			"value":            *v,
+			"unitCode":         p.unitCode,
+			"unitText":         p.unitText,
+		})
+	}
+	return json.Marshal(map[string]interface{}{"@context": "https://schema.org", "@graph": graph})
+}
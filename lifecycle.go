@@ -0,0 +1,28 @@
+package netatmo
+
+import "errors"
+
+// ErrClientClosed is wrapped by errors of API calls made after Close.
+var ErrClientClosed = errors.New("client is closed")
+
+// Close closes idle connections, stops background goroutines of the client and makes further API calls fail with
+// ErrClientClosed. Calls in flight are not interrupted. Closing a closed client does nothing.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		if t, ok := c.options.baseTransport.(interface{ CloseIdleConnections() }); ok {
+			t.CloseIdleConnections()
+		}
+	})
+	return nil
+}
+
+// isClosed reports whether Close was called.
+func (c *Client) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,86 @@
+package netatmo
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteMarkdownStations writes an overview of the devices and their modules as a GitHub-flavored Markdown table, one
+// row per device or module with current values formatted by the options.
+func WriteMarkdownStations(w io.Writer, devices []Device, opts FormatOptions) error {
+	rows := [][]string{{"Station", "Module", "Type", "Values", "Battery", "Reachable", "Updated"}}
+	for _, d := range devices {
+		rows = append(rows, []string{d.StationName, d.ModuleName, d.Type, markdownValues(d.DashboardData, opts), "",
+			strconv.FormatBool(d.Reachable), markdownUpdated(d.DashboardData)})
+		for _, m := range d.Modules {
+			rows = append(rows, []string{d.StationName, m.ModuleName, m.Type, markdownValues(m.DashboardData, opts),
+				strconv.Itoa(m.BatteryPercent) + " %", strconv.FormatBool(m.Reachable),
+				markdownUpdated(m.DashboardData)})
+		}
+	}
+	return writeMarkdownTable(w, rows)
+}
+
+// WriteMarkdownMeasures writes the measurements (ex. Temperature, CO2; TargetMeasurements if empty) of the measures
+// as a GitHub-flavored Markdown table, one row per measure. Missing values are left empty.
+func WriteMarkdownMeasures(w io.Writer, measures []Measure, metrics []string, opts FormatOptions) error {
+	if len(metrics) == 0 {
+		metrics = TargetMeasurements
+	}
+	header := []string{"Time"}
+	for _, metric := range metrics {
+		if unit := metricUnits[metric]; unit != "" {
+			metric += " (" + unit + ")"
+		}
+		header = append(header, metric)
+	}
+	rows := [][]string{header}
+	for i := range measures {
+		row := []string{time.Unix(measures[i].Timestamp, 0).Format("2006-01-02 15:04")}
+		for _, metric := range metrics {
+			cell := ""
+			if v, ok := measures[i].Value(metric); ok {
+				cell = opts.number(v, -1)
+			}
+			row = append(row, cell)
+		}
+		rows = append(rows, row)
+	}
+	return writeMarkdownTable(w, rows)
+}
+
+func markdownValues(data *DashboardData, opts FormatOptions) string {
+	if data == nil {
+		return ""
+	}
+	return FormatDashboardData(data, opts)
+}
+
+func markdownUpdated(data *DashboardData) string {
+	if data == nil || data.UTCTime == 0 {
+		return ""
+	}
+	return time.Unix(data.UTCTime, 0).Format("2006-01-02 15:04")
+}
+
+// writeMarkdownTable writes the rows as a table, the first row being the header.
+func writeMarkdownTable(w io.Writer, rows [][]string) error {
+	for i, row := range rows {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = strings.Replace(strings.Replace(cell, "|", `\|`, -1), "\n", " ", -1)
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+			return err
+		}
+		if i == 0 {
+			if _, err := fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(row))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
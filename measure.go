@@ -0,0 +1,203 @@
+package netatmo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scale defines time step of measure requests.
+type Scale string
+
+// Supported scales.
+const (
+	ScaleMax    Scale = "max" // Every sample (about 5 minutes)
+	Scale30Min  Scale = "30min"
+	Scale1Hour  Scale = "1hour"
+	Scale3Hours Scale = "3hours"
+	Scale1Day   Scale = "1day"
+	Scale1Week  Scale = "1week"
+	Scale1Month Scale = "1month"
+)
+
+// valid reports whether the scale is one of the supported scales.
+func (s Scale) valid() bool {
+	switch s {
+	case ScaleMax, Scale30Min, Scale1Hour, Scale3Hours, Scale1Day, Scale1Week, Scale1Month:
+		return true
+	}
+	return false
+}
+
+// step returns approximate duration of a step of the scale (30 days for a month).
+func (s Scale) step() time.Duration {
+	switch s {
+	case Scale30Min:
+		return 30 * time.Minute
+	case Scale1Hour:
+		return time.Hour
+	case Scale3Hours:
+		return 3 * time.Hour
+	case Scale1Day:
+		return 24 * time.Hour
+	case Scale1Week:
+		return 7 * 24 * time.Hour
+	case Scale1Month:
+		return 30 * 24 * time.Hour
+	default:
+		return DefaultSampleInterval
+	}
+}
+
+// ErrInvalidTimeRange is wrapped by errors of measure requests with time windows the API cannot answer.
+var ErrInvalidTimeRange = errors.New("invalid time range")
+
+// measureLimit defines maximum number of steps returned by a single getmeasure call.
+const measureLimit = 1024
+
+// AggregateMeasurements defines list of aggregate measurement types, which are requested in addition to
+// TargetMeasurements when the scale is not max.
+var AggregateMeasurements = []string{"min_temp", "max_temp", "min_hum", "max_hum", "min_pressure", "max_pressure",
+	"min_noise", "max_noise", "sum_rain", "date_max_gust"}
+
+// MeasureOption configures a measure request.
+type MeasureOption func(*measureRequest)
+
+// WithScale sets the time step of the measure request. Default is ScaleMax.
+func WithScale(scale Scale) MeasureOption {
+	return func(r *measureRequest) {
+		r.scale = scale
+	}
+}
+
+// WithTypes sets the measurement types of the measure request (ex. "Temperature", "min_temp"). Default is
+// TargetMeasurements, plus AggregateMeasurements when the scale is not max.
+func WithTypes(types ...string) MeasureOption {
+	return func(r *measureRequest) {
+		r.types = types
+	}
+}
+
+// WithRealTime sets the real_time parameter of the measure request. When the scale is not max, the API shifts
+// timestamps by half of the scale by default (ex. 12:30 for the 12:00-13:00 step of 1hour); real time disables the
+// shift so timestamps align with the beginning of each step. It has no effect with ScaleMax.
+// Default is true for GetMeasureByTimeRange and GetMeasureSince, and the API default (false) for GetMeasureByNewest.
+func WithRealTime(realTime bool) MeasureOption {
+	return func(r *measureRequest) {
+		r.realTime = &realTime
+	}
+}
+
+// Order defines timestamp order of returned measures.
+type Order string
+
+// Supported orders.
+const (
+	OrderAscending  Order = "asc" // Oldest first, as returned by the API
+	OrderDescending Order = "desc"
+)
+
+// WithOrder sets the timestamp order of returned measures. Default is OrderAscending. With OrderDescending,
+// EachMeasurePage also pages newest-first.
+func WithOrder(order Order) MeasureOption {
+	return func(r *measureRequest) {
+		r.order = order
+	}
+}
+
+// measureRequest defines parameters of getmeasure common to all measure methods.
+type measureRequest struct {
+	scale    Scale
+	types    []string
+	realTime *bool // Nil leaves the default of each method
+	order    Order
+}
+
+func newMeasureRequest(opts []MeasureOption) (*measureRequest, error) {
+	r := &measureRequest{scale: ScaleMax, order: OrderAscending}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if !r.scale.valid() {
+		return nil, fmt.Errorf("unknown scale: %q (max, 30min, 1hour, 3hours, 1day, 1week or 1month)", r.scale)
+	}
+	if r.order != OrderAscending && r.order != OrderDescending {
+		return nil, fmt.Errorf("unknown order: %s", r.order)
+	}
+	if len(r.types) == 0 {
+		r.types = TargetMeasurements
+		if r.scale != ScaleMax {
+			r.types = append(append([]string{}, TargetMeasurements...), AggregateMeasurements...)
+		}
+	}
+	for _, t := range r.types {
+		if _, ok := measureFields[strings.ToLower(t)]; !ok {
+			return nil, fmt.Errorf("unknown measurement type: %s", t)
+		}
+		if isDerivedMeasurement(t) {
+			return nil, fmt.Errorf("measurement type %s is derived, not available from the API", t)
+		}
+		if r.scale == ScaleMax && isAggregateMeasurement(t) {
+			return nil, fmt.Errorf("measurement type %s is not available with scale %s", t, r.scale)
+		}
+	}
+	return r, nil
+}
+
+// values returns query parameters of the request.
+func (r *measureRequest) values(deviceID, moduleID string) url.Values {
+	query := url.Values{}
+	query.Set("device_id", deviceID)
+	query.Set("module_id", moduleID)
+	query.Set("scale", string(r.scale))
+	query.Set("type", strings.Join(r.types, ","))
+	query.Set("limit", strconv.Itoa(measureLimit))
+	if r.realTime != nil {
+		query.Set("real_time", strconv.FormatBool(*r.realTime))
+	}
+	return query
+}
+
+// validateRange checks the time window of the request before calling the API, which answers impossible windows with
+// empty bodies. Zero begin and zero end mean unbounded.
+func (r *measureRequest) validateRange(begin, end int64, now time.Time) error {
+	format := func(t int64) string { return time.Unix(t, 0).UTC().Format(time.RFC3339) }
+	switch {
+	case begin < 0 || end < 0:
+		return fmt.Errorf("%w: negative unix time (begin %d, end %d)", ErrInvalidTimeRange, begin, end)
+	case begin > now.Unix():
+		return fmt.Errorf("%w: begin %s is in the future", ErrInvalidTimeRange, format(begin))
+	case end != 0 && begin >= end:
+		return fmt.Errorf("%w: begin %s is not before end %s", ErrInvalidTimeRange, format(begin), format(end))
+	}
+	if end == 0 {
+		end = now.Unix()
+	}
+	if begin != 0 && r.scale != ScaleMax && end-begin < int64(r.scale.step()/time.Second) {
+		return fmt.Errorf("%w: %s to %s is shorter than a step of scale %s", ErrInvalidTimeRange, format(begin),
+			format(end), r.scale)
+	}
+	return nil
+}
+
+// sort orders measures returned by the API (ascending) in the order of the request.
+func (r *measureRequest) sort(measures []Measure) []Measure {
+	if r.order == OrderDescending {
+		for i, j := 0, len(measures)-1; i < j; i, j = i+1, j-1 {
+			measures[i], measures[j] = measures[j], measures[i]
+		}
+	}
+	return measures
+}
+
+func isAggregateMeasurement(name string) bool {
+	for _, t := range AggregateMeasurements {
+		if strings.EqualFold(t, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package netatmo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// kmhPerKnot defines km/h of a knot.
+const kmhPerKnot = 1.852
+
+// METAR formats current conditions of the device as a METAR-like observation string
+// (ex. "ABCD 171230Z AUTO 27010G22KT 18/12 Q1013"). Ident is the station identifier, usually four letters.
+// Unavailable groups are rendered as slashes.
+func METAR(ident string, device *Device) string {
+	var outdoor, wind *DashboardData
+	if m := findModuleByType(device, TypeOutdoor); m != nil {
+		outdoor = m.DashboardData
+	}
+	if m := findModuleByType(device, TypeWind); m != nil {
+		wind = m.DashboardData
+	}
+	observed := int64(0)
+	for _, data := range []*DashboardData{device.DashboardData, outdoor, wind} {
+		if data != nil && data.UTCTime > observed {
+			observed = data.UTCTime
+		}
+	}
+	groups := []string{strings.ToUpper(ident), time.Unix(observed, 0).UTC().Format("021504Z"), "AUTO",
+		metarWind(wind), metarTemperature(outdoor), metarPressure(device.DashboardData)}
+	return strings.Join(groups, " ")
+}
+
+// GetMETAR fetches current data of the device and formats it as a METAR-like observation string.
+func (c *Client) GetMETAR(deviceID, ident string) (string, error) {
+	return c.GetMETARContext(context.Background(), deviceID, ident)
+}
+
+// GetMETARContext is GetMETAR with a context canceling the request.
+func (c *Client) GetMETARContext(ctx context.Context, deviceID, ident string) (string, error) {
+	device, err := c.getDevice(ctx, deviceID)
+	if err != nil {
+		return "", err
+	}
+	return METAR(ident, device), nil
+}
+
+func metarWind(data *DashboardData) string {
+	if data == nil || data.WindStrength == nil {
+		return "/////KT"
+	}
+	speed := knots(*data.WindStrength)
+	if speed == 0 {
+		return "00000KT"
+	}
+	direction := "VRB"
+	if data.WindAngle != nil {
+		// Directions are reported to the nearest 10 degrees, north as 360
+		d := int(math.Round(float64(*data.WindAngle)/10)) * 10 % 360
+		if d == 0 {
+			d = 360
+		}
+		direction = fmt.Sprintf("%03d", d)
+	}
+	group := fmt.Sprintf("%s%02d", direction, speed)
+	if data.GustStrength != nil {
+		// Gusts are reported if they exceed the mean speed by 10 knots or more
+		if gust := knots(*data.GustStrength); gust-speed >= 10 {
+			group += fmt.Sprintf("G%02d", gust)
+		}
+	}
+	return group + "KT"
+}
+
+func metarTemperature(data *DashboardData) string {
+	if data == nil || data.Temperature == nil {
+		return "/////"
+	}
+	group := metarCelsius(*data.Temperature) + "/"
+	if dewPoint := dewPointOf(data.Temperature, data.Humidity); dewPoint != nil {
+		group += metarCelsius(*dewPoint)
+	} else {
+		group += "//"
+	}
+	return group
+}
+
+func metarPressure(data *DashboardData) string {
+	if data == nil || data.Pressure == nil {
+		return "Q////"
+	}
+	return fmt.Sprintf("Q%04d", int(math.Floor(*data.Pressure)))
+}
+
+// metarCelsius formats whole degrees with M prefix for negative values (ex. -3.4 -> M03).
+func metarCelsius(v float64) string {
+	r := int(math.Round(v))
+	if r < 0 || (r == 0 && v < 0) {
+		return fmt.Sprintf("M%02d", -r)
+	}
+	return fmt.Sprintf("%02d", r)
+}
+
+func knots(kmh int) int {
+	return int(math.Round(float64(kmh) / kmhPerKnot))
+}
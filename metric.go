@@ -0,0 +1,151 @@
+package netatmo
+
+import (
+	"math"
+	"strings"
+)
+
+// measureField defines accessor of a Measure field. Exactly one of the accessors is set.
+type measureField struct {
+	float    func(m *Measure) **float64
+	int      func(m *Measure) **int
+	time     func(m *Measure) **int64
+	keepZero bool // Zero is a meaningful value (ex. no rain), not a null from the API
+}
+
+// measureFields maps lower-cased measurement type names of getmeasure to Measure fields.
+var measureFields = map[string]measureField{
+	"temperature":   {float: func(m *Measure) **float64 { return &m.Temperature }},
+	"co2":           {int: func(m *Measure) **int { return &m.CO2 }},
+	"humidity":      {int: func(m *Measure) **int { return &m.Humidity }},
+	"pressure":      {float: func(m *Measure) **float64 { return &m.Pressure }},
+	"noise":         {int: func(m *Measure) **int { return &m.Noise }},
+	"windstrength":  {int: func(m *Measure) **int { return &m.WindStrength }},
+	"windangle":     {int: func(m *Measure) **int { return &m.WindAngle }},
+	"guststrength":  {int: func(m *Measure) **int { return &m.GustStrength }},
+	"gustangle":     {int: func(m *Measure) **int { return &m.GustAngle }},
+	"rain":          {float: func(m *Measure) **float64 { return &m.Rain }, keepZero: true},
+	"min_temp":      {float: func(m *Measure) **float64 { return &m.MinTemperature }},
+	"max_temp":      {float: func(m *Measure) **float64 { return &m.MaxTemperature }},
+	"min_hum":       {int: func(m *Measure) **int { return &m.MinHumidity }},
+	"max_hum":       {int: func(m *Measure) **int { return &m.MaxHumidity }},
+	"min_pressure":  {float: func(m *Measure) **float64 { return &m.MinPressure }},
+	"max_pressure":  {float: func(m *Measure) **float64 { return &m.MaxPressure }},
+	"min_noise":     {int: func(m *Measure) **int { return &m.MinNoise }},
+	"max_noise":     {int: func(m *Measure) **int { return &m.MaxNoise }},
+	"sum_rain":      {float: func(m *Measure) **float64 { return &m.SumRain }, keepZero: true},
+	"date_max_gust": {time: func(m *Measure) **int64 { return &m.MaxGustTime }},
+	"dew_point":     {float: func(m *Measure) **float64 { return &m.DewPoint }, keepZero: true},
+	"humidex":       {float: func(m *Measure) **float64 { return &m.Humidex }, keepZero: true},
+}
+
+// Value returns the measurement value by type name (ex. Temperature, min_temp; case insensitive). The second result
+// is false if the name is unknown or the value is null.
+func (m *Measure) Value(name string) (float64, bool) {
+	f, ok := measureFields[strings.ToLower(name)]
+	switch {
+	case !ok:
+		return 0, false
+	case f.float != nil:
+		return floatValue(*f.float(m))
+	case f.int != nil:
+		return intValue(*f.int(m))
+	default:
+		if v := *f.time(m); v != nil {
+			return float64(*v), true
+		}
+		return 0, false
+	}
+}
+
+// SetValue sets the measurement value by type name (ex. Temperature, min_temp; case insensitive). Integer
+// measurements are rounded. It returns false if the name is unknown.
+func (m *Measure) SetValue(name string, v float64) bool {
+	f, ok := measureFields[strings.ToLower(name)]
+	switch {
+	case !ok:
+		return false
+	case f.float != nil:
+		*f.float(m) = &v
+	case f.int != nil:
+		*f.int(m) = roundInt(v)
+	default:
+		t := int64(v)
+		*f.time(m) = &t
+	}
+	return true
+}
+
+// clearValue sets the measurement value by type name to null.
+func (m *Measure) clearValue(name string) {
+	f, ok := measureFields[strings.ToLower(name)]
+	switch {
+	case !ok:
+	case f.float != nil:
+		*f.float(m) = nil
+	case f.int != nil:
+		*f.int(m) = nil
+	default:
+		*f.time(m) = nil
+	}
+}
+
+// setResponseValue sets the value of getmeasure response, treating exact zero as null unless zero is meaningful.
+func (m *Measure) setResponseValue(name string, v *float64) {
+	f, ok := measureFields[strings.ToLower(name)]
+	if !ok || v == nil {
+		return
+	}
+	if !f.keepZero && *v == 0.0 {
+		return
+	}
+	m.SetValue(name, *v)
+}
+
+func floatValue(v *float64) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
+
+func intValue(v *int) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return float64(*v), true
+}
+
+func roundInt(v float64) *int {
+	iv := int(math.Round(v))
+	return &iv
+}
+
+// Value returns the dashboard value by measurement name (one of TargetMeasurements; case insensitive). The second
+// result is false if the name is unknown or the value is null.
+func (d *DashboardData) Value(name string) (float64, bool) {
+	switch strings.ToLower(name) {
+	case "temperature":
+		return floatValue(d.Temperature)
+	case "co2":
+		return intValue(d.CO2)
+	case "humidity":
+		return intValue(d.Humidity)
+	case "pressure":
+		return floatValue(d.Pressure)
+	case "noise":
+		return intValue(d.Noise)
+	case "windstrength":
+		return intValue(d.WindStrength)
+	case "windangle":
+		return intValue(d.WindAngle)
+	case "guststrength":
+		return intValue(d.GustStrength)
+	case "gustangle":
+		return intValue(d.GustAngle)
+	case "rain":
+		return floatValue(d.Rain)
+	default:
+		return 0, false
+	}
+}
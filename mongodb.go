@@ -0,0 +1,107 @@
+package netatmo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultMongoBatchSize defines number of documents per insert of MongoSink.
+const DefaultMongoBatchSize = 1000
+
+// MongoDocument defines a measure stored in MongoDB. Field tags follow the BSON names used by the MongoDB driver.
+type MongoDocument struct {
+	Timestamp time.Time          `bson:"timestamp" json:"timestamp"`
+	Meta      MongoMeta          `bson:"meta" json:"meta"`
+	Values    map[string]float64 `bson:"values" json:"values"` // Keyed by snake case name (ex. wind_strength)
+}
+
+// MongoMeta defines the identity of MongoDocument, used as the metaField of time series collections (timeField
+// "timestamp", metaField "meta", granularity "minutes").
+type MongoMeta struct {
+	Account  string `bson:"account,omitempty" json:"account,omitempty"`
+	DeviceID string `bson:"device_id" json:"device_id"`
+	ModuleID string `bson:"module_id" json:"module_id"`
+}
+
+// Filter returns the upsert filter of the document, matching module and timestamp.
+func (d *MongoDocument) Filter() map[string]interface{} {
+	return map[string]interface{}{"meta.device_id": d.Meta.DeviceID, "meta.module_id": d.Meta.ModuleID,
+		"timestamp": d.Timestamp}
+}
+
+// MongoCollection is implemented by adapters of a MongoDB driver collection, keeping the driver out of the
+// dependencies of this package (see README for an adapter of go.mongodb.org/mongo-driver).
+type MongoCollection interface {
+	// InsertMany inserts the documents (ex. collection.InsertMany with ordered false).
+	InsertMany(ctx context.Context, docs []MongoDocument) error
+	// UpsertMany replaces or inserts the documents matching their Filter (ex. collection.BulkWrite of
+	// ReplaceOneModel with upsert).
+	UpsertMany(ctx context.Context, docs []MongoDocument) error
+}
+
+// MongoSink writes measures into a MongoDB collection in batches.
+type MongoSink struct {
+	Collection MongoCollection
+	BatchSize  int // Defaults to DefaultMongoBatchSize
+	// Upsert replaces documents of the same module and timestamp instead of inserting duplicates. Time series
+	// collections support upserts only since MongoDB 5.0.5 with metaField filters; otherwise wrap the sink with
+	// NewDedupSink and keep Upsert false.
+	Upsert bool
+}
+
+// Write inserts or upserts the measures.
+func (s *MongoSink) Write(ctx context.Context, measures []Measure) error {
+	size := s.BatchSize
+	if size <= 0 {
+		size = DefaultMongoBatchSize
+	}
+	if IsDryRun(ctx) {
+		var lines []string
+		for i := 0; i < len(measures) && i < DryRunSampleLines; i++ {
+			data, err := json.Marshal(NewMongoDocument(&measures[i]))
+			if err != nil {
+				return err
+			}
+			lines = append(lines, string(data))
+		}
+		verb := "insert"
+		if s.Upsert {
+			verb = "upsert"
+		}
+		dryRun(ctx, "mongodb", fmt.Sprintf("%s %d documents in %d batches", verb, len(measures),
+			(len(measures)+size-1)/size), strings.Join(lines, "\n"))
+		return nil
+	}
+	for start := 0; start < len(measures); start += size {
+		batch := make([]MongoDocument, 0, size)
+		for i := start; i < len(measures) && i < start+size; i++ {
+			batch = append(batch, NewMongoDocument(&measures[i]))
+		}
+		var err error
+		if s.Upsert {
+			err = s.Collection.UpsertMany(ctx, batch)
+		} else {
+			err = s.Collection.InsertMany(ctx, batch)
+		}
+		if err != nil {
+			return fmt.Errorf("mongodb: %w", err)
+		}
+	}
+	return nil
+}
+
+// NewMongoDocument converts the measure to a document.
+func NewMongoDocument(m *Measure) MongoDocument {
+	values := make(map[string]float64)
+	for _, v := range sinkValues(m) {
+		values[snakeCase(v.Name)] = v.Value
+	}
+	return MongoDocument{
+		Timestamp: time.Unix(m.Timestamp, 0).UTC(),
+		Meta:      MongoMeta{Account: m.Account, DeviceID: m.DeviceID, ModuleID: m.ModuleID},
+		Values:    values,
+	}
+}
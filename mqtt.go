@@ -0,0 +1,298 @@
+package netatmo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// DefaultMQTTTopic defines topic template of MQTTSink without explicit topic
+// (ex. netatmo/70:ee:50:00:00:14/02:00:00:00:00:01).
+const DefaultMQTTTopic = "netatmo/{{.DeviceID}}/{{.ModuleID}}"
+
+// MQTTSink publishes each measure as JSON to an MQTT 3.1.1 broker, one message per module and timestamp. Each write
+// connects, publishes and disconnects, so the sink keeps no connection between the 10 minutes intervals.
+type MQTTSink struct {
+	URL      string        // Broker URL, tcp://host:1883 or ssl://host:8883 (tls:// is an alias)
+	Topic    string        // Topic template over the Measure, defaults to DefaultMQTTTopic
+	ClientID string        // Defaults to a random netatmo-<hex>
+	Username string        // Optional
+	Password string        // Optional, requires Username (MQTT 3.1.1 has no password without user name)
+	QoS      byte          // 0 or 1, QoS 1 waits for PUBACK of each message
+	Retain   bool          // Retain messages, so new subscribers get the newest measure
+	Timeout  time.Duration // Dial and I/O timeout, defaults to 10 seconds
+	mu       sync.Mutex
+	topic    *template.Template
+}
+
+// Write publishes the measures in a single session.
+func (s *MQTTSink) Write(ctx context.Context, measures []Measure) error {
+	if len(measures) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.QoS > 1 {
+		return fmt.Errorf("mqtt: unsupported QoS %d", s.QoS)
+	}
+	if s.Password != "" && s.Username == "" {
+		return errors.New("mqtt: password requires a username")
+	}
+	messages := make([]mqttMessage, len(measures))
+	for i := range measures {
+		msg, err := s.message(&measures[i])
+		if err != nil {
+			return fmt.Errorf("mqtt: %w", err)
+		}
+		messages[i] = msg
+	}
+	if IsDryRun(ctx) {
+		lines := make([]string, len(messages))
+		for i, msg := range messages {
+			lines[i] = msg.topic + " " + string(msg.payload)
+		}
+		dryRun(ctx, "mqtt", fmt.Sprintf("%d messages to %s", len(messages), s.URL), strings.Join(lines, "\n"))
+		return nil
+	}
+	if err := s.publish(ctx, messages); err != nil {
+		return fmt.Errorf("mqtt: %w", err)
+	}
+	return nil
+}
+
+// mqttMessage defines a message to publish.
+type mqttMessage struct {
+	topic   string
+	payload []byte
+}
+
+// message returns message of the measure.
+func (s *MQTTSink) message(m *Measure) (mqttMessage, error) {
+	if s.topic == nil {
+		text := s.Topic
+		if text == "" {
+			text = DefaultMQTTTopic
+		}
+		t, err := template.New("topic").Parse(text)
+		if err != nil {
+			return mqttMessage{}, err
+		}
+		s.topic = t
+	}
+	var topic strings.Builder
+	if err := s.topic.Execute(&topic, m); err != nil {
+		return mqttMessage{}, err
+	}
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return mqttMessage{}, err
+	}
+	return mqttMessage{topic: topic.String(), payload: payload}, nil
+}
+
+// publish connects, publishes the messages and disconnects.
+func (s *MQTTSink) publish(ctx context.Context, messages []mqttMessage) error {
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+	deadline := time.Now().Add(s.timeout())
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+	r := bufio.NewReader(conn)
+	if _, err := conn.Write(s.connectPacket()); err != nil {
+		return err
+	}
+	packetType, body, err := readMQTTPacket(r)
+	if err != nil {
+		return err
+	}
+	if packetType != mqttConnAck || len(body) != 2 {
+		return fmt.Errorf("unexpected packet %d instead of CONNACK", packetType)
+	}
+	if body[1] != 0 {
+		return fmt.Errorf("connection refused: %s", mqttConnectReturnCode(body[1]))
+	}
+	for i, msg := range messages {
+		id := uint16(i%0xffff + 1)
+		if _, err := conn.Write(s.publishPacket(msg, id)); err != nil {
+			return err
+		}
+		if s.QoS == 0 {
+			continue
+		}
+		packetType, body, err := readMQTTPacket(r)
+		if err != nil {
+			return err
+		}
+		if packetType != mqttPubAck || len(body) != 2 || uint16(body[0])<<8|uint16(body[1]) != id {
+			return fmt.Errorf("unexpected packet %d instead of PUBACK", packetType)
+		}
+	}
+	_, err = conn.Write([]byte{mqttDisconnect << 4, 0})
+	return err
+}
+
+// dial opens a TCP or TLS connection to the broker.
+func (s *MQTTSink) dial(ctx context.Context) (net.Conn, error) {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: s.timeout()}
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		return dialer.DialContext(ctx, "tcp", hostPort(u, "1883"))
+	case "ssl", "tls", "mqtts":
+		d := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: u.Hostname()}}
+		return d.DialContext(ctx, "tcp", hostPort(u, "8883"))
+	}
+	return nil, fmt.Errorf("unsupported scheme: %s", u.Scheme)
+}
+
+func hostPort(u *url.URL, port string) string {
+	if u.Port() != "" {
+		port = u.Port()
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func (s *MQTTSink) timeout() time.Duration {
+	if s.Timeout > 0 {
+		return s.Timeout
+	}
+	return 10 * time.Second
+}
+
+// MQTT 3.1.1 control packet types.
+const (
+	mqttConnect    = 1
+	mqttConnAck    = 2
+	mqttPublish    = 3
+	mqttPubAck     = 4
+	mqttDisconnect = 14
+)
+
+// connectPacket returns CONNECT packet with a clean session and no keep alive.
+func (s *MQTTSink) connectPacket() []byte {
+	clientID := s.ClientID
+	if clientID == "" {
+		clientID = "netatmo-" + NewRequestID()[:15] // 23 bytes at most in MQTT 3.1.1
+	}
+	var body bytes.Buffer
+	writeMQTTString(&body, "MQTT")
+	body.WriteByte(4) // Protocol level 3.1.1
+	flags := byte(0x02)
+	if s.Username != "" {
+		flags |= 0x80
+	}
+	if s.Password != "" {
+		flags |= 0x40
+	}
+	body.WriteByte(flags)
+	body.Write([]byte{0, 0})
+	writeMQTTString(&body, clientID)
+	if s.Username != "" {
+		writeMQTTString(&body, s.Username)
+	}
+	if s.Password != "" {
+		writeMQTTString(&body, s.Password)
+	}
+	return mqttPacket(mqttConnect<<4, body.Bytes())
+}
+
+// publishPacket returns PUBLISH packet of the message, the id is used with QoS 1 only.
+func (s *MQTTSink) publishPacket(msg mqttMessage, id uint16) []byte {
+	header := byte(mqttPublish<<4) | s.QoS<<1
+	if s.Retain {
+		header |= 0x01
+	}
+	var body bytes.Buffer
+	writeMQTTString(&body, msg.topic)
+	if s.QoS > 0 {
+		body.Write([]byte{byte(id >> 8), byte(id)})
+	}
+	body.Write(msg.payload)
+	return mqttPacket(header, body.Bytes())
+}
+
+// mqttPacket returns the packet with the fixed header and remaining length.
+func mqttPacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		packet = append(packet, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+func writeMQTTString(b *bytes.Buffer, s string) {
+	b.Write([]byte{byte(len(s) >> 8), byte(len(s))})
+	b.WriteString(s)
+}
+
+// readMQTTPacket reads a packet, returning its type and body.
+func readMQTTPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, shift := 0, 0
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(b&0x7f) << shift
+		if b&0x80 == 0 {
+			break
+		}
+		if shift += 7; shift > 21 {
+			return 0, nil, errors.New("invalid remaining length")
+		}
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header >> 4, body, nil
+}
+
+// mqttConnectReturnCode returns description of a refused CONNACK return code.
+func mqttConnectReturnCode(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "identifier rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad user name or password"
+	case 5:
+		return "not authorized"
+	}
+	return fmt.Sprintf("return code %d", code)
+}
@@ -0,0 +1,212 @@
+package netatmo
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode"
+)
+
+// metricUnits defines unit suffixes of measurements used by metric names.
+var metricUnits = map[string]string{
+	"Temperature":  "celsius",
+	"Humidity":     "percent",
+	"CO2":          "ppm",
+	"Pressure":     "hpa",
+	"Noise":        "decibels",
+	"WindStrength": "kmh",
+	"WindAngle":    "degrees",
+	"GustStrength": "kmh",
+	"GustAngle":    "degrees",
+	"Rain":         "mm",
+	"sum_rain":     "mm",
+	"dew_point":    "celsius",
+	"humidex":      "celsius",
+}
+
+// DefaultMetricLabels defines labels attached by exporters without explicit label templates.
+var DefaultMetricLabels = map[string]string{
+	"account": "{{.Account}}",
+	"device":  "{{.DeviceID}}",
+	"module":  "{{.ModuleID}}",
+}
+
+// MetricNaming defines how exporters name metrics and their labels (tags). Templates are text/template with the
+// fields of MetricIdentity and the functions snake, lower and upper
+// (ex. "home_{{snake .Module}}_{{snake .Metric}}_{{.Unit}}" -> home_living_room_temperature_celsius).
+type MetricNaming struct {
+	Name      string            // Template of metric names, defaults to "{{snake .Metric}}"
+	Labels    map[string]string // Templates of label values keyed by label name, defaults to DefaultMetricLabels
+	Sanitizer *Sanitizer        // Rules applied to metric and label names, defaults to SanitizeSnakeCase
+}
+
+// MetricIdentity defines the values available to naming templates.
+type MetricIdentity struct {
+	Account  string
+	DeviceID string
+	ModuleID string
+	Station  string // Station name, empty unless devices are known (see MetricNamer.SetDevices)
+	Module   string // Module name, falls back to the module ID
+	Metric   string // Measurement name (ex. Temperature)
+	Unit     string // Unit suffix (ex. celsius)
+}
+
+// Sanitizer defines rules making names acceptable to a backend.
+type Sanitizer struct {
+	Invalid   *regexp.Regexp // Characters replaced by Separator, nil keeps all
+	Separator string
+	Lowercase bool
+	Prefix    string // Prepended if the name starts with a digit
+}
+
+// Predefined sanitizers.
+var (
+	// SanitizeSnakeCase lower-cases and keeps letters, digits and underscores, as Prometheus and most TSDBs expect.
+	SanitizeSnakeCase = &Sanitizer{Invalid: regexp.MustCompile(`[^a-z0-9_]+`), Separator: "_", Lowercase: true,
+		Prefix: "_"}
+	// SanitizeDotted lower-cases and keeps letters, digits, underscores and dots, as Graphite style backends expect.
+	SanitizeDotted = &Sanitizer{Invalid: regexp.MustCompile(`[^a-z0-9_.]+`), Separator: "_", Lowercase: true}
+	// SanitizeNone keeps names as is.
+	SanitizeNone = &Sanitizer{}
+)
+
+// Sanitize applies the rules to the name.
+func (s *Sanitizer) Sanitize(name string) string {
+	if s.Lowercase {
+		name = strings.ToLower(name)
+	}
+	if s.Invalid != nil {
+		name = s.Invalid.ReplaceAllString(name, s.Separator)
+		if s.Separator != "" {
+			name = strings.Trim(name, s.Separator)
+		}
+	}
+	if s.Prefix != "" && name != "" && unicode.IsDigit(rune(name[0])) {
+		name = s.Prefix + name
+	}
+	return name
+}
+
+// MetricNamer names metrics with compiled naming templates. It is safe for concurrent use.
+type MetricNamer struct {
+	name      *template.Template
+	labels    map[string]*template.Template
+	sanitizer *Sanitizer
+	mu        sync.RWMutex
+	modules   map[string]string // Module names keyed by module ID
+	stations  map[string]string // Station names keyed by device ID
+}
+
+// NewMetricNamer compiles the naming templates.
+func NewMetricNamer(naming MetricNaming) (*MetricNamer, error) {
+	if naming.Name == "" {
+		naming.Name = "{{snake .Metric}}"
+	}
+	if naming.Labels == nil {
+		naming.Labels = DefaultMetricLabels
+	}
+	if naming.Sanitizer == nil {
+		naming.Sanitizer = SanitizeSnakeCase
+	}
+	n := &MetricNamer{labels: make(map[string]*template.Template), sanitizer: naming.Sanitizer}
+	var err error
+	if n.name, err = newNamingTemplate("name", naming.Name); err != nil {
+		return nil, err
+	}
+	for label, text := range naming.Labels {
+		if n.labels[label], err = newNamingTemplate(label, text); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// SetDevices makes station and module names of the devices available to templates.
+func (n *MetricNamer) SetDevices(devices []Device) {
+	modules, stations := make(map[string]string), make(map[string]string)
+	for _, d := range devices {
+		stations[d.ID] = d.StationName
+		modules[d.ID] = d.ModuleName
+		for _, m := range d.Modules {
+			modules[m.ID] = m.ModuleName
+		}
+	}
+	n.mu.Lock()
+	n.modules, n.stations = modules, stations
+	n.mu.Unlock()
+}
+
+// Identity returns the template values of the measurement of the measure.
+func (n *MetricNamer) Identity(m *Measure, metric string) MetricIdentity {
+	n.mu.RLock()
+	module, station := n.modules[m.ModuleID], n.stations[m.DeviceID]
+	n.mu.RUnlock()
+	if module == "" {
+		module = m.ModuleID
+	}
+	return MetricIdentity{Account: m.Account, DeviceID: m.DeviceID, ModuleID: m.ModuleID, Station: station,
+		Module: module, Metric: metric, Unit: metricUnits[metric]}
+}
+
+// Name returns sanitized metric name and labels of the measurement of the measure. Labels with empty values are
+// omitted.
+func (n *MetricNamer) Name(m *Measure, metric string) (string, map[string]string, error) {
+	id := n.Identity(m, metric)
+	name, err := executeNaming(n.name, id)
+	if err != nil {
+		return "", nil, err
+	}
+	labels := make(map[string]string, len(n.labels))
+	for label, t := range n.labels {
+		value, err := executeNaming(t, id)
+		if err != nil {
+			return "", nil, err
+		}
+		if value != "" {
+			labels[n.sanitizer.Sanitize(label)] = value
+		}
+	}
+	return n.sanitizer.Sanitize(name), labels, nil
+}
+
+func newNamingTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Funcs(template.FuncMap{
+		"snake": snakeCase,
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}).Parse(text)
+}
+
+func executeNaming(t *template.Template, id MetricIdentity) (string, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, id); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// snakeCase converts names to lower snake case (ex. WindStrength -> wind_strength, Living Room -> living_room,
+// CO2 -> co2).
+func snakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) &&
+				unicode.IsUpper(runes[i-1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
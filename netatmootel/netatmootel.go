@@ -0,0 +1,64 @@
+// Package netatmootel traces Netatmo API requests of clients as OpenTelemetry spans.
+//
+//	client, err := netatmo.NewClient(ctx, clientID, clientSecret, username, password,
+//		netatmootel.WithTracerProvider(otel.GetTracerProvider()))
+package netatmootel
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/mikan/netatmo-weather-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName defines instrumentation scope name of spans.
+const TracerName = "github.com/mikan/netatmo-weather-go"
+
+// WithTracerProvider returns option of netatmo clients creating a span for each API request with the endpoint,
+// device, module and request ID attributes and the response status.
+func WithTracerProvider(provider trace.TracerProvider) netatmo.Option {
+	return netatmo.WithRequestTracer(NewTracer(provider))
+}
+
+// Tracer creates spans of API requests, implementing netatmo.RequestTracer.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer creates a tracer of the provider.
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	return &Tracer{tracer: provider.Tracer(TracerName)}
+}
+
+// StartRequest starts span of the API request, implementing netatmo.RequestTracer. The returned function ends the
+// span with the status code (0 if no response) and error.
+func (t *Tracer) StartRequest(ctx context.Context, endpoint string, query url.Values) (context.Context,
+	func(status int, err error)) {
+	attributes := []attribute.KeyValue{attribute.String("netatmo.endpoint", endpoint)}
+	if v := query.Get("device_id"); v != "" {
+		attributes = append(attributes, attribute.String("netatmo.device_id", v))
+	}
+	if v := query.Get("module_id"); v != "" {
+		attributes = append(attributes, attribute.String("netatmo.module_id", v))
+	}
+	ctx, span := t.tracer.Start(ctx, "netatmo "+endpoint, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...))
+	return ctx, func(status int, err error) {
+		if status != 0 {
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+		}
+		if id, ok := netatmo.RequestIDFromContext(ctx); ok {
+			span.SetAttributes(attribute.String("netatmo.request_id", id))
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else if status >= 400 {
+			span.SetStatus(codes.Error, "")
+		}
+		span.End()
+	}
+}
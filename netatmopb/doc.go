@@ -0,0 +1,3 @@
+// Package netatmopb contains the types generated from proto/netatmo.proto. The netatmo package converts them from
+// and to its measures and devices (see netatmo.MeasureToProto and netatmo.DeviceToProto).
+package netatmopb
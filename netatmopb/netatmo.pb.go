@@ -0,0 +1,1017 @@
+// Protocol buffers schema of measures and devices, encoded by MarshalMeasureProto, MarshalDeviceProto and
+// WriteMeasuresProto of github.com/mikan/netatmo-weather-go. Go types are generated into the netatmopb package;
+// consumers in other languages generate their types from this file.
+//
+// Field numbers are stable: add new fields with new numbers and reserve removed ones.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: proto/netatmo.proto
+
+package netatmopb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Measure mirrors netatmo.Measure. Optional fields are absent when the value is null.
+type Measure struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account        string            `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	DeviceId       string            `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	ModuleId       string            `protobuf:"bytes,3,opt,name=module_id,json=moduleId,proto3" json:"module_id,omitempty"`
+	Timestamp      int64             `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                  // Unix time in seconds
+	Temperature    *float64          `protobuf:"fixed64,5,opt,name=temperature,proto3,oneof" json:"temperature,omitempty"`                       // °C
+	Co2            *int32            `protobuf:"varint,6,opt,name=co2,proto3,oneof" json:"co2,omitempty"`                                        // ppm
+	Humidity       *int32            `protobuf:"varint,7,opt,name=humidity,proto3,oneof" json:"humidity,omitempty"`                              // %
+	Pressure       *float64          `protobuf:"fixed64,8,opt,name=pressure,proto3,oneof" json:"pressure,omitempty"`                             // hPa
+	Noise          *int32            `protobuf:"varint,9,opt,name=noise,proto3,oneof" json:"noise,omitempty"`                                    // dB
+	WindStrength   *int32            `protobuf:"varint,10,opt,name=wind_strength,json=windStrength,proto3,oneof" json:"wind_strength,omitempty"` // km/h
+	WindAngle      *int32            `protobuf:"varint,11,opt,name=wind_angle,json=windAngle,proto3,oneof" json:"wind_angle,omitempty"`          // °
+	GustStrength   *int32            `protobuf:"varint,12,opt,name=gust_strength,json=gustStrength,proto3,oneof" json:"gust_strength,omitempty"` // km/h
+	GustAngle      *int32            `protobuf:"varint,13,opt,name=gust_angle,json=gustAngle,proto3,oneof" json:"gust_angle,omitempty"`          // °
+	Co2Calibrating bool              `protobuf:"varint,14,opt,name=co2_calibrating,json=co2Calibrating,proto3" json:"co2_calibrating,omitempty"`
+	Flagged        map[string]string `protobuf:"bytes,15,rep,name=flagged,proto3" json:"flagged,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	MinTemp        *float64          `protobuf:"fixed64,16,opt,name=min_temp,json=minTemp,proto3,oneof" json:"min_temp,omitempty"`
+	MaxTemp        *float64          `protobuf:"fixed64,17,opt,name=max_temp,json=maxTemp,proto3,oneof" json:"max_temp,omitempty"`
+	MinHum         *int32            `protobuf:"varint,18,opt,name=min_hum,json=minHum,proto3,oneof" json:"min_hum,omitempty"`
+	MaxHum         *int32            `protobuf:"varint,19,opt,name=max_hum,json=maxHum,proto3,oneof" json:"max_hum,omitempty"`
+	MinPressure    *float64          `protobuf:"fixed64,20,opt,name=min_pressure,json=minPressure,proto3,oneof" json:"min_pressure,omitempty"`
+	MaxPressure    *float64          `protobuf:"fixed64,21,opt,name=max_pressure,json=maxPressure,proto3,oneof" json:"max_pressure,omitempty"`
+	MinNoise       *int32            `protobuf:"varint,22,opt,name=min_noise,json=minNoise,proto3,oneof" json:"min_noise,omitempty"`
+	MaxNoise       *int32            `protobuf:"varint,23,opt,name=max_noise,json=maxNoise,proto3,oneof" json:"max_noise,omitempty"`
+	SumRain        *float64          `protobuf:"fixed64,24,opt,name=sum_rain,json=sumRain,proto3,oneof" json:"sum_rain,omitempty"`              // mm
+	DateMaxGust    *int64            `protobuf:"varint,25,opt,name=date_max_gust,json=dateMaxGust,proto3,oneof" json:"date_max_gust,omitempty"` // Unix time in seconds
+	Rain           *float64          `protobuf:"fixed64,26,opt,name=rain,proto3,oneof" json:"rain,omitempty"`                                   // mm since the previous sample
+	DewPoint       *float64          `protobuf:"fixed64,27,opt,name=dew_point,json=dewPoint,proto3,oneof" json:"dew_point,omitempty"`           // °C
+	Humidex        *float64          `protobuf:"fixed64,28,opt,name=humidex,proto3,oneof" json:"humidex,omitempty"`                             // °C
+}
+
+func (x *Measure) Reset() {
+	*x = Measure{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_netatmo_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Measure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Measure) ProtoMessage() {}
+
+func (x *Measure) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netatmo_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Measure.ProtoReflect.Descriptor instead.
+func (*Measure) Descriptor() ([]byte, []int) {
+	return file_proto_netatmo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Measure) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *Measure) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *Measure) GetModuleId() string {
+	if x != nil {
+		return x.ModuleId
+	}
+	return ""
+}
+
+func (x *Measure) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Measure) GetTemperature() float64 {
+	if x != nil && x.Temperature != nil {
+		return *x.Temperature
+	}
+	return 0
+}
+
+func (x *Measure) GetCo2() int32 {
+	if x != nil && x.Co2 != nil {
+		return *x.Co2
+	}
+	return 0
+}
+
+func (x *Measure) GetHumidity() int32 {
+	if x != nil && x.Humidity != nil {
+		return *x.Humidity
+	}
+	return 0
+}
+
+func (x *Measure) GetPressure() float64 {
+	if x != nil && x.Pressure != nil {
+		return *x.Pressure
+	}
+	return 0
+}
+
+func (x *Measure) GetNoise() int32 {
+	if x != nil && x.Noise != nil {
+		return *x.Noise
+	}
+	return 0
+}
+
+func (x *Measure) GetWindStrength() int32 {
+	if x != nil && x.WindStrength != nil {
+		return *x.WindStrength
+	}
+	return 0
+}
+
+func (x *Measure) GetWindAngle() int32 {
+	if x != nil && x.WindAngle != nil {
+		return *x.WindAngle
+	}
+	return 0
+}
+
+func (x *Measure) GetGustStrength() int32 {
+	if x != nil && x.GustStrength != nil {
+		return *x.GustStrength
+	}
+	return 0
+}
+
+func (x *Measure) GetGustAngle() int32 {
+	if x != nil && x.GustAngle != nil {
+		return *x.GustAngle
+	}
+	return 0
+}
+
+func (x *Measure) GetCo2Calibrating() bool {
+	if x != nil {
+		return x.Co2Calibrating
+	}
+	return false
+}
+
+func (x *Measure) GetFlagged() map[string]string {
+	if x != nil {
+		return x.Flagged
+	}
+	return nil
+}
+
+func (x *Measure) GetMinTemp() float64 {
+	if x != nil && x.MinTemp != nil {
+		return *x.MinTemp
+	}
+	return 0
+}
+
+func (x *Measure) GetMaxTemp() float64 {
+	if x != nil && x.MaxTemp != nil {
+		return *x.MaxTemp
+	}
+	return 0
+}
+
+func (x *Measure) GetMinHum() int32 {
+	if x != nil && x.MinHum != nil {
+		return *x.MinHum
+	}
+	return 0
+}
+
+func (x *Measure) GetMaxHum() int32 {
+	if x != nil && x.MaxHum != nil {
+		return *x.MaxHum
+	}
+	return 0
+}
+
+func (x *Measure) GetMinPressure() float64 {
+	if x != nil && x.MinPressure != nil {
+		return *x.MinPressure
+	}
+	return 0
+}
+
+func (x *Measure) GetMaxPressure() float64 {
+	if x != nil && x.MaxPressure != nil {
+		return *x.MaxPressure
+	}
+	return 0
+}
+
+func (x *Measure) GetMinNoise() int32 {
+	if x != nil && x.MinNoise != nil {
+		return *x.MinNoise
+	}
+	return 0
+}
+
+func (x *Measure) GetMaxNoise() int32 {
+	if x != nil && x.MaxNoise != nil {
+		return *x.MaxNoise
+	}
+	return 0
+}
+
+func (x *Measure) GetSumRain() float64 {
+	if x != nil && x.SumRain != nil {
+		return *x.SumRain
+	}
+	return 0
+}
+
+func (x *Measure) GetDateMaxGust() int64 {
+	if x != nil && x.DateMaxGust != nil {
+		return *x.DateMaxGust
+	}
+	return 0
+}
+
+func (x *Measure) GetRain() float64 {
+	if x != nil && x.Rain != nil {
+		return *x.Rain
+	}
+	return 0
+}
+
+func (x *Measure) GetDewPoint() float64 {
+	if x != nil && x.DewPoint != nil {
+		return *x.DewPoint
+	}
+	return 0
+}
+
+func (x *Measure) GetHumidex() float64 {
+	if x != nil && x.Humidex != nil {
+		return *x.Humidex
+	}
+	return 0
+}
+
+// MeasureBatch holds measures sent together (ex. a Kafka message or a gRPC response).
+type MeasureBatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Measures []*Measure `protobuf:"bytes,1,rep,name=measures,proto3" json:"measures,omitempty"`
+}
+
+func (x *MeasureBatch) Reset() {
+	*x = MeasureBatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_netatmo_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MeasureBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MeasureBatch) ProtoMessage() {}
+
+func (x *MeasureBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netatmo_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MeasureBatch.ProtoReflect.Descriptor instead.
+func (*MeasureBatch) Descriptor() ([]byte, []int) {
+	return file_proto_netatmo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MeasureBatch) GetMeasures() []*Measure {
+	if x != nil {
+		return x.Measures
+	}
+	return nil
+}
+
+// Place mirrors netatmo.Place.
+type Place struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Altitude int32     `protobuf:"varint,1,opt,name=altitude,proto3" json:"altitude,omitempty"`
+	City     string    `protobuf:"bytes,2,opt,name=city,proto3" json:"city,omitempty"`
+	Country  string    `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	Timezone string    `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Location []float64 `protobuf:"fixed64,5,rep,packed,name=location,proto3" json:"location,omitempty"` // Lon, Lat
+}
+
+func (x *Place) Reset() {
+	*x = Place{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_netatmo_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Place) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Place) ProtoMessage() {}
+
+func (x *Place) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netatmo_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Place.ProtoReflect.Descriptor instead.
+func (*Place) Descriptor() ([]byte, []int) {
+	return file_proto_netatmo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Place) GetAltitude() int32 {
+	if x != nil {
+		return x.Altitude
+	}
+	return 0
+}
+
+func (x *Place) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Place) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Place) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Place) GetLocation() []float64 {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+// Module mirrors netatmo.Module without dashboard data, which is carried by measures.
+type Module struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id             string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type           string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	ModuleName     string   `protobuf:"bytes,3,opt,name=module_name,json=moduleName,proto3" json:"module_name,omitempty"`
+	DataType       []string `protobuf:"bytes,4,rep,name=data_type,json=dataType,proto3" json:"data_type,omitempty"`
+	LastSetup      int64    `protobuf:"varint,5,opt,name=last_setup,json=lastSetup,proto3" json:"last_setup,omitempty"`
+	Reachable      bool     `protobuf:"varint,6,opt,name=reachable,proto3" json:"reachable,omitempty"`
+	Firmware       int32    `protobuf:"varint,7,opt,name=firmware,proto3" json:"firmware,omitempty"`
+	LastMessage    int64    `protobuf:"varint,8,opt,name=last_message,json=lastMessage,proto3" json:"last_message,omitempty"`
+	LastSeen       int64    `protobuf:"varint,9,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	RfStatus       int32    `protobuf:"varint,10,opt,name=rf_status,json=rfStatus,proto3" json:"rf_status,omitempty"`
+	BatteryVp      int32    `protobuf:"varint,11,opt,name=battery_vp,json=batteryVp,proto3" json:"battery_vp,omitempty"`
+	BatteryPercent int32    `protobuf:"varint,12,opt,name=battery_percent,json=batteryPercent,proto3" json:"battery_percent,omitempty"`
+}
+
+func (x *Module) Reset() {
+	*x = Module{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_netatmo_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Module) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Module) ProtoMessage() {}
+
+func (x *Module) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netatmo_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Module.ProtoReflect.Descriptor instead.
+func (*Module) Descriptor() ([]byte, []int) {
+	return file_proto_netatmo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Module) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Module) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Module) GetModuleName() string {
+	if x != nil {
+		return x.ModuleName
+	}
+	return ""
+}
+
+func (x *Module) GetDataType() []string {
+	if x != nil {
+		return x.DataType
+	}
+	return nil
+}
+
+func (x *Module) GetLastSetup() int64 {
+	if x != nil {
+		return x.LastSetup
+	}
+	return 0
+}
+
+func (x *Module) GetReachable() bool {
+	if x != nil {
+		return x.Reachable
+	}
+	return false
+}
+
+func (x *Module) GetFirmware() int32 {
+	if x != nil {
+		return x.Firmware
+	}
+	return 0
+}
+
+func (x *Module) GetLastMessage() int64 {
+	if x != nil {
+		return x.LastMessage
+	}
+	return 0
+}
+
+func (x *Module) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+func (x *Module) GetRfStatus() int32 {
+	if x != nil {
+		return x.RfStatus
+	}
+	return 0
+}
+
+func (x *Module) GetBatteryVp() int32 {
+	if x != nil {
+		return x.BatteryVp
+	}
+	return 0
+}
+
+func (x *Module) GetBatteryPercent() int32 {
+	if x != nil {
+		return x.BatteryPercent
+	}
+	return 0
+}
+
+// Device mirrors netatmo.Device without dashboard data, which is carried by measures.
+type Device struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CipherId        string    `protobuf:"bytes,2,opt,name=cipher_id,json=cipherId,proto3" json:"cipher_id,omitempty"`
+	DateSetup       int64     `protobuf:"varint,3,opt,name=date_setup,json=dateSetup,proto3" json:"date_setup,omitempty"`
+	LastSetup       int64     `protobuf:"varint,4,opt,name=last_setup,json=lastSetup,proto3" json:"last_setup,omitempty"`
+	Type            string    `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	LastStatusStore int64     `protobuf:"varint,6,opt,name=last_status_store,json=lastStatusStore,proto3" json:"last_status_store,omitempty"`
+	ModuleName      string    `protobuf:"bytes,7,opt,name=module_name,json=moduleName,proto3" json:"module_name,omitempty"`
+	Firmware        int32     `protobuf:"varint,8,opt,name=firmware,proto3" json:"firmware,omitempty"`
+	LastUpgrade     int64     `protobuf:"varint,9,opt,name=last_upgrade,json=lastUpgrade,proto3" json:"last_upgrade,omitempty"`
+	WifiStatus      int32     `protobuf:"varint,10,opt,name=wifi_status,json=wifiStatus,proto3" json:"wifi_status,omitempty"`
+	Reachable       bool      `protobuf:"varint,11,opt,name=reachable,proto3" json:"reachable,omitempty"`
+	Co2Calibrating  bool      `protobuf:"varint,12,opt,name=co2_calibrating,json=co2Calibrating,proto3" json:"co2_calibrating,omitempty"`
+	StationName     string    `protobuf:"bytes,13,opt,name=station_name,json=stationName,proto3" json:"station_name,omitempty"`
+	DataType        []string  `protobuf:"bytes,14,rep,name=data_type,json=dataType,proto3" json:"data_type,omitempty"`
+	Place           *Place    `protobuf:"bytes,15,opt,name=place,proto3" json:"place,omitempty"`
+	Modules         []*Module `protobuf:"bytes,16,rep,name=modules,proto3" json:"modules,omitempty"`
+	Account         string    `protobuf:"bytes,17,opt,name=account,proto3" json:"account,omitempty"`
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_netatmo_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_netatmo_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_proto_netatmo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Device) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Device) GetCipherId() string {
+	if x != nil {
+		return x.CipherId
+	}
+	return ""
+}
+
+func (x *Device) GetDateSetup() int64 {
+	if x != nil {
+		return x.DateSetup
+	}
+	return 0
+}
+
+func (x *Device) GetLastSetup() int64 {
+	if x != nil {
+		return x.LastSetup
+	}
+	return 0
+}
+
+func (x *Device) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Device) GetLastStatusStore() int64 {
+	if x != nil {
+		return x.LastStatusStore
+	}
+	return 0
+}
+
+func (x *Device) GetModuleName() string {
+	if x != nil {
+		return x.ModuleName
+	}
+	return ""
+}
+
+func (x *Device) GetFirmware() int32 {
+	if x != nil {
+		return x.Firmware
+	}
+	return 0
+}
+
+func (x *Device) GetLastUpgrade() int64 {
+	if x != nil {
+		return x.LastUpgrade
+	}
+	return 0
+}
+
+func (x *Device) GetWifiStatus() int32 {
+	if x != nil {
+		return x.WifiStatus
+	}
+	return 0
+}
+
+func (x *Device) GetReachable() bool {
+	if x != nil {
+		return x.Reachable
+	}
+	return false
+}
+
+func (x *Device) GetCo2Calibrating() bool {
+	if x != nil {
+		return x.Co2Calibrating
+	}
+	return false
+}
+
+func (x *Device) GetStationName() string {
+	if x != nil {
+		return x.StationName
+	}
+	return ""
+}
+
+func (x *Device) GetDataType() []string {
+	if x != nil {
+		return x.DataType
+	}
+	return nil
+}
+
+func (x *Device) GetPlace() *Place {
+	if x != nil {
+		return x.Place
+	}
+	return nil
+}
+
+func (x *Device) GetModules() []*Module {
+	if x != nil {
+		return x.Modules
+	}
+	return nil
+}
+
+func (x *Device) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+var File_proto_netatmo_proto protoreflect.FileDescriptor
+
+var file_proto_netatmo_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6e, 0x65, 0x74, 0x61, 0x74, 0x6d, 0x6f, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x6e, 0x65, 0x74, 0x61, 0x74, 0x6d, 0x6f, 0x2e, 0x76,
+	0x31, 0x22, 0xb6, 0x0a, 0x0a, 0x07, 0x4d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x49,
+	0x64, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12,
+	0x25, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x63, 0x6f, 0x32, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x03, 0x63, 0x6f, 0x32, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a,
+	0x08, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x02, 0x52, 0x08, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12, 0x1f,
+	0x0a, 0x08, 0x70, 0x72, 0x65, 0x73, 0x73, 0x75, 0x72, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x03, 0x52, 0x08, 0x70, 0x72, 0x65, 0x73, 0x73, 0x75, 0x72, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x19, 0x0a, 0x05, 0x6e, 0x6f, 0x69, 0x73, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x48, 0x04,
+	0x52, 0x05, 0x6e, 0x6f, 0x69, 0x73, 0x65, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x77, 0x69,
+	0x6e, 0x64, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x05, 0x52, 0x0c, 0x77, 0x69, 0x6e, 0x64, 0x53, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74,
+	0x68, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x77, 0x69, 0x6e, 0x64, 0x5f, 0x61, 0x6e, 0x67,
+	0x6c, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x48, 0x06, 0x52, 0x09, 0x77, 0x69, 0x6e, 0x64,
+	0x41, 0x6e, 0x67, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x67, 0x75, 0x73, 0x74,
+	0x5f, 0x73, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x07, 0x52, 0x0c, 0x67, 0x75, 0x73, 0x74, 0x53, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x88,
+	0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x67, 0x75, 0x73, 0x74, 0x5f, 0x61, 0x6e, 0x67, 0x6c, 0x65,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x48, 0x08, 0x52, 0x09, 0x67, 0x75, 0x73, 0x74, 0x41, 0x6e,
+	0x67, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x32, 0x5f, 0x63, 0x61,
+	0x6c, 0x69, 0x62, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0e, 0x63, 0x6f, 0x32, 0x43, 0x61, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12,
+	0x3a, 0x0a, 0x07, 0x66, 0x6c, 0x61, 0x67, 0x67, 0x65, 0x64, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x20, 0x2e, 0x6e, 0x65, 0x74, 0x61, 0x74, 0x6d, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65,
+	0x61, 0x73, 0x75, 0x72, 0x65, 0x2e, 0x46, 0x6c, 0x61, 0x67, 0x67, 0x65, 0x64, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x07, 0x66, 0x6c, 0x61, 0x67, 0x67, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x08, 0x6d,
+	0x69, 0x6e, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x18, 0x10, 0x20, 0x01, 0x28, 0x01, 0x48, 0x09, 0x52,
+	0x07, 0x6d, 0x69, 0x6e, 0x54, 0x65, 0x6d, 0x70, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x08, 0x6d,
+	0x61, 0x78, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x18, 0x11, 0x20, 0x01, 0x28, 0x01, 0x48, 0x0a, 0x52,
+	0x07, 0x6d, 0x61, 0x78, 0x54, 0x65, 0x6d, 0x70, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a, 0x07, 0x6d,
+	0x69, 0x6e, 0x5f, 0x68, 0x75, 0x6d, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x48, 0x0b, 0x52, 0x06,
+	0x6d, 0x69, 0x6e, 0x48, 0x75, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a, 0x07, 0x6d, 0x61, 0x78,
+	0x5f, 0x68, 0x75, 0x6d, 0x18, 0x13, 0x20, 0x01, 0x28, 0x05, 0x48, 0x0c, 0x52, 0x06, 0x6d, 0x61,
+	0x78, 0x48, 0x75, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x6d, 0x69, 0x6e, 0x5f, 0x70,
+	0x72, 0x65, 0x73, 0x73, 0x75, 0x72, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x01, 0x48, 0x0d, 0x52,
+	0x0b, 0x6d, 0x69, 0x6e, 0x50, 0x72, 0x65, 0x73, 0x73, 0x75, 0x72, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x26, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x72, 0x65, 0x73, 0x73, 0x75, 0x72, 0x65, 0x18,
+	0x15, 0x20, 0x01, 0x28, 0x01, 0x48, 0x0e, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x50, 0x72, 0x65, 0x73,
+	0x73, 0x75, 0x72, 0x65, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x6d, 0x69, 0x6e, 0x5f, 0x6e,
+	0x6f, 0x69, 0x73, 0x65, 0x18, 0x16, 0x20, 0x01, 0x28, 0x05, 0x48, 0x0f, 0x52, 0x08, 0x6d, 0x69,
+	0x6e, 0x4e, 0x6f, 0x69, 0x73, 0x65, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x6d, 0x61, 0x78,
+	0x5f, 0x6e, 0x6f, 0x69, 0x73, 0x65, 0x18, 0x17, 0x20, 0x01, 0x28, 0x05, 0x48, 0x10, 0x52, 0x08,
+	0x6d, 0x61, 0x78, 0x4e, 0x6f, 0x69, 0x73, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x08, 0x73,
+	0x75, 0x6d, 0x5f, 0x72, 0x61, 0x69, 0x6e, 0x18, 0x18, 0x20, 0x01, 0x28, 0x01, 0x48, 0x11, 0x52,
+	0x07, 0x73, 0x75, 0x6d, 0x52, 0x61, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0d, 0x64,
+	0x61, 0x74, 0x65, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x67, 0x75, 0x73, 0x74, 0x18, 0x19, 0x20, 0x01,
+	0x28, 0x03, 0x48, 0x12, 0x52, 0x0b, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x78, 0x47, 0x75, 0x73,
+	0x74, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x72, 0x61, 0x69, 0x6e, 0x18, 0x1a, 0x20, 0x01,
+	0x28, 0x01, 0x48, 0x13, 0x52, 0x04, 0x72, 0x61, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a,
+	0x09, 0x64, 0x65, 0x77, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x14, 0x52, 0x08, 0x64, 0x65, 0x77, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12,
+	0x1d, 0x0a, 0x07, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x65, 0x78, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x15, 0x52, 0x07, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x65, 0x78, 0x88, 0x01, 0x01, 0x1a, 0x3a,
+	0x0a, 0x0c, 0x46, 0x6c, 0x61, 0x67, 0x67, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x74,
+	0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x63,
+	0x6f, 0x32, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x42,
+	0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x72, 0x65, 0x73, 0x73, 0x75, 0x72, 0x65, 0x42, 0x08, 0x0a, 0x06,
+	0x5f, 0x6e, 0x6f, 0x69, 0x73, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x5f,
+	0x73, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x77, 0x69, 0x6e,
+	0x64, 0x5f, 0x61, 0x6e, 0x67, 0x6c, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x67, 0x75, 0x73, 0x74,
+	0x5f, 0x73, 0x74, 0x72, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x67, 0x75,
+	0x73, 0x74, 0x5f, 0x61, 0x6e, 0x67, 0x6c, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6d, 0x69, 0x6e,
+	0x5f, 0x74, 0x65, 0x6d, 0x70, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x65,
+	0x6d, 0x70, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x68, 0x75, 0x6d, 0x42, 0x0a,
+	0x0a, 0x08, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x68, 0x75, 0x6d, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d,
+	0x69, 0x6e, 0x5f, 0x70, 0x72, 0x65, 0x73, 0x73, 0x75, 0x72, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f,
+	0x6d, 0x61, 0x78, 0x5f, 0x70, 0x72, 0x65, 0x73, 0x73, 0x75, 0x72, 0x65, 0x42, 0x0c, 0x0a, 0x0a,
+	0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x6e, 0x6f, 0x69, 0x73, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6d,
+	0x61, 0x78, 0x5f, 0x6e, 0x6f, 0x69, 0x73, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73, 0x75, 0x6d,
+	0x5f, 0x72, 0x61, 0x69, 0x6e, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x6d,
+	0x61, 0x78, 0x5f, 0x67, 0x75, 0x73, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x72, 0x61, 0x69, 0x6e,
+	0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x64, 0x65, 0x77, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x0a,
+	0x0a, 0x08, 0x5f, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x65, 0x78, 0x22, 0x3f, 0x0a, 0x0c, 0x4d, 0x65,
+	0x61, 0x73, 0x75, 0x72, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x2f, 0x0a, 0x08, 0x6d, 0x65,
+	0x61, 0x73, 0x75, 0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6e,
+	0x65, 0x74, 0x61, 0x74, 0x6d, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x61, 0x73, 0x75, 0x72,
+	0x65, 0x52, 0x08, 0x6d, 0x65, 0x61, 0x73, 0x75, 0x72, 0x65, 0x73, 0x22, 0x89, 0x01, 0x0a, 0x05,
+	0x50, 0x6c, 0x61, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x61, 0x6c, 0x74, 0x69, 0x74, 0x75, 0x64,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x63, 0x69, 0x74, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x03, 0x28, 0x01, 0x52, 0x08, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xe8, 0x02, 0x0a, 0x06, 0x4d, 0x6f, 0x64, 0x75,
+	0x6c, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x6f, 0x64,
+	0x75, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x61, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x74,
+	0x75, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65,
+	0x74, 0x75, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x61, 0x63, 0x68, 0x61, 0x62, 0x6c, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x72, 0x65, 0x61, 0x63, 0x68, 0x61, 0x62, 0x6c,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x72, 0x6d, 0x77, 0x61, 0x72, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x66, 0x69, 0x72, 0x6d, 0x77, 0x61, 0x72, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x12, 0x1b, 0x0a,
+	0x09, 0x72, 0x66, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x72, 0x66, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61,
+	0x74, 0x74, 0x65, 0x72, 0x79, 0x5f, 0x76, 0x70, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09,
+	0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x56, 0x70, 0x12, 0x27, 0x0a, 0x0f, 0x62, 0x61, 0x74,
+	0x74, 0x65, 0x72, 0x79, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0e, 0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x50, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x22, 0xac, 0x04, 0x0a, 0x06, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x61,
+	0x74, 0x65, 0x5f, 0x73, 0x65, 0x74, 0x75, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x64, 0x61, 0x74, 0x65, 0x53, 0x65, 0x74, 0x75, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x61, 0x73,
+	0x74, 0x5f, 0x73, 0x65, 0x74, 0x75, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6c,
+	0x61, 0x73, 0x74, 0x53, 0x65, 0x74, 0x75, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2a, 0x0a, 0x11,
+	0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x6f, 0x64, 0x75,
+	0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d,
+	0x6f, 0x64, 0x75, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x72,
+	0x6d, 0x77, 0x61, 0x72, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x66, 0x69, 0x72,
+	0x6d, 0x77, 0x61, 0x72, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x75, 0x70,
+	0x67, 0x72, 0x61, 0x64, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6c, 0x61, 0x73,
+	0x74, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x69, 0x66, 0x69,
+	0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x77,
+	0x69, 0x66, 0x69, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x61,
+	0x63, 0x68, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x72, 0x65,
+	0x61, 0x63, 0x68, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x32, 0x5f, 0x63,
+	0x61, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0e, 0x63, 0x6f, 0x32, 0x43, 0x61, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67,
+	0x12, 0x21, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x0e, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x27, 0x0a, 0x05, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x6e, 0x65, 0x74, 0x61, 0x74, 0x6d, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61,
+	0x63, 0x65, 0x52, 0x05, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x6d, 0x6f, 0x64,
+	0x75, 0x6c, 0x65, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6e, 0x65, 0x74,
+	0x61, 0x74, 0x6d, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x07,
+	0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x6d, 0x69, 0x6b, 0x61, 0x6e, 0x2f, 0x6e, 0x65, 0x74, 0x61, 0x74, 0x6d, 0x6f, 0x2d, 0x77, 0x65,
+	0x61, 0x74, 0x68, 0x65, 0x72, 0x2d, 0x67, 0x6f, 0x2f, 0x6e, 0x65, 0x74, 0x61, 0x74, 0x6d, 0x6f,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_netatmo_proto_rawDescOnce sync.Once
+	file_proto_netatmo_proto_rawDescData = file_proto_netatmo_proto_rawDesc
+)
+
+func file_proto_netatmo_proto_rawDescGZIP() []byte {
+	file_proto_netatmo_proto_rawDescOnce.Do(func() {
+		file_proto_netatmo_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_netatmo_proto_rawDescData)
+	})
+	return file_proto_netatmo_proto_rawDescData
+}
+
+var file_proto_netatmo_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_netatmo_proto_goTypes = []interface{}{
+	(*Measure)(nil),      // 0: netatmo.v1.Measure
+	(*MeasureBatch)(nil), // 1: netatmo.v1.MeasureBatch
+	(*Place)(nil),        // 2: netatmo.v1.Place
+	(*Module)(nil),       // 3: netatmo.v1.Module
+	(*Device)(nil),       // 4: netatmo.v1.Device
+	nil,                  // 5: netatmo.v1.Measure.FlaggedEntry
+}
+var file_proto_netatmo_proto_depIdxs = []int32{
+	5, // 0: netatmo.v1.Measure.flagged:type_name -> netatmo.v1.Measure.FlaggedEntry
+	0, // 1: netatmo.v1.MeasureBatch.measures:type_name -> netatmo.v1.Measure
+	2, // 2: netatmo.v1.Device.place:type_name -> netatmo.v1.Place
+	3, // 3: netatmo.v1.Device.modules:type_name -> netatmo.v1.Module
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_proto_netatmo_proto_init() }
+func file_proto_netatmo_proto_init() {
+	if File_proto_netatmo_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_netatmo_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Measure); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_netatmo_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MeasureBatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_netatmo_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Place); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_netatmo_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Module); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_netatmo_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Device); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_proto_netatmo_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_netatmo_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_netatmo_proto_goTypes,
+		DependencyIndexes: file_proto_netatmo_proto_depIdxs,
+		MessageInfos:      file_proto_netatmo_proto_msgTypes,
+	}.Build()
+	File_proto_netatmo_proto = out.File
+	file_proto_netatmo_proto_rawDesc = nil
+	file_proto_netatmo_proto_goTypes = nil
+	file_proto_netatmo_proto_depIdxs = nil
+}
@@ -0,0 +1,98 @@
+// Package netatmoprom records Netatmo API requests of clients as Prometheus metrics.
+//
+//	metrics := netatmoprom.New("")
+//	prometheus.MustRegister(metrics)
+//	client, err := netatmo.NewClient(ctx, clientID, clientSecret, username, password, metrics.Option())
+package netatmoprom
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/mikan/netatmo-weather-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultNamespace prefixes metric names if New is given no namespace.
+const DefaultNamespace = "netatmo"
+
+// Metrics counts requests and errors and observes latency per endpoint. It implements netatmo.MetricsHook and
+// prometheus.Collector, so a single value can instrument several clients and be registered on any registry.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// New creates metrics named <namespace>_api_requests_total (by endpoint and HTTP status code, 0 if no response),
+// <namespace>_api_errors_total (by endpoint and error class, see netatmo.ErrorClassTransport) and
+// <namespace>_api_request_duration_seconds (by endpoint). Empty namespace means DefaultNamespace.
+func New(namespace string) *Metrics {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "api",
+			Name:      "requests_total",
+			Help:      "Number of Netatmo API requests.",
+		}, []string{"endpoint", "code"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "api",
+			Name:      "errors_total",
+			Help:      "Number of failed Netatmo API requests.",
+		}, []string{"endpoint", "class"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "api",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of Netatmo API requests.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"endpoint"}),
+	}
+}
+
+// Option returns option of netatmo clients recording their requests.
+func (m *Metrics) Option() netatmo.Option {
+	return netatmo.WithMetricsHook(m)
+}
+
+// ObserveRequest records an API request, implementing netatmo.MetricsHook.
+func (m *Metrics) ObserveRequest(endpoint string, duration time.Duration, status int, err error) {
+	m.requests.WithLabelValues(endpoint, strconv.Itoa(status)).Inc()
+	m.duration.WithLabelValues(endpoint).Observe(duration.Seconds())
+	if class := errorClass(status, err); class != "" {
+		m.errors.WithLabelValues(endpoint, class).Inc()
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.errors.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.errors.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+// errorClass returns error class of the request like netatmo.ClientStats, empty if it succeeded.
+func errorClass(status int, err error) string {
+	var apiErr *netatmo.APIError
+	switch {
+	case status >= 500:
+		return netatmo.ErrorClassServer
+	case status >= 400:
+		return netatmo.ErrorClassClient
+	case err != nil && !errors.As(err, &apiErr):
+		return netatmo.ErrorClassTransport
+	}
+	return ""
+}
@@ -0,0 +1,189 @@
+package netatmo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// alertTitle returns a short title of the alert (ex. "Warning: frost").
+func alertTitle(a *Alert) string {
+	severity := string(a.Severity)
+	if severity != "" {
+		severity = strings.ToUpper(severity[:1]) + severity[1:]
+	}
+	return severity + ": " + a.Kind
+}
+
+// alertBody returns the message of the alert with the module and the time.
+func alertBody(a *Alert) string {
+	body := a.Message
+	if a.ModuleID != "" {
+		body += "\nModule: " + a.ModuleID
+	}
+	if a.Time != 0 {
+		body += "\nTime: " + time.Unix(a.Time, 0).UTC().Format(time.RFC3339)
+	}
+	return body
+}
+
+// MultiNotifier delivers alerts with every notifier, returning the first error after trying all of them.
+type MultiNotifier []Notifier
+
+// Notify delivers the alert with each notifier.
+func (m MultiNotifier) Notify(ctx context.Context, alert Alert) error {
+	var first error
+	for _, n := range m {
+		if err := n.Notify(ctx, alert); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// NtfyNotifier publishes alerts to a topic of ntfy (https://ntfy.sh or a self-hosted server). Critical alerts are sent
+// with urgent priority and warnings with high priority.
+type NtfyNotifier struct {
+	Server     string // Defaults to https://ntfy.sh
+	Topic      string
+	Token      string       // Access token, optional
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+}
+
+// Notify publishes the alert.
+func (n *NtfyNotifier) Notify(ctx context.Context, alert Alert) error {
+	server := n.Server
+	if server == "" {
+		server = "https://ntfy.sh"
+	}
+	endpoint := strings.TrimSuffix(server, "/") + "/" + url.PathEscape(n.Topic)
+	body := alertBody(&alert)
+	if dryRun(ctx, "ntfy", "alert to "+endpoint, body) {
+		return nil
+	}
+	priority := map[Severity]string{SeverityCritical: "urgent", SeverityWarning: "high"}[alert.Severity]
+	_, err := sinkPost(ctx, n.HTTPClient, endpoint, "text/plain; charset=utf-8", strings.NewReader(body),
+		func(req *http.Request) {
+			req.Header.Set("Title", alertTitle(&alert))
+			req.Header.Set("Tags", alert.Kind)
+			if priority != "" {
+				req.Header.Set("Priority", priority)
+			}
+			if n.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+n.Token)
+			}
+		})
+	if err != nil {
+		return fmt.Errorf("ntfy: %w", err)
+	}
+	return nil
+}
+
+// pushoverURL defines Pushover message API endpoint.
+// Reference: https://pushover.net/api
+const pushoverURL = "https://api.pushover.net/1/messages.json"
+
+// PushoverNotifier sends alerts with Pushover. Critical alerts are sent with high priority, bypassing quiet hours, and
+// info alerts with low priority.
+type PushoverNotifier struct {
+	Token      string       // Application API token
+	User       string       // User or group key
+	Device     string       // Device name, optional, defaults to all devices of the user
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+}
+
+// Notify sends the alert.
+func (n *PushoverNotifier) Notify(ctx context.Context, alert Alert) error {
+	form := url.Values{}
+	form.Set("token", n.Token)
+	form.Set("user", n.User)
+	form.Set("title", alertTitle(&alert))
+	form.Set("message", alertBody(&alert))
+	if n.Device != "" {
+		form.Set("device", n.Device)
+	}
+	if alert.Time != 0 {
+		form.Set("timestamp", strconv.FormatInt(alert.Time, 10))
+	}
+	switch alert.Severity {
+	case SeverityCritical:
+		form.Set("priority", "1")
+	case SeverityInfo:
+		form.Set("priority", "-1")
+	}
+	if dryRun(ctx, "pushover", "alert to "+n.User, maskQuery(form, "token", "user")) {
+		return nil
+	}
+	_, err := sinkPost(ctx, n.HTTPClient, pushoverURL, "application/x-www-form-urlencoded",
+		strings.NewReader(form.Encode()), nil)
+	if err != nil {
+		return fmt.Errorf("pushover: %w", err)
+	}
+	return nil
+}
+
+// SlackNotifier posts alerts to a Slack incoming webhook.
+type SlackNotifier struct {
+	WebhookURL string       // Incoming webhook URL (ex. https://hooks.slack.com/services/...)
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+}
+
+// Notify posts the alert.
+func (n *SlackNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(map[string]string{"text": "*" + alertTitle(&alert) + "*\n" + alertBody(&alert)})
+	if err != nil {
+		return err
+	}
+	if dryRun(ctx, "slack", "alert to webhook", string(body)) {
+		return nil
+	}
+	if _, err := sinkPost(ctx, n.HTTPClient, n.WebhookURL, "application/json", bytes.NewReader(body), nil); err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	return nil
+}
+
+// SMTPNotifier sends alerts as plain text emails.
+type SMTPNotifier struct {
+	Addr     string // Server host and port (ex. smtp.example.com:587), STARTTLS is used when offered
+	Username string // PLAIN authentication, optional
+	Password string
+	From     string
+	To       []string
+}
+
+// Notify sends the alert. The context is not used to cancel sending, as net/smtp does not support it.
+func (n *SMTPNotifier) Notify(ctx context.Context, alert Alert) error {
+	var msg strings.Builder
+	msg.WriteString("From: " + n.From + "\r\n")
+	msg.WriteString("To: " + strings.Join(n.To, ", ") + "\r\n")
+	msg.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", alertTitle(&alert)) + "\r\n")
+	msg.WriteString("Date: " + ClockFromContext(ctx).Now().Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.Replace(alertBody(&alert), "\n", "\r\n", -1) + "\r\n")
+	if dryRun(ctx, "smtp", "alert to "+strings.Join(n.To, ", "), strings.Replace(msg.String(), "\r\n", "\n", -1)) {
+		return nil
+	}
+	var auth smtp.Auth
+	if n.Username != "" {
+		host, _, err := net.SplitHostPort(n.Addr)
+		if err != nil {
+			return fmt.Errorf("smtp: %w", err)
+		}
+		auth = smtp.PlainAuth("", n.Username, n.Password, host)
+	}
+	if err := smtp.SendMail(n.Addr, auth, n.From, n.To, []byte(msg.String())); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package netatmo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"golang.org/x/oauth2"
+)
+
+// Errors matched by OAuthError with errors.Is, telling rejected credentials apart from transient failures.
+var (
+	ErrInvalidGrant  = errors.New("invalid grant")  // Wrong username/password, or revoked or expired refresh token
+	ErrInvalidClient = errors.New("invalid client") // Wrong client ID or client secret
+	ErrInvalidScope  = errors.New("invalid scope")  // Scope not allowed for the application
+)
+
+// OAuthError defines error response of the token endpoint, returned by constructors and wrapped by errors of API
+// calls failing to refresh the token.
+type OAuthError struct {
+	Code        string // Error code of the response (ex. "invalid_grant")
+	Description string // Nullable
+	StatusCode  int
+	Err         *oauth2.RetrieveError
+}
+
+// Error returns the error code with the description.
+func (e *OAuthError) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("oauth2: %s (status %d)", e.Code, e.StatusCode)
+	}
+	return fmt.Sprintf("oauth2: %s: %s (status %d)", e.Code, e.Description, e.StatusCode)
+}
+
+// Is reports whether the error code corresponds to ErrInvalidGrant, ErrInvalidClient or ErrInvalidScope.
+func (e *OAuthError) Is(target error) bool {
+	switch e.Code {
+	case "invalid_grant":
+		return target == ErrInvalidGrant
+	case "invalid_client":
+		return target == ErrInvalidClient
+	case "invalid_scope":
+		return target == ErrInvalidScope
+	}
+	return false
+}
+
+// Unwrap returns the underlying oauth2 error.
+func (e *OAuthError) Unwrap() error {
+	return e.Err
+}
+
+// oauthError converts error response of the token endpoint to OAuthError, other errors are returned as is.
+func oauthError(err error) error {
+	var retrieveErr *oauth2.RetrieveError
+	if !errors.As(err, &retrieveErr) || retrieveErr.Response == nil {
+		return err
+	}
+	var body struct {
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if json.Unmarshal(retrieveErr.Body, &body) != nil {
+		values, _ := url.ParseQuery(string(retrieveErr.Body))
+		body.Error, body.Description = values.Get("error"), values.Get("error_description")
+	}
+	if body.Error == "" {
+		return err
+	}
+	return &OAuthError{Code: body.Error, Description: body.Description, StatusCode: retrieveErr.Response.StatusCode,
+		Err: retrieveErr}
+}
+
+// oauthErrorTokenSource converts refresh failures to OAuthError.
+type oauthErrorTokenSource struct {
+	source oauth2.TokenSource
+}
+
+func (s oauthErrorTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, oauthError(err)
+	}
+	return token, nil
+}
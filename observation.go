@@ -0,0 +1,95 @@
+package netatmo
+
+// Observation defines current conditions of a station in metric units, as uploaded to citizen weather networks.
+type Observation struct {
+	DeviceID          string
+	Time              int64    // Unix time of the latest sample
+	Latitude          float64  // Station location
+	Longitude         float64  // Station location
+	Altitude          int      // Station altitude in m
+	Temperature       *float64 // Nullable, outdoor °C
+	Humidity          *float64 // Nullable, outdoor %
+	DewPoint          *float64 // Nullable, outdoor °C
+	Pressure          *float64 // Nullable, sea level hPa
+	AbsolutePressure  *float64 // Nullable, station level hPa
+	WindSpeed         *float64 // Nullable, km/h
+	WindAngle         *float64 // Nullable, degrees
+	GustSpeed         *float64 // Nullable, km/h
+	GustAngle         *float64 // Nullable, degrees
+	RainLastHour      *float64 // Nullable, mm
+	RainToday         *float64 // Nullable, mm since local midnight
+	IndoorTemperature *float64 // Nullable, °C
+	IndoorHumidity    *float64 // Nullable, %
+	IndoorCO2         *float64 // Nullable, ppm
+	IndoorNoise       *float64 // Nullable, dB
+}
+
+// NewObservation gathers current conditions of the device and its outdoor, wind and rain modules.
+func NewObservation(device *Device) Observation {
+	o := Observation{DeviceID: device.ID, Latitude: device.Place.Latitude(), Longitude: device.Place.Longitude(),
+		Altitude: device.Place.Altitude}
+	if data := device.DashboardData; data != nil {
+		o.Time = data.UTCTime
+		o.Pressure = data.Pressure
+		o.AbsolutePressure = data.AbsolutePressure
+		o.IndoorTemperature = data.Temperature
+		o.IndoorHumidity = intToFloat(data.Humidity)
+		o.IndoorCO2 = intToFloat(data.CO2)
+		o.IndoorNoise = intToFloat(data.Noise)
+	}
+	if m := findModuleByType(device, TypeOutdoor); m != nil && m.DashboardData != nil {
+		o.Temperature = m.DashboardData.Temperature
+		o.Humidity = intToFloat(m.DashboardData.Humidity)
+		o.DewPoint = dewPointOf(m.DashboardData.Temperature, m.DashboardData.Humidity)
+		o.observe(m.DashboardData.UTCTime)
+	}
+	if m := findModuleByType(device, TypeWind); m != nil && m.DashboardData != nil {
+		o.WindSpeed = intToFloat(m.DashboardData.WindStrength)
+		o.WindAngle = intToFloat(m.DashboardData.WindAngle)
+		o.GustSpeed = intToFloat(m.DashboardData.GustStrength)
+		o.GustAngle = intToFloat(m.DashboardData.GustAngle)
+		o.observe(m.DashboardData.UTCTime)
+	}
+	if m := findModuleByType(device, TypeRain); m != nil && m.DashboardData != nil {
+		o.RainLastHour = m.DashboardData.RainPerHour
+		o.RainToday = m.DashboardData.RainPerDay
+		o.observe(m.DashboardData.UTCTime)
+	}
+	return o
+}
+
+func (o *Observation) observe(t int64) {
+	if t > o.Time {
+		o.Time = t
+	}
+}
+
+func intToFloat(v *int) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+// Unit conversions used by uploaders.
+
+func celsiusToFahrenheit(v float64) float64 {
+	return v*9/5 + 32
+}
+
+func hPaToInHg(v float64) float64 {
+	return v * 0.0295299830714
+}
+
+func kmhToMph(v float64) float64 {
+	return v / 1.609344
+}
+
+func kmhToMs(v float64) float64 {
+	return v / 3.6
+}
+
+func mmToInch(v float64) float64 {
+	return v / 25.4
+}
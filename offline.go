@@ -0,0 +1,167 @@
+package netatmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// StaleError is returned with cached data by GetStationsData and GetMeasureByNewest when the API call failed and
+// offline fallback is enabled (see WithOfflineFallback). Use errors.As to accept the stale data.
+type StaleError struct {
+	Age time.Duration // Age of the newest sample of the returned data
+	Err error         // Failure of the API call
+}
+
+// Error returns the message with the age.
+func (e *StaleError) Error() string {
+	return fmt.Sprintf("serving data %s old: %v", e.Age.Round(time.Second), e.Err)
+}
+
+// Unwrap returns the failure of the API call.
+func (e *StaleError) Unwrap() error {
+	return e.Err
+}
+
+// WithOfflineFallback makes GetStationsData and GetMeasureByNewest return the most recent successfully fetched data
+// with a *StaleError if the API is unreachable or fails with a 5xx status (see offlineFailure); rejected tokens and
+// requests fail as usual. The store is optional and consulted when nothing was fetched by this client
+// yet (ex. after a restart during an outage), ex. the store filled by Syncer.
+func WithOfflineFallback(store Store) Option {
+	return func(o *clientOptions) {
+		o.offline = &snapshotCache{store: store, newest: make(map[measureKey]Measure)}
+	}
+}
+
+// snapshotCache keeps the last successful responses.
+type snapshotCache struct {
+	store   Store
+	mu      sync.Mutex
+	devices []Device
+	user    *User
+	newest  map[measureKey]Measure // Keyed by device and module, timestamp unused
+}
+
+// putStations keeps copies of the devices and the user, so callers modifying theirs do not change the cache.
+func (s *snapshotCache) putStations(devices []Device, user *User) {
+	devices = copyDevices(devices)
+	if user != nil {
+		u := *user
+		user = &u
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.devices, s.user = devices, user
+}
+
+func (s *snapshotCache) putNewest(deviceID, moduleID string, m *Measure) {
+	if m == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.newest[measureKey{DeviceID: deviceID, ModuleID: moduleID}] = *m
+}
+
+// stations returns copies of cached devices, falling back to the store. User is nil if the devices come from the
+// store.
+func (s *snapshotCache) stations(ctx context.Context) ([]Device, *User, bool) {
+	s.mu.Lock()
+	devices, user := s.devices, s.user
+	s.mu.Unlock()
+	if devices != nil {
+		if user != nil {
+			u := *user
+			user = &u
+		}
+		return copyDevices(devices), user, true
+	}
+	if s.store == nil {
+		return nil, nil, false
+	}
+	devices, err := s.store.Devices(ctx)
+	if err != nil || len(devices) == 0 {
+		return nil, nil, false
+	}
+	return devices, nil, true
+}
+
+// measure returns the cached newest measure, falling back to the newest stored one.
+func (s *snapshotCache) measure(ctx context.Context, deviceID, moduleID string) (*Measure, bool) {
+	s.mu.Lock()
+	m, ok := s.newest[measureKey{DeviceID: deviceID, ModuleID: moduleID}]
+	s.mu.Unlock()
+	if ok {
+		return &m, true
+	}
+	if s.store == nil {
+		return nil, false
+	}
+	newest, err := s.store.NewestMeasure(ctx, MeasureFilter{DeviceID: deviceID, ModuleID: moduleID})
+	if err != nil || newest == nil {
+		return nil, false
+	}
+	return newest, true
+}
+
+// copyDevices returns a copy of the devices with their slices and dashboard data, so the copy can be modified.
+func copyDevices(devices []Device) []Device {
+	if devices == nil {
+		return nil
+	}
+	copied := make([]Device, len(devices))
+	for i, d := range devices {
+		d.DataTypes = append([]string(nil), d.DataTypes...)
+		d.Place.Location = append([]float64(nil), d.Place.Location...)
+		d.DashboardData = copyPtr(d.DashboardData)
+		d.Modules = append([]Module(nil), d.Modules...)
+		for j := range d.Modules {
+			d.Modules[j].DataTypes = append([]string(nil), d.Modules[j].DataTypes...)
+			d.Modules[j].DashboardData = copyPtr(d.Modules[j].DashboardData)
+		}
+		copied[i] = d
+	}
+	return copied
+}
+
+// offlineFailure reports whether the error of an API call is an outage served from the cache: a transport error, an
+// open circuit breaker or a 5xx status of the API or the token endpoint. Errors of the API or the token endpoint
+// rejecting the request or the token (ex. invalid_grant), revoked tokens, closed clients and canceled calls are not.
+func offlineFailure(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		return retrieveErr.Response != nil && retrieveErr.Response.StatusCode >= 500
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+	if errors.Is(err, ErrTokenRevoked) || errors.Is(err, ErrClientClosed) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var requestErr *RequestError
+	return errors.As(err, &requestErr)
+}
+
+// stationsAge returns age of the newest dashboard data of the devices.
+func stationsAge(devices []Device, now time.Time) time.Duration {
+	newest := int64(0)
+	for _, d := range devices {
+		if d.DashboardData != nil && d.DashboardData.UTCTime > newest {
+			newest = d.DashboardData.UTCTime
+		}
+		for _, m := range d.Modules {
+			if m.DashboardData != nil && m.DashboardData.UTCTime > newest {
+				newest = m.DashboardData.UTCTime
+			}
+		}
+	}
+	return now.Sub(time.Unix(newest, 0))
+}
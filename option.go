@@ -0,0 +1,106 @@
+package netatmo
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+)
+
+// Option configures Client.
+type Option func(*clientOptions)
+
+// clientOptions holds settings collected from options.
+type clientOptions struct {
+	tlsConfig *tls.Config
+	proxy     *url.URL
+	proxySet  bool
+	// Request ID header and generator, empty if disabled
+	requestIDHeader string
+	newRequestID    func() string
+	requestTracers  []RequestTracer
+	metricsHooks    []MetricsHook
+	breaker         *circuitBreaker // Nil if disabled
+	rateLimiter     *rateLimiter    // Nil if disabled
+	debug           io.Writer       // Nil if disabled
+	logger          *slog.Logger    // Nil if disabled
+	cache           *responseCache  // Nil if disabled
+	offline         *snapshotCache  // Nil if disabled
+	clock           Clock
+	callInfoHooks   []func(CallInfo)
+	baseTransport   http.RoundTripper // Set by context
+	endpoint        Endpoint
+	// Decoding of responses
+	strictDecoding    bool
+	fieldWarningHooks []func(FieldWarning)
+	tokenStore        TokenStore // Nil if tokens are not persisted
+	tokenHooks        []func(*oauth2.Token)
+}
+
+// WithTLSConfig uses the TLS configuration for API and token requests, ex. to trust a custom root CA of a
+// TLS-intercepting proxy or an internal API mirror, or to present client certificates.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(o *clientOptions) {
+		o.tlsConfig = config
+	}
+}
+
+// WithProxy sends API and token requests through the proxy, or directly if nil. Without this option, proxies are
+// taken from HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func WithProxy(proxy *url.URL) Option {
+	return func(o *clientOptions) {
+		o.proxy, o.proxySet = proxy, true
+	}
+}
+
+func newClientOptions(opts []Option) *clientOptions {
+	o := &clientOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.endpoint = o.endpoint.withDefaults()
+	return o
+}
+
+// transport builds base transport of the options, based on http.DefaultTransport which honors proxy environment
+// variables.
+func (o *clientOptions) transport() http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if o.tlsConfig != nil {
+		transport.TLSClientConfig = o.tlsConfig.Clone()
+	}
+	if o.proxySet {
+		transport.Proxy = nil
+		if o.proxy != nil {
+			transport.Proxy = http.ProxyURL(o.proxy)
+		}
+	}
+	if o.debug != nil {
+		return &debugTransport{base: transport, w: o.debug}
+	}
+	return transport
+}
+
+// oauthConfig returns OAuth2 configuration of the application for the endpoint of the options.
+func (o *clientOptions) oauthConfig(clientID, clientSecret string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       []string{"read_station"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  o.endpoint.AuthURL,
+			TokenURL: o.endpoint.TokenURL,
+		},
+	}
+}
+
+// context returns context carrying the base HTTP client used by oauth2 for token requests and as transport of the
+// authenticated client.
+func (o *clientOptions) context(ctx context.Context) context.Context {
+	o.baseTransport = o.transport()
+	return context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: o.baseTransport})
+}
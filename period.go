@@ -0,0 +1,60 @@
+package netatmo
+
+import (
+	"fmt"
+	"time"
+)
+
+// Period defines a calendar period.
+type Period string
+
+// Supported periods.
+const (
+	PeriodDay   Period = "day"
+	PeriodWeek  Period = "week" // Starting on Monday (ISO 8601)
+	PeriodMonth Period = "month"
+	PeriodYear  Period = "year"
+)
+
+// Start returns the beginning of the period containing the time, in the location of the time.
+func (p Period) Start(t time.Time) (time.Time, error) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch p {
+	case PeriodDay:
+		return day, nil
+	case PeriodWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7), nil
+	case PeriodMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()), nil
+	case PeriodYear:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location()), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown period: %s", p)
+	}
+}
+
+// Add returns the time moved by n periods (ex. PeriodWeek.Add(start, -1) for the previous week).
+func (p Period) Add(t time.Time, n int) time.Time {
+	switch p {
+	case PeriodWeek:
+		return t.AddDate(0, 0, 7*n)
+	case PeriodMonth:
+		return t.AddDate(0, n, 0)
+	case PeriodYear:
+		return t.AddDate(n, 0, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
+
+// stationLocation returns the time zone of the station, falling back to the local time zone.
+func stationLocation(d *Device) *time.Location {
+	if d == nil || d.Place.Timezone == "" {
+		return time.Local
+	}
+	location, err := time.LoadLocation(d.Place.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
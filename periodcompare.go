@@ -0,0 +1,125 @@
+package netatmo
+
+import (
+	"context"
+	"time"
+)
+
+// PeriodComparison defines change of a measurement of a module between a period and the previous one
+// (ex. last 24 hours vs the 24 hours before, this week vs last week).
+type PeriodComparison struct {
+	Account       string   `json:"account,omitempty"`
+	DeviceID      string   `json:"device_id"`
+	ModuleID      string   `json:"module_id"`
+	Metric        string   `json:"metric"`
+	Begin         int64    `json:"begin"` // Inclusive unix time of the current period
+	End           int64    `json:"end"`   // Inclusive unix time of the current period
+	PreviousBegin int64    `json:"previous_begin"`
+	PreviousEnd   int64    `json:"previous_end"`
+	Current       Stats    `json:"current"`
+	Previous      Stats    `json:"previous"`
+	MeanDelta     float64  `json:"mean_delta"`     // Current minus previous mean
+	PercentChange *float64 `json:"percent_change"` // Nullable, change of the mean relative to the previous mean
+	MinShift      float64  `json:"min_shift"`      // Current minus previous minimum
+	MaxShift      float64  `json:"max_shift"`      // Current minus previous maximum
+	SumDelta      float64  `json:"sum_delta"`      // Current minus previous sum, meaningful for sum_rain
+}
+
+// ComparePeriods compares values of the measurement of the measures within the current and the previous windows
+// (inclusive unix times). Measures are expected to be of a single module. The second result is false unless both
+// windows have values.
+func ComparePeriods(measures []Measure, metric string, begin, end, previousBegin, previousEnd int64) (
+	PeriodComparison, bool) {
+	c := PeriodComparison{Metric: metric, Begin: begin, End: end, PreviousBegin: previousBegin,
+		PreviousEnd: previousEnd}
+	for i := range measures {
+		m := &measures[i]
+		v, ok := m.Value(metric)
+		if !ok {
+			continue
+		}
+		switch {
+		case m.Timestamp >= begin && m.Timestamp <= end:
+			c.Current.Add(v)
+		case m.Timestamp >= previousBegin && m.Timestamp <= previousEnd:
+			c.Previous.Add(v)
+		default:
+			continue
+		}
+		c.Account, c.DeviceID, c.ModuleID = m.Account, m.DeviceID, m.ModuleID
+	}
+	if c.Current.Count == 0 || c.Previous.Count == 0 {
+		return c, false
+	}
+	c.MeanDelta = c.Current.Mean() - c.Previous.Mean()
+	if previous := c.Previous.Mean(); previous != 0 {
+		percent := c.MeanDelta / previous * 100
+		c.PercentChange = &percent
+	}
+	c.MinShift, c.MaxShift = c.Current.Min-c.Previous.Min, c.Current.Max-c.Previous.Max
+	c.SumDelta = c.Current.Sum - c.Previous.Sum
+	return c, true
+}
+
+// CompareLast compares stored measures of the last span with the span before (ex. 24 hours) for each module
+// matching the filter and each metric (TargetMeasurements if empty). Time bounds of the filter are ignored, and
+// modules and metrics without values in either span are omitted.
+func CompareLast(ctx context.Context, store Store, filter MeasureFilter, metrics []string, span time.Duration) (
+	[]PeriodComparison, error) {
+	end := ClockFromContext(ctx).Now().Unix()
+	seconds := int64(span / time.Second)
+	return compareStored(ctx, store, filter, metrics, end-seconds+1, end, end-2*seconds+1, end-seconds)
+}
+
+// CompareCalendarPeriods compares the calendar period to date (ex. this week) with the same elapsed time of the
+// previous period (ex. last week up to the same weekday and time) in the location, for each module matching the
+// filter and each metric (TargetMeasurements if empty). Time bounds of the filter are ignored, and modules and
+// metrics without values in either period are omitted.
+func CompareCalendarPeriods(ctx context.Context, store Store, filter MeasureFilter, metrics []string, period Period,
+	location *time.Location) ([]PeriodComparison, error) {
+	now := ClockFromContext(ctx).Now().In(location)
+	start, err := period.Start(now)
+	if err != nil {
+		return nil, err
+	}
+	previousStart := period.Add(start, -1)
+	elapsed := now.Sub(start)
+	previousEnd := previousStart.Add(elapsed)
+	if next := start.Add(-time.Second); previousEnd.After(next) {
+		previousEnd = next // Previous month shorter than the elapsed time of this one
+	}
+	return compareStored(ctx, store, filter, metrics, start.Unix(), now.Unix(), previousStart.Unix(),
+		previousEnd.Unix())
+}
+
+// compareStored compares the windows of each module of stored measures.
+func compareStored(ctx context.Context, store Store, filter MeasureFilter, metrics []string, begin, end,
+	previousBegin, previousEnd int64) ([]PeriodComparison, error) {
+	if len(metrics) == 0 {
+		metrics = TargetMeasurements
+	}
+	filter.Begin, filter.End = previousBegin, end
+	measures, err := store.Measures(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var comparisons []PeriodComparison
+	for start := 0; start < len(measures); {
+		stop := start + 1
+		for stop < len(measures) && sameModule(&measures[start], &measures[stop]) {
+			stop++
+		}
+		for _, metric := range metrics {
+			if c, ok := ComparePeriods(measures[start:stop], metric, begin, end, previousBegin, previousEnd); ok {
+				comparisons = append(comparisons, c)
+			}
+		}
+		start = stop
+	}
+	return comparisons, nil
+}
+
+// sameModule reports whether the measures are of the same account, device and module.
+func sameModule(a, b *Measure) bool {
+	return a.Account == b.Account && a.DeviceID == b.DeviceID && a.ModuleID == b.ModuleID
+}
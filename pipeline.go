@@ -0,0 +1,666 @@
+package netatmo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PipelineConfig defines a collection pipeline: sources polled on a schedule, transforms applied in order and sinks
+// receiving the transformed measures. See LoadPipelineConfig for the YAML form.
+type PipelineConfig struct {
+	Interval   time.Duration       `yaml:"interval"` // Of sources without their own, defaults to 10 minutes
+	Lookback   time.Duration       `yaml:"lookback"` // How far back the first run reaches, defaults to 24 hours
+	Sources    []PipelineSource    `yaml:"sources"`
+	Transforms []PipelineTransform `yaml:"transforms"`
+	Sinks      []PipelineSink      `yaml:"sinks"`
+}
+
+// PipelineSource defines an account to collect measures from. It authenticates with the refresh token, or with the
+// user name and password (no longer granted to new applications); a token saved in the token file is used instead
+// of both, so the token file alone suffices once a token was saved.
+type PipelineSource struct {
+	Account      string        `yaml:"account"` // Namespace stamped on measures, optional for a single account
+	ClientID     string        `yaml:"client_id"`
+	ClientSecret string        `yaml:"client_secret"`
+	RefreshToken string        `yaml:"refresh_token"` // See NewClientWithRefreshToken
+	TokenFile    string        `yaml:"token_file"`    // FileTokenStore keeping the rotated token across restarts
+	Username     string        `yaml:"username"`
+	Password     string        `yaml:"password"`
+	Endpoint     string        `yaml:"endpoint"` // Endpoint profile or base URL, defaults to "default"
+	Devices      []string      `yaml:"devices"`  // Device IDs to collect, all if empty
+	Modules      []string      `yaml:"modules"`  // Module IDs to collect (base stations included), all if empty
+	Interval     time.Duration `yaml:"interval"` // Polling interval of the source, defaults to the config interval
+}
+
+// PipelineTransform defines a step applied to measures before the sinks. Exactly one field is set.
+type PipelineTransform struct {
+	Filter *struct {
+		Action string `yaml:"action"` // "drop" (default) or "flag"
+	} `yaml:"filter"` // FilterSink with default checks
+	Dedup   *struct{} `yaml:"dedup"`   // DedupSink
+	Derived *struct{} `yaml:"derived"` // ApplyDerivedMeasurements, before unit conversion
+	Units   string    `yaml:"units"`   // "imperial" converts to °F, inHg, mph and inches
+}
+
+// PipelineSink defines a destination of measures. Exactly one sink type is set.
+type PipelineSink struct {
+	Interval time.Duration `yaml:"interval"` // Batches measures and writes them every interval, 0 writes as collected
+	Store    *struct {
+		Path string `yaml:"path"`
+	} `yaml:"store"` // FileStore, also receiving devices
+	Exec *struct {
+		Command string   `yaml:"command"`
+		Args    []string `yaml:"args"`
+		Env     []string `yaml:"env"`
+	} `yaml:"exec"`
+	Elasticsearch *struct {
+		URL         string `yaml:"url"`
+		IndexPrefix string `yaml:"index_prefix"`
+		Username    string `yaml:"username"`
+		Password    string `yaml:"password"`
+		APIKey      string `yaml:"api_key"`
+	} `yaml:"elasticsearch"`
+	ClickHouse *struct {
+		URL      string `yaml:"url"`
+		Database string `yaml:"database"`
+		Table    string `yaml:"table"`
+		Username string `yaml:"username"`
+		Password string `yaml:"password"`
+	} `yaml:"clickhouse"`
+	QuestDB *struct {
+		URL      string `yaml:"url"`
+		Token    string `yaml:"token"`
+		Username string `yaml:"username"`
+		Password string `yaml:"password"`
+	} `yaml:"questdb"`
+	Redis *struct {
+		Addr      string        `yaml:"addr"`
+		Username  string        `yaml:"username"`
+		Password  string        `yaml:"password"`
+		DB        int           `yaml:"db"`
+		Retention time.Duration `yaml:"retention"`
+	} `yaml:"redis"`
+	InfluxDB *struct {
+		URL    string `yaml:"url"`
+		Org    string `yaml:"org"`
+		Bucket string `yaml:"bucket"`
+		Token  string `yaml:"token"`
+	} `yaml:"influxdb"`
+	Prometheus *struct {
+		Listen string        `yaml:"listen"`
+		Path   string        `yaml:"path"`
+		MaxAge time.Duration `yaml:"max_age"`
+	} `yaml:"prometheus"`
+	MQTT *struct {
+		URL      string `yaml:"url"`
+		Topic    string `yaml:"topic"`
+		ClientID string `yaml:"client_id"`
+		Username string `yaml:"username"`
+		Password string `yaml:"password"`
+		QoS      byte   `yaml:"qos"`
+		Retain   bool   `yaml:"retain"`
+	} `yaml:"mqtt"`
+}
+
+// LoadPipelineConfig reads the YAML pipeline configuration. ${VAR} references in values are replaced with
+// environment variables, so secrets can stay out of the file; other $ characters and keys are kept as is. Example:
+//
+//	interval: 10m
+//	sources:
+//	  - account: home
+//	    client_id: ${NETATMO_CLIENT_ID}
+//	    client_secret: ${NETATMO_CLIENT_SECRET}
+//	    refresh_token: ${NETATMO_REFRESH_TOKEN}
+//	    token_file: home-token.json
+//	  - account: office
+//	    interval: 1h
+//	    ...
+//	transforms:
+//	  - derived: {}
+//	  - filter: {action: flag}
+//	  - dedup: {}
+//	sinks:
+//	  - store: {path: history.ndjson.gz}
+//	  - questdb: {url: "http://localhost:9000", interval: 1h}
+func LoadPipelineConfig(path string) (*PipelineConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var config PipelineConfig
+	if doc.Kind == 0 {
+		return &config, nil // Empty file
+	}
+	expandEnvValues(&doc)
+	// Decoded again from the expanded document, as decoding a node does not reject unknown fields
+	expanded, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(expanded))
+	dec.KnownFields(true)
+	if err := dec.Decode(&config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// envReference matches ${VAR} references of pipeline configurations.
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnvValues replaces ${VAR} references in scalar values (not mapping keys) of the node with environment
+// variables. Expanded values are typed again, so references also work for numbers and durations, quoted or not
+// (quote them inside flow collections, ex. {db: "${REDIS_DB}"}).
+func expandEnvValues(n *yaml.Node) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			expandEnvValues(n.Content[i])
+		}
+	case yaml.ScalarNode:
+		if envReference.MatchString(n.Value) {
+			n.Value = envReference.ReplaceAllStringFunc(n.Value, func(ref string) string {
+				return os.Getenv(ref[2 : len(ref)-1])
+			})
+			n.Tag, n.Style = "", 0
+		}
+	default:
+		for _, c := range n.Content {
+			expandEnvValues(c)
+		}
+	}
+}
+
+// pipelineSource defines an authenticated source of a running pipeline.
+type pipelineSource struct {
+	PipelineSource
+	client *Client
+	every  int // Runs every this many ticks
+}
+
+// maxPipelinePending defines how many measures a failing sink keeps for retries, about 2 weeks of 50 modules.
+const maxPipelinePending = 100000
+
+// batchSink keeps measures of a sink until flushed, so a failed sink retries its own measures without writing them
+// again to the other sinks.
+type batchSink struct {
+	name    string // For errors (ex. sink 2)
+	sink    Sink
+	every   int  // Flushes every this many ticks
+	direct  bool // Flushes on each write, for sinks without an interval
+	mu      sync.Mutex
+	pending []Measure
+}
+
+// Write keeps the measures for the next flush, dropping the oldest ones beyond maxPipelinePending.
+func (s *batchSink) Write(ctx context.Context, measures []Measure) error {
+	s.mu.Lock()
+	s.pending = append(s.pending, measures...)
+	dropped := max(0, len(s.pending)-maxPipelinePending)
+	if dropped > 0 {
+		s.pending = append([]Measure{}, s.pending[dropped:]...)
+	}
+	s.mu.Unlock()
+	var err error
+	if s.direct {
+		err = s.flush(ctx)
+	}
+	if dropped > 0 {
+		err = errors.Join(fmt.Errorf("%s: dropped %d oldest pending measures", s.name, dropped), err)
+	}
+	return err
+}
+
+// flush writes the kept measures, keeping them for the next flush if the sink fails.
+func (s *batchSink) flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) == 0 {
+		return nil
+	}
+	if err := s.sink.Write(ctx, s.pending); err != nil {
+		return fmt.Errorf("%s: %w (%d measures kept for retry)", s.name, err, len(s.pending))
+	}
+	s.pending = nil
+	return nil
+}
+
+// pipelineTick returns the greatest common divisor of the intervals, so every source and sink is due on a tick.
+func pipelineTick(config PipelineConfig) time.Duration {
+	tick := config.Interval
+	for _, d := range config.Sources {
+		tick = gcdDuration(tick, d.Interval)
+	}
+	for _, d := range config.Sinks {
+		tick = gcdDuration(tick, d.Interval)
+	}
+	return max(tick, time.Second)
+}
+
+func gcdDuration(a, b time.Duration) time.Duration {
+	for b > 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// pipelineKey identifies a module of an account.
+type pipelineKey struct {
+	Account  string
+	DeviceID string
+	ModuleID string
+}
+
+// Pipeline runs a PipelineConfig.
+type Pipeline struct {
+	config  PipelineConfig
+	sources []pipelineSource
+	sink    Sink          // Head of the transform chain
+	sinks   []Sink        // Destinations, receiving devices if they are stores
+	batches []*batchSink  // Of each destination
+	tick    time.Duration // Greatest common divisor of the source and sink intervals
+	clock   Clock
+	last    map[pipelineKey]int64 // Latest collected timestamp of each module
+	closers []io.Closer
+}
+
+// NewPipeline authenticates the sources and creates the transforms and sinks of the configuration.
+func NewPipeline(ctx context.Context, config PipelineConfig, opts ...Option) (*Pipeline, error) {
+	if len(config.Sources) == 0 {
+		return nil, errors.New("pipeline: no source")
+	}
+	if len(config.Sinks) == 0 {
+		return nil, errors.New("pipeline: no sink")
+	}
+	if config.Interval <= 0 {
+		config.Interval = 10 * time.Minute
+	}
+	if config.Lookback <= 0 {
+		config.Lookback = defaultSyncLookback
+	}
+	p := &Pipeline{config: config, tick: pipelineTick(config), clock: newClientOptions(opts).clock,
+		last: make(map[pipelineKey]int64)}
+	destinations := make(MultiSink, len(config.Sinks))
+	for i, sc := range config.Sinks {
+		sink, err := newPipelineSink(ctx, sc)
+		if err != nil {
+			_ = p.Close()
+			return nil, fmt.Errorf("pipeline: sink %d: %w", i+1, err)
+		}
+		p.sinks = append(p.sinks, sink)
+		if c, ok := sink.(io.Closer); ok {
+			p.closers = append(p.closers, c)
+		}
+		batch := &batchSink{name: fmt.Sprintf("sink %d", i+1), sink: sink, every: 1, direct: sc.Interval <= 0}
+		if sc.Interval > 0 {
+			batch.every = p.ticks(sc.Interval)
+		}
+		p.batches = append(p.batches, batch)
+		destinations[i] = batch
+	}
+	p.sink = destinations
+	for i := len(config.Transforms) - 1; i >= 0; i-- {
+		next, err := wrapPipelineTransform(config.Transforms[i], p.sink)
+		if err != nil {
+			_ = p.Close()
+			return nil, fmt.Errorf("pipeline: transform %d: %w", i+1, err)
+		}
+		p.sink = next
+	}
+	for _, sc := range config.Sources {
+		client, err := newPipelineClient(ctx, sc, opts)
+		if err != nil {
+			_ = p.Close()
+			return nil, fmt.Errorf("pipeline: account %s: %w", sc.Account, err)
+		}
+		interval := sc.Interval
+		if interval <= 0 {
+			interval = config.Interval
+		}
+		p.sources = append(p.sources, pipelineSource{PipelineSource: sc, client: client, every: p.ticks(interval)})
+		p.closers = append(p.closers, client)
+	}
+	return p, nil
+}
+
+// newPipelineClient authenticates the source.
+func newPipelineClient(ctx context.Context, sc PipelineSource, opts []Option) (*Client, error) {
+	profile := sc.Endpoint
+	if profile == "" {
+		profile = "default"
+	}
+	endpoint, err := LookupEndpoint(profile)
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]Option{WithEndpoint(endpoint)}, opts...)
+	var store *FileTokenStore
+	if sc.TokenFile != "" {
+		store = &FileTokenStore{Path: sc.TokenFile}
+		opts = append(opts, WithTokenStore(store))
+	}
+	switch {
+	case sc.RefreshToken != "":
+		return NewClientWithRefreshToken(ctx, sc.ClientID, sc.ClientSecret, sc.RefreshToken, opts...)
+	case sc.Username != "" || sc.Password != "":
+		return NewClient(ctx, sc.ClientID, sc.ClientSecret, sc.Username, sc.Password, opts...)
+	case store != nil:
+		token, err := store.Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if token == nil {
+			return nil, fmt.Errorf("no token saved in %s", sc.TokenFile)
+		}
+		return NewClientWithToken(ctx, sc.ClientID, sc.ClientSecret, token, opts...)
+	}
+	return nil, errors.New("no refresh_token, token_file or username and password")
+}
+
+// Run collects every source immediately and then every interval of the source until the context is done. Sinks
+// with an interval receive the measures collected meanwhile every interval. Failed runs are passed to onError (if
+// not nil) and retried when the source or sink is due next; a failed sink keeps its measures (at most
+// maxPipelinePending, dropping the oldest) and retries them alone, so the other sinks get no duplicates.
+func (p *Pipeline) Run(ctx context.Context, onError func(err error)) error {
+	ticker := orSystemClock(p.clock).NewTicker(p.tick)
+	defer ticker.Stop()
+	for tick := 0; ; tick++ {
+		if _, err := p.run(ctx, func(every int) bool { return tick%every == 0 }); err != nil && onError != nil &&
+			ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
+
+// RunOnce collects measures of every source newer than the previous run (or the lookback on the first run) and
+// writes them through the transforms to the sinks, flushing the batches of sinks with an interval. It returns the
+// number of collected measures.
+func (p *Pipeline) RunOnce(ctx context.Context) (int, error) {
+	return p.run(ctx, func(int) bool { return true })
+}
+
+// run collects the due sources and flushes the due batches.
+func (p *Pipeline) run(ctx context.Context, due func(every int) bool) (int, error) {
+	now := orSystemClock(p.clock).Now().Unix()
+	total := 0
+	var errs []error
+	for _, src := range p.sources {
+		if !due(src.every) {
+			continue
+		}
+		devices, _, err := src.client.GetStationsDataContext(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", src.Account, err))
+			continue
+		}
+		devices = src.selectDevices(devices)
+		for i := range devices {
+			devices[i].Account = src.Account
+		}
+		for _, sink := range p.sinks {
+			if store, ok := sink.(Store); ok {
+				if err := store.PutDevices(ctx, devices); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+		for i := range devices {
+			d := &devices[i]
+			for _, moduleID := range src.selectModules(d) {
+				n, err := p.collect(ctx, src, d, moduleID, now)
+				total += n
+				if err != nil {
+					errs = append(errs, fmt.Errorf("account %s: module %s: %w", src.Account, moduleID, err))
+				}
+			}
+		}
+	}
+	for _, batch := range p.batches {
+		if due(batch.every) {
+			if err := batch.flush(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+// ticks returns the interval in ticks of the pipeline.
+func (p *Pipeline) ticks(d time.Duration) int {
+	return max(1, int(d/p.tick))
+}
+
+func (p *Pipeline) collect(ctx context.Context, src pipelineSource, d *Device, moduleID string, now int64) (int, error) {
+	key := pipelineKey{Account: src.Account, DeviceID: d.ID, ModuleID: moduleID}
+	begin, ok := p.last[key]
+	if ok {
+		begin++
+	} else {
+		var err error
+		if begin, err = p.resume(ctx, key, now-int64(p.config.Lookback/time.Second)); err != nil {
+			return 0, err
+		}
+	}
+	measures, err := src.client.GetMeasureSinceContext(ctx, d.ID, moduleID, begin)
+	if err != nil || len(measures) == 0 {
+		return 0, err
+	}
+	for i := range measures {
+		measures[i].Account = src.Account
+	}
+	// Failed sinks keep the measures for retries, so they are not collected again
+	p.last[key] = measures[len(measures)-1].Timestamp
+	return len(measures), p.sink.Write(ctx, measures)
+}
+
+// resume returns the beginning of the first collection of the module: after the newest measure kept by every store
+// sink, so a restart does not write the lookback again, but not before the lookback.
+func (p *Pipeline) resume(ctx context.Context, key pipelineKey, lookback int64) (int64, error) {
+	begin := int64(-1)
+	for _, sink := range p.sinks {
+		store, ok := sink.(Store)
+		if !ok {
+			continue
+		}
+		newest, err := store.NewestMeasure(ctx, MeasureFilter{Account: key.Account, DeviceID: key.DeviceID,
+			ModuleID: key.ModuleID})
+		if err != nil {
+			return 0, err
+		}
+		if newest == nil {
+			return lookback, nil
+		}
+		if begin < 0 || newest.Timestamp+1 < begin {
+			begin = newest.Timestamp + 1
+		}
+	}
+	return max(begin, lookback), nil
+}
+
+// Close writes the pending batches and closes the clients and the sinks holding connections or processes.
+func (p *Pipeline) Close() error {
+	var errs []error
+	for _, batch := range p.batches {
+		if err := batch.flush(context.Background()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, c := range p.closers {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *pipelineSource) selectDevices(devices []Device) []Device {
+	if len(s.Devices) == 0 {
+		return devices
+	}
+	var selected []Device
+	for _, d := range devices {
+		if sliceContains(s.Devices, d.ID) {
+			selected = append(selected, d)
+		}
+	}
+	return selected
+}
+
+func (s *pipelineSource) selectModules(d *Device) []string {
+	var ids []string
+	for _, id := range append([]string{d.ID}, moduleIDs(d)...) {
+		if len(s.Modules) == 0 || sliceContains(s.Modules, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func moduleIDs(d *Device) []string {
+	ids := make([]string, 0, len(d.Modules))
+	for _, m := range d.Modules {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func newPipelineSink(ctx context.Context, c PipelineSink) (Sink, error) {
+	switch {
+	case c.Store != nil:
+		return OpenFileStore(ctx, c.Store.Path)
+	case c.Exec != nil:
+		return &ExecSink{Command: c.Exec.Command, Args: c.Exec.Args, Env: c.Exec.Env}, nil
+	case c.Elasticsearch != nil:
+		e := c.Elasticsearch
+		return &ElasticsearchSink{URL: e.URL, IndexPrefix: e.IndexPrefix, Username: e.Username, Password: e.Password,
+			APIKey: e.APIKey}, nil
+	case c.ClickHouse != nil:
+		e := c.ClickHouse
+		return &ClickHouseSink{URL: e.URL, Database: e.Database, Table: e.Table, Username: e.Username,
+			Password: e.Password}, nil
+	case c.QuestDB != nil:
+		e := c.QuestDB
+		return &QuestDBSink{URL: e.URL, Token: e.Token, Username: e.Username, Password: e.Password}, nil
+	case c.Redis != nil:
+		e := c.Redis
+		return &RedisTimeSeriesSink{Addr: e.Addr, Username: e.Username, Password: e.Password, DB: e.DB,
+			Retention: e.Retention}, nil
+	case c.InfluxDB != nil:
+		e := c.InfluxDB
+		return &InfluxDBSink{URL: e.URL, Org: e.Org, Bucket: e.Bucket, Token: e.Token}, nil
+	case c.Prometheus != nil:
+		return newPrometheusServer(c.Prometheus.Listen, c.Prometheus.Path, &PrometheusSink{MaxAge: c.Prometheus.MaxAge})
+	case c.MQTT != nil:
+		e := c.MQTT
+		return &MQTTSink{URL: e.URL, Topic: e.Topic, ClientID: e.ClientID, Username: e.Username, Password: e.Password,
+			QoS: e.QoS, Retain: e.Retain}, nil
+	}
+	return nil, errors.New("no sink type")
+}
+
+// prometheusServer serves a PrometheusSink of a pipeline until closed.
+type prometheusServer struct {
+	*PrometheusSink
+	server *http.Server
+}
+
+// newPrometheusServer listens on the address, so a busy port fails the pipeline creation, and serves the sink on
+// the path.
+func newPrometheusServer(addr, path string, sink *PrometheusSink) (*prometheusServer, error) {
+	if path == "" {
+		path = "/metrics"
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, sink)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	s := &prometheusServer{PrometheusSink: sink, server: server}
+	go func() { _ = s.server.Serve(l) }()
+	return s, nil
+}
+
+// Close stops serving.
+func (s *prometheusServer) Close() error {
+	return s.server.Close()
+}
+
+func wrapPipelineTransform(t PipelineTransform, next Sink) (Sink, error) {
+	switch {
+	case t.Filter != nil:
+		config := FilterConfig{}
+		switch t.Filter.Action {
+		case "", "drop":
+		case "flag":
+			config.Action = FilterFlag
+		default:
+			return nil, fmt.Errorf("unknown filter action: %s", t.Filter.Action)
+		}
+		return NewFilterSink(next, config), nil
+	case t.Dedup != nil:
+		return NewDedupSink(next), nil
+	case t.Derived != nil:
+		return SinkFunc(func(ctx context.Context, measures []Measure) error {
+			derived := append([]Measure{}, measures...)
+			ApplyDerivedMeasurements(derived)
+			return next.Write(ctx, derived)
+		}), nil
+	case t.Units == "imperial":
+		return SinkFunc(func(ctx context.Context, measures []Measure) error {
+			converted := append([]Measure{}, measures...)
+			for i := range converted {
+				toImperial(&converted[i])
+			}
+			return next.Write(ctx, converted)
+		}), nil
+	case t.Units != "":
+		return nil, fmt.Errorf("unknown units: %s", t.Units)
+	}
+	return nil, errors.New("no transform type")
+}
+
+// toImperial converts values of the measure to °F, inHg, mph and inches.
+func toImperial(m *Measure) {
+	for _, v := range []**float64{&m.Temperature, &m.MinTemperature, &m.MaxTemperature, &m.DewPoint, &m.Humidex} {
+		*v = convertFloat(*v, celsiusToFahrenheit)
+	}
+	for _, v := range []**float64{&m.Pressure, &m.MinPressure, &m.MaxPressure} {
+		*v = convertFloat(*v, hPaToInHg)
+	}
+	for _, v := range []**int{&m.WindStrength, &m.GustStrength} {
+		if *v != nil {
+			*v = roundInt(kmhToMph(float64(**v)))
+		}
+	}
+	m.Rain, m.SumRain = convertFloat(m.Rain, mmToInch), convertFloat(m.SumRain, mmToInch)
+}
+
+func convertFloat(v *float64, convert func(float64) float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	c := convert(*v)
+	return &c
+}
@@ -0,0 +1,162 @@
+package netatmo
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// EventType defines kind of change detected by Poller.
+type EventType string
+
+// Supported event types.
+const (
+	EventCO2CalibrationStarted EventType = "co2_calibration_started"
+	EventCO2CalibrationEnded   EventType = "co2_calibration_ended"
+	EventFirmwareChanged       EventType = "firmware_changed"
+)
+
+// Event defines a change detected between two station data fetches.
+type Event struct {
+	Type     EventType
+	Time     int64 // Unix time of the fetch detecting the change
+	DeviceID string
+	ModuleID string // Equals DeviceID for changes of the base station
+	Old      string // Previous value for changes of a value (ex. firmware version)
+	New      string // Current value for changes of a value
+}
+
+// Detector finds changes between previous and current station data. Previous is nil on the first fetch.
+type Detector func(previous, current []Device, now int64) []Event
+
+// DefaultDetectors defines detectors used by Poller when no detector is specified.
+var DefaultDetectors = []Detector{DetectCO2Calibration, DetectFirmwareChange}
+
+// Poller periodically fetches station data, passes it to OnData and reports detected changes to OnEvent.
+type Poller struct {
+	Client    *Client
+	Interval  time.Duration                      // Defaults to 10 minutes, the update interval of Netatmo stations
+	Detectors []Detector                         // Defaults to DefaultDetectors
+	OnData    func(devices []Device, user *User) // Optional
+	OnEvent   func(event Event)                  // Optional
+	OnError   func(err error)                    // Optional, failed fetches are retried on the next tick
+	Warner    *WeatherWarner                     // Optional, checks outdoor modules for frost and heat
+	Notifier  Notifier                           // Optional, receives alerts of Warner
+	Clock     Clock                              // Defaults to SystemClock
+	previous  []Device
+}
+
+// Run polls immediately and then every interval until the context is done. It blocks, so start it with a go
+// statement.
+func (p *Poller) Run(ctx context.Context) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	ticker := orSystemClock(p.Clock).NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := p.PollContext(ctx); err != nil && p.OnError != nil {
+			p.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
+
+// Poll fetches station data once, calls OnData and reports changes since the previous fetch.
+func (p *Poller) Poll() error {
+	return p.PollContext(context.Background())
+}
+
+// PollContext is Poll with a context canceling the request.
+func (p *Poller) PollContext(ctx context.Context) error {
+	devices, user, err := p.Client.GetStationsDataContext(ctx)
+	if err != nil {
+		return err
+	}
+	if p.OnData != nil {
+		p.OnData(devices, user)
+	}
+	detectors := p.Detectors
+	if detectors == nil {
+		detectors = DefaultDetectors
+	}
+	now := orSystemClock(p.Clock).Now().Unix()
+	for _, detect := range detectors {
+		for _, event := range detect(p.previous, devices, now) {
+			if p.OnEvent != nil {
+				p.OnEvent(event)
+			}
+		}
+	}
+	p.previous = devices
+	if p.Warner != nil {
+		return notifyAll(ctx, p.Notifier, p.Warner.Observe(devices, time.Unix(now, 0)))
+	}
+	return nil
+}
+
+// DetectCO2Calibration reports start and end of CO2 sensor calibration of devices.
+func DetectCO2Calibration(previous, current []Device, now int64) []Event {
+	var events []Event
+	for _, d := range current {
+		old := findDevice(previous, d.ID)
+		if old == nil || old.CO2Calibrating == d.CO2Calibrating {
+			continue
+		}
+		event := Event{Type: EventCO2CalibrationEnded, Time: now, DeviceID: d.ID, ModuleID: d.ID}
+		if d.CO2Calibrating {
+			event.Type = EventCO2CalibrationStarted
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// DetectFirmwareChange reports firmware version changes of devices and modules.
+func DetectFirmwareChange(previous, current []Device, now int64) []Event {
+	var events []Event
+	for _, d := range current {
+		old := findDevice(previous, d.ID)
+		if old == nil {
+			continue
+		}
+		if old.Firmware != d.Firmware {
+			events = append(events, firmwareEvent(now, d.ID, d.ID, old.Firmware, d.Firmware))
+		}
+		for _, m := range d.Modules {
+			oldModule := findModule(old.Modules, m.ID)
+			if oldModule != nil && oldModule.Firmware != m.Firmware {
+				events = append(events, firmwareEvent(now, d.ID, m.ID, oldModule.Firmware, m.Firmware))
+			}
+		}
+	}
+	return events
+}
+
+func firmwareEvent(now int64, deviceID, moduleID string, old, new int) Event {
+	return Event{Type: EventFirmwareChanged, Time: now, DeviceID: deviceID, ModuleID: moduleID,
+		Old: strconv.Itoa(old), New: strconv.Itoa(new)}
+}
+
+func findModule(modules []Module, id string) *Module {
+	for i := range modules {
+		if modules[i].ID == id {
+			return &modules[i]
+		}
+	}
+	return nil
+}
+
+func findDevice(devices []Device, id string) *Device {
+	for i := range devices {
+		if devices[i].ID == id {
+			return &devices[i]
+		}
+	}
+	return nil
+}
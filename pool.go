@@ -0,0 +1,119 @@
+package netatmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ClientPool holds authenticated clients of several accounts, keyed by account (ex. "home", "office"). The zero value
+// is an empty pool ready to use.
+type ClientPool struct {
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+// Add adds the client of the account, replacing the previous one.
+func (p *ClientPool) Add(account string, client *Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.clients == nil {
+		p.clients = make(map[string]*Client)
+	}
+	p.clients[account] = client
+}
+
+// Remove removes the client of the account and returns it, nil if none. The client is not closed.
+func (p *ClientPool) Remove(account string) *Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	client := p.clients[account]
+	delete(p.clients, account)
+	return client
+}
+
+// Client returns the client of the account.
+func (p *ClientPool) Client(account string) (*Client, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	client, ok := p.clients[account]
+	return client, ok
+}
+
+// Accounts returns the accounts of the pool in ascending order.
+func (p *ClientPool) Accounts() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	accounts := make([]string, 0, len(p.clients))
+	for account := range p.clients {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+	return accounts
+}
+
+// Sources returns the clients of the pool as sources of a Syncer, in account order.
+func (p *ClientPool) Sources() []Source {
+	var sources []Source
+	for _, account := range p.Accounts() {
+		if client, ok := p.Client(account); ok {
+			sources = append(sources, Source{Account: account, Client: client})
+		}
+	}
+	return sources
+}
+
+// GetStationsData gathers station data of all accounts concurrently. Devices are stamped with their account and
+// merged in account order; users are keyed by account. Failures of some accounts are joined into the error, wrapping
+// the error of each account, along with the data of the other accounts.
+func (p *ClientPool) GetStationsData() ([]Device, map[string]*User, error) {
+	return p.GetStationsDataContext(context.Background())
+}
+
+// GetStationsDataContext is GetStationsData with a context canceling the requests.
+func (p *ClientPool) GetStationsDataContext(ctx context.Context) ([]Device, map[string]*User, error) {
+	sources := p.Sources()
+	type result struct {
+		devices []Device
+		user    *User
+		err     error
+	}
+	results := make([]result, len(sources))
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Add(1)
+		go func(i int, src Source) {
+			defer wg.Done()
+			devices, user, err := src.Client.GetStationsDataContext(ctx)
+			for j := range devices {
+				devices[j].Account = src.Account
+			}
+			results[i] = result{devices: devices, user: user, err: err}
+		}(i, src)
+	}
+	wg.Wait()
+	var devices []Device
+	users := make(map[string]*User)
+	var errs []error
+	for i, r := range results {
+		if r.err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", sources[i].Account, r.err))
+			continue
+		}
+		devices = append(devices, r.devices...)
+		users[sources[i].Account] = r.user
+	}
+	return devices, users, errors.Join(errs...)
+}
+
+// Close closes the clients of all accounts.
+func (p *ClientPool) Close() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, client := range p.clients {
+		client.Close()
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package netatmo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPrometheusNaming defines metric naming of PrometheusSink without explicit naming
+// (ex. netatmo_temperature_celsius with account, device and module labels).
+var DefaultPrometheusNaming = MetricNaming{Name: "netatmo_{{snake .Metric}}{{if .Unit}}_{{.Unit}}{{end}}"}
+
+// PrometheusSink keeps the newest value of each measurement of each module and serves them as gauges in the
+// Prometheus text format, so a pipeline can be scraped like an exporter. Samples carry no timestamps: a scrape
+// returns the newest values, which Netatmo updates every 10 minutes.
+type PrometheusSink struct {
+	Namer  *MetricNamer  // Metric names and labels, defaults to DefaultPrometheusNaming
+	MaxAge time.Duration // Values not updated for longer (ex. removed modules) are dropped, 0 keeps them
+	mu     sync.Mutex
+	series map[string]*prometheusSample // Keyed by name and labels
+}
+
+// prometheusSample defines the newest value of a series.
+type prometheusSample struct {
+	name      string
+	labels    string // Formatted label set, empty or starting with {
+	value     float64
+	timestamp int64 // Unix time of the measure
+	updated   time.Time
+}
+
+// Write keeps the values of the measures newer than the kept ones.
+func (s *PrometheusSink) Write(ctx context.Context, measures []Measure) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Namer == nil {
+		namer, err := NewMetricNamer(DefaultPrometheusNaming)
+		if err != nil {
+			return fmt.Errorf("prometheus: %w", err)
+		}
+		s.Namer = namer
+	}
+	if s.series == nil {
+		s.series = make(map[string]*prometheusSample)
+	}
+	now := time.Now()
+	for i := range measures {
+		m := &measures[i]
+		for _, v := range sinkValues(m) {
+			name, labels, err := s.Namer.Name(m, v.Name)
+			if err != nil {
+				return fmt.Errorf("prometheus: %w", err)
+			}
+			sample := &prometheusSample{name: name, labels: prometheusLabels(labels), value: v.Value,
+				timestamp: m.Timestamp, updated: now}
+			key := sample.name + sample.labels
+			if kept, ok := s.series[key]; ok && kept.timestamp > sample.timestamp {
+				continue
+			}
+			s.series[key] = sample
+		}
+	}
+	return nil
+}
+
+// ServeHTTP writes the kept values in the Prometheus text exposition format.
+func (s *PrometheusSink) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var b bytes.Buffer
+	s.writeText(&b, time.Now())
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(b.Bytes())
+}
+
+// writeText writes the kept values grouped by metric name, dropping values older than MaxAge.
+func (s *PrometheusSink) writeText(b *bytes.Buffer, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	samples := make([]*prometheusSample, 0, len(s.series))
+	for key, sample := range s.series {
+		if s.MaxAge > 0 && now.Sub(sample.updated) > s.MaxAge {
+			delete(s.series, key)
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].name != samples[j].name {
+			return samples[i].name < samples[j].name
+		}
+		return samples[i].labels < samples[j].labels
+	})
+	for i, sample := range samples {
+		if i == 0 || samples[i-1].name != sample.name {
+			fmt.Fprintf(b, "# TYPE %s gauge\n", sample.name)
+		}
+		fmt.Fprintf(b, "%s%s %s\n", sample.name, sample.labels, strconv.FormatFloat(sample.value, 'g', -1, 64))
+	}
+}
+
+// prometheusLabels formats the label set sorted by name, empty if there are no labels.
+func prometheusLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + prometheusLabelEscaper.Replace(labels[name]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// prometheusLabelEscaper escapes label values of the text format.
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,346 @@
+package netatmo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/mikan/netatmo-weather-go/netatmopb"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+//go:generate protoc --go_out=. --go_opt=module=github.com/mikan/netatmo-weather-go proto/netatmo.proto
+
+// ProtoContentType defines the content type of protocol buffers payloads.
+const ProtoContentType = "application/x-protobuf"
+
+// MaxProtoMessageSize defines the largest length-delimited message accepted by ReadMeasuresProto.
+const MaxProtoMessageSize = 4 << 20
+
+// protoMarshal sorts map entries, so equal measures encode to equal bytes.
+var protoMarshal = proto.MarshalOptions{Deterministic: true}
+
+// MarshalMeasureProto encodes the measure as the Measure message of proto/netatmo.proto.
+func MarshalMeasureProto(m *Measure) ([]byte, error) {
+	b, err := protoMarshal.Marshal(MeasureToProto(m))
+	if err != nil {
+		return nil, fmt.Errorf("protobuf: %w", err)
+	}
+	return b, nil
+}
+
+// UnmarshalMeasureProto decodes the Measure message of proto/netatmo.proto. Unknown fields are skipped, so newer
+// schema versions remain readable.
+func UnmarshalMeasureProto(b []byte) (Measure, error) {
+	var p netatmopb.Measure
+	if err := proto.Unmarshal(b, &p); err != nil {
+		return Measure{}, fmt.Errorf("protobuf: %w", err)
+	}
+	return MeasureFromProto(&p), nil
+}
+
+// MarshalMeasuresProto encodes the measures as the MeasureBatch message of proto/netatmo.proto.
+func MarshalMeasuresProto(measures []Measure) ([]byte, error) {
+	batch := &netatmopb.MeasureBatch{Measures: make([]*netatmopb.Measure, len(measures))}
+	for i := range measures {
+		batch.Measures[i] = MeasureToProto(&measures[i])
+	}
+	b, err := protoMarshal.Marshal(batch)
+	if err != nil {
+		return nil, fmt.Errorf("protobuf: %w", err)
+	}
+	return b, nil
+}
+
+// UnmarshalMeasuresProto decodes the MeasureBatch message of proto/netatmo.proto.
+func UnmarshalMeasuresProto(b []byte) ([]Measure, error) {
+	var batch netatmopb.MeasureBatch
+	if err := proto.Unmarshal(b, &batch); err != nil {
+		return nil, fmt.Errorf("protobuf: %w", err)
+	}
+	var measures []Measure
+	for _, p := range batch.Measures {
+		measures = append(measures, MeasureFromProto(p))
+	}
+	return measures, nil
+}
+
+// WriteMeasuresProto writes the measures as varint length-delimited Measure messages, the framing of Java
+// writeDelimitedTo and of most archive formats, so that streams can be appended and read incrementally.
+func WriteMeasuresProto(w io.Writer, measures []Measure) error {
+	var b []byte
+	for i := range measures {
+		message, err := MarshalMeasureProto(&measures[i])
+		if err != nil {
+			return err
+		}
+		b = protowire.AppendBytes(b[:0], message)
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadMeasuresProto reads varint length-delimited Measure messages written by WriteMeasuresProto until EOF.
+func ReadMeasuresProto(r io.Reader) ([]Measure, error) {
+	br := bufio.NewReader(r)
+	var measures []Measure
+	for {
+		size, err := readUvarint(br)
+		if err == io.EOF {
+			return measures, nil
+		}
+		if err != nil {
+			return measures, err
+		}
+		if size > MaxProtoMessageSize {
+			return measures, fmt.Errorf("protobuf: message of %d bytes exceeds %d", size, MaxProtoMessageSize)
+		}
+		b := make([]byte, size)
+		if _, err := io.ReadFull(br, b); err != nil {
+			return measures, fmt.Errorf("protobuf: %w", err)
+		}
+		m, err := UnmarshalMeasureProto(b)
+		if err != nil {
+			return measures, err
+		}
+		measures = append(measures, m)
+	}
+}
+
+// MarshalDeviceProto encodes the device and its modules as the Device message of proto/netatmo.proto. Dashboard
+// data are not encoded; they travel as measures.
+func MarshalDeviceProto(d *Device) ([]byte, error) {
+	b, err := protoMarshal.Marshal(DeviceToProto(d))
+	if err != nil {
+		return nil, fmt.Errorf("protobuf: %w", err)
+	}
+	return b, nil
+}
+
+// UnmarshalDeviceProto decodes the Device message of proto/netatmo.proto.
+func UnmarshalDeviceProto(b []byte) (Device, error) {
+	var p netatmopb.Device
+	if err := proto.Unmarshal(b, &p); err != nil {
+		return Device{}, fmt.Errorf("protobuf: %w", err)
+	}
+	return DeviceFromProto(&p), nil
+}
+
+// MeasureToProto converts the measure to the generated Measure message, ex. for gRPC services.
+func MeasureToProto(m *Measure) *netatmopb.Measure {
+	p := &netatmopb.Measure{
+		Account:        m.Account,
+		DeviceId:       m.DeviceID,
+		ModuleId:       m.ModuleID,
+		Timestamp:      m.Timestamp,
+		Temperature:    copyPtr(m.Temperature),
+		Co2:            toInt32(m.CO2),
+		Humidity:       toInt32(m.Humidity),
+		Pressure:       copyPtr(m.Pressure),
+		Noise:          toInt32(m.Noise),
+		WindStrength:   toInt32(m.WindStrength),
+		WindAngle:      toInt32(m.WindAngle),
+		GustStrength:   toInt32(m.GustStrength),
+		GustAngle:      toInt32(m.GustAngle),
+		Co2Calibrating: m.CO2Calibrating,
+		MinTemp:        copyPtr(m.MinTemperature),
+		MaxTemp:        copyPtr(m.MaxTemperature),
+		MinHum:         toInt32(m.MinHumidity),
+		MaxHum:         toInt32(m.MaxHumidity),
+		MinPressure:    copyPtr(m.MinPressure),
+		MaxPressure:    copyPtr(m.MaxPressure),
+		MinNoise:       toInt32(m.MinNoise),
+		MaxNoise:       toInt32(m.MaxNoise),
+		SumRain:        copyPtr(m.SumRain),
+		DateMaxGust:    copyPtr(m.MaxGustTime),
+		Rain:           copyPtr(m.Rain),
+		DewPoint:       copyPtr(m.DewPoint),
+		Humidex:        copyPtr(m.Humidex),
+	}
+	if len(m.Flagged) > 0 {
+		p.Flagged = make(map[string]string, len(m.Flagged))
+		for k, v := range m.Flagged {
+			p.Flagged[k] = v
+		}
+	}
+	return p
+}
+
+// MeasureFromProto converts the generated Measure message to a measure.
+func MeasureFromProto(p *netatmopb.Measure) Measure {
+	m := Measure{
+		Account:        p.GetAccount(),
+		DeviceID:       p.GetDeviceId(),
+		ModuleID:       p.GetModuleId(),
+		Timestamp:      p.GetTimestamp(),
+		Temperature:    copyPtr(p.Temperature),
+		CO2:            fromInt32(p.Co2),
+		Humidity:       fromInt32(p.Humidity),
+		Pressure:       copyPtr(p.Pressure),
+		Noise:          fromInt32(p.Noise),
+		WindStrength:   fromInt32(p.WindStrength),
+		WindAngle:      fromInt32(p.WindAngle),
+		GustStrength:   fromInt32(p.GustStrength),
+		GustAngle:      fromInt32(p.GustAngle),
+		CO2Calibrating: p.GetCo2Calibrating(),
+		MinTemperature: copyPtr(p.MinTemp),
+		MaxTemperature: copyPtr(p.MaxTemp),
+		MinHumidity:    fromInt32(p.MinHum),
+		MaxHumidity:    fromInt32(p.MaxHum),
+		MinPressure:    copyPtr(p.MinPressure),
+		MaxPressure:    copyPtr(p.MaxPressure),
+		MinNoise:       fromInt32(p.MinNoise),
+		MaxNoise:       fromInt32(p.MaxNoise),
+		SumRain:        copyPtr(p.SumRain),
+		MaxGustTime:    copyPtr(p.DateMaxGust),
+		Rain:           copyPtr(p.Rain),
+		DewPoint:       copyPtr(p.DewPoint),
+		Humidex:        copyPtr(p.Humidex),
+	}
+	if len(p.GetFlagged()) > 0 {
+		m.Flagged = make(map[string]string, len(p.Flagged))
+		for k, v := range p.Flagged {
+			m.Flagged[k] = v
+		}
+	}
+	return m
+}
+
+// DeviceToProto converts the device and its modules to the generated Device message, without dashboard data.
+func DeviceToProto(d *Device) *netatmopb.Device {
+	p := &netatmopb.Device{
+		Id:              d.ID,
+		CipherId:        d.CipherID,
+		DateSetup:       d.SetupTime,
+		LastSetup:       d.LastSetupTime,
+		Type:            d.Type,
+		LastStatusStore: d.LastStatusStoreTime,
+		ModuleName:      d.ModuleName,
+		Firmware:        int32(d.Firmware),
+		LastUpgrade:     d.LastUpgradeTime,
+		WifiStatus:      int32(d.WiFiStatus),
+		Reachable:       d.Reachable,
+		Co2Calibrating:  d.CO2Calibrating,
+		StationName:     d.StationName,
+		DataType:        append([]string(nil), d.DataTypes...),
+		Place: &netatmopb.Place{
+			Altitude: int32(d.Place.Altitude),
+			City:     d.Place.City,
+			Country:  d.Place.Country,
+			Timezone: d.Place.Timezone,
+			Location: append([]float64(nil), d.Place.Location...),
+		},
+		Account: d.Account,
+	}
+	for i := range d.Modules {
+		m := &d.Modules[i]
+		p.Modules = append(p.Modules, &netatmopb.Module{
+			Id:             m.ID,
+			Type:           m.Type,
+			ModuleName:     m.ModuleName,
+			DataType:       append([]string(nil), m.DataTypes...),
+			LastSetup:      m.LastSetupTime,
+			Reachable:      m.Reachable,
+			Firmware:       int32(m.Firmware),
+			LastMessage:    m.LastMessageTime,
+			LastSeen:       m.LastSeenTime,
+			RfStatus:       int32(m.RFStatus),
+			BatteryVp:      int32(m.BatteryVP),
+			BatteryPercent: int32(m.BatteryPercent),
+		})
+	}
+	return p
+}
+
+// DeviceFromProto converts the generated Device message to a device.
+func DeviceFromProto(p *netatmopb.Device) Device {
+	place := p.GetPlace()
+	d := Device{
+		ID:                  p.GetId(),
+		CipherID:            p.GetCipherId(),
+		SetupTime:           p.GetDateSetup(),
+		LastSetupTime:       p.GetLastSetup(),
+		Type:                p.GetType(),
+		LastStatusStoreTime: p.GetLastStatusStore(),
+		ModuleName:          p.GetModuleName(),
+		Firmware:            int(p.GetFirmware()),
+		LastUpgradeTime:     p.GetLastUpgrade(),
+		WiFiStatus:          int(p.GetWifiStatus()),
+		Reachable:           p.GetReachable(),
+		CO2Calibrating:      p.GetCo2Calibrating(),
+		StationName:         p.GetStationName(),
+		DataTypes:           append([]string(nil), p.GetDataType()...),
+		Place: Place{
+			Altitude: int(place.GetAltitude()),
+			City:     place.GetCity(),
+			Country:  place.GetCountry(),
+			Timezone: place.GetTimezone(),
+			Location: append([]float64(nil), place.GetLocation()...),
+		},
+		Account: p.GetAccount(),
+	}
+	for _, m := range p.GetModules() {
+		d.Modules = append(d.Modules, Module{
+			ID:              m.GetId(),
+			Type:            m.GetType(),
+			ModuleName:      m.GetModuleName(),
+			DataTypes:       append([]string(nil), m.GetDataType()...),
+			LastSetupTime:   m.GetLastSetup(),
+			Reachable:       m.GetReachable(),
+			Firmware:        int(m.GetFirmware()),
+			LastMessageTime: m.GetLastMessage(),
+			LastSeenTime:    m.GetLastSeen(),
+			RFStatus:        int(m.GetRfStatus()),
+			BatteryVP:       int(m.GetBatteryVp()),
+			BatteryPercent:  int(m.GetBatteryPercent()),
+		})
+	}
+	return d
+}
+
+func copyPtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+func toInt32(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	c := int32(*v)
+	return &c
+}
+
+func fromInt32(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	c := int(*v)
+	return &c
+}
+
+// readUvarint reads a varint, returning io.EOF only when no byte was read.
+func readUvarint(r io.ByteReader) (uint64, error) {
+	var v uint64
+	for i := 0; i < 10; i++ {
+		c, err := r.ReadByte()
+		if err == io.EOF && i > 0 {
+			return 0, fmt.Errorf("protobuf: %w", io.ErrUnexpectedEOF)
+		}
+		if err != nil {
+			return 0, err
+		}
+		v |= uint64(c&0x7f) << (7 * i)
+		if c < 0x80 {
+			return v, nil
+		}
+	}
+	return 0, errors.New("protobuf: varint overflow")
+}
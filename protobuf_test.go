@@ -0,0 +1,116 @@
+package netatmo
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fullProtoMeasure sets every field of Measure encoded by the schema.
+func fullProtoMeasure() Measure {
+	f := func(v float64) *float64 { return &v }
+	i := func(v int) *int { return &v }
+	gust := int64(1700000400)
+	return Measure{Account: "home", DeviceID: "70:ee:50:00:00:14", ModuleID: "02:00:00:00:00:01",
+		Timestamp: 1700000000, Temperature: f(21.5), CO2: i(650), Humidity: i(48), Pressure: f(1013.2), Noise: i(38),
+		WindStrength: i(12), WindAngle: i(270), GustStrength: i(25), GustAngle: i(260), CO2Calibrating: true,
+		Flagged: map[string]string{"Temperature": "spike", "Humidity": "range"}, MinTemperature: f(-3.5),
+		MaxTemperature: f(24), MinHumidity: i(30), MaxHumidity: i(90), MinPressure: f(990.5), MaxPressure: f(1030),
+		MinNoise: i(32), MaxNoise: i(60), SumRain: f(12.3), MaxGustTime: &gust, Rain: f(0.101), DewPoint: f(10.1),
+		Humidex: f(24.8)}
+}
+
+func TestMeasureProtoRoundTrip(t *testing.T) {
+	want := fullProtoMeasure()
+	b, err := MarshalMeasureProto(&want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalMeasureProto(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	again, err := MarshalMeasureProto(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again, b) {
+		t.Error("encoding is not deterministic")
+	}
+	empty, err := UnmarshalMeasureProto(nil)
+	if err != nil || !reflect.DeepEqual(empty, Measure{}) {
+		t.Errorf("empty message: got %+v, %v", empty, err)
+	}
+}
+
+// TestMeasureToProtoFields fails when a field of proto/netatmo.proto is not filled from Measure, so the converter
+// follows the schema.
+func TestMeasureToProtoFields(t *testing.T) {
+	m := fullProtoMeasure()
+	message := MeasureToProto(&m).ProtoReflect()
+	fields := message.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if fd := fields.Get(i); !message.Has(fd) {
+			t.Errorf("field %d %s not converted", fd.Number(), fd.Name())
+		}
+	}
+}
+
+func TestDeviceProtoRoundTrip(t *testing.T) {
+	want := Device{ID: "70:ee:50:00:00:14", CipherID: "enc:16:abc", SetupTime: 1500000000,
+		LastSetupTime: 1500000100, Type: "NAMain", LastStatusStoreTime: 1700000000, ModuleName: "Indoor",
+		Firmware: 181, LastUpgradeTime: 1600000000, WiFiStatus: 56, Reachable: true, CO2Calibrating: true,
+		StationName: "Home", DataTypes: []string{"Temperature", "CO2"}, Account: "home",
+		Place: Place{Altitude: 40, City: "千代田区", Country: "JP", Timezone: "Asia/Tokyo",
+			Location: []float64{139.752778, 35.6825}},
+		Modules: []Module{{ID: "02:00:00:00:00:01", Type: "NAModule1", ModuleName: "Outdoor",
+			DataTypes: []string{"Temperature", "Humidity"}, LastSetupTime: 1500000200, Reachable: true, Firmware: 50,
+			LastMessageTime: 1700000000, LastSeenTime: 1699999990, RFStatus: 70, BatteryVP: 5500, BatteryPercent: 80}}}
+	b, err := MarshalDeviceProto(&want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalDeviceProto(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	for _, message := range []protoreflect.Message{DeviceToProto(&want).ProtoReflect(),
+		DeviceToProto(&want).Modules[0].ProtoReflect(), DeviceToProto(&want).Place.ProtoReflect()} {
+		fields := message.Descriptor().Fields()
+		for i := 0; i < fields.Len(); i++ {
+			if fd := fields.Get(i); !message.Has(fd) {
+				t.Errorf("%s: field %d %s not converted", message.Descriptor().Name(), fd.Number(), fd.Name())
+			}
+		}
+	}
+}
+
+func TestMeasuresProtoStream(t *testing.T) {
+	want := []Measure{fullProtoMeasure(), {DeviceID: "70:ee:50:00:00:14", Timestamp: 1700000600}}
+	var buf bytes.Buffer
+	if err := WriteMeasuresProto(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadMeasuresProto(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stream: got %+v, want %+v", got, want)
+	}
+	b, err := MarshalMeasuresProto(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err = UnmarshalMeasuresProto(b); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("batch: got %+v, %v, want %+v", got, err, want)
+	}
+}
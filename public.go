@@ -0,0 +1,199 @@
+package netatmo
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// earthRadiusKm defines mean radius of the earth used for distance computation.
+const earthRadiusKm = 6371.0
+
+// BoundingBox defines rectangular area by its north-east and south-west corners.
+type BoundingBox struct {
+	NorthEastLat float64
+	NorthEastLon float64
+	SouthWestLat float64
+	SouthWestLon float64
+}
+
+// BoundingBoxAround returns the box enclosing the circle of the radius in km around the point.
+func BoundingBoxAround(lat, lon, radiusKm float64) BoundingBox {
+	dLat := radiusKm / (earthRadiusKm * math.Pi / 180)
+	dLon := dLat / math.Max(math.Cos(lat*math.Pi/180), 0.01)
+	return BoundingBox{NorthEastLat: lat + dLat, NorthEastLon: lon + dLon, SouthWestLat: lat - dLat, SouthWestLon: lon - dLon}
+}
+
+// PublicStation defines anonymized public station with its latest readings.
+type PublicStation struct {
+	ID           string
+	Latitude     float64
+	Longitude    float64
+	Altitude     int
+	Timezone     string
+	Country      string
+	City         string
+	Timestamp    int64    // Time of the newest temperature, humidity or pressure reading
+	Temperature  *float64 // Nullable
+	Humidity     *int     // Nullable
+	Pressure     *float64 // Nullable
+	Rain60Min    *float64 // Nullable
+	Rain24H      *float64 // Nullable
+	RainLive     *float64 // Nullable
+	WindStrength *int     // Nullable
+	WindAngle    *int     // Nullable
+	GustStrength *int     // Nullable
+	GustAngle    *int     // Nullable
+	Distance     float64  // Distance in km from the queried point, set by NearestPublicStations
+}
+
+type publicMeasure struct {
+	Res          map[string][]*float64 `json:"res"` // Keyed by unix time
+	Type         []string              `json:"type"`
+	Rain60Min    *float64              `json:"rain_60min"`
+	Rain24H      *float64              `json:"rain_24h"`
+	RainLive     *float64              `json:"rain_live"`
+	WindStrength *int                  `json:"wind_strength"`
+	WindAngle    *int                  `json:"wind_angle"`
+	GustStrength *int                  `json:"gust_strength"`
+	GustAngle    *int                  `json:"gust_angle"`
+}
+
+type publicStationBody struct {
+	ID    string `json:"_id"`
+	Place struct {
+		Location []float64 `json:"location"` // Lon, Lat
+		Timezone string    `json:"timezone"`
+		Country  string    `json:"country"`
+		Altitude float64   `json:"altitude"`
+		City     string    `json:"city"`
+	} `json:"place"`
+	Measures map[string]publicMeasure `json:"measures"` // Keyed by module ID
+}
+
+type getPublicDataResponse struct {
+	Body       []publicStationBody `json:"body"`
+	Status     string              `json:"status"`
+	ExecTime   float64             `json:"time_exec"`
+	ServerTime int64               `json:"time_server"`
+}
+
+// GetPublicData gathers anonymized data of public stations within the box. Optional required data (ex.
+// "temperature", "rain") limits results to stations having them.
+// Reference: https://dev.netatmo.com/apidocumentation/weather#getpublicdata
+func (c *Client) GetPublicData(box BoundingBox, requiredData ...string) ([]PublicStation, error) {
+	return c.GetPublicDataContext(context.Background(), box, requiredData...)
+}
+
+// GetPublicDataContext is GetPublicData with a context canceling the request.
+func (c *Client) GetPublicDataContext(ctx context.Context, box BoundingBox, requiredData ...string) ([]PublicStation,
+	error) {
+	query := url.Values{
+		"lat_ne": {strconv.FormatFloat(box.NorthEastLat, 'f', -1, 64)},
+		"lon_ne": {strconv.FormatFloat(box.NorthEastLon, 'f', -1, 64)},
+		"lat_sw": {strconv.FormatFloat(box.SouthWestLat, 'f', -1, 64)},
+		"lon_sw": {strconv.FormatFloat(box.SouthWestLon, 'f', -1, 64)},
+	}
+	if len(requiredData) > 0 {
+		query.Set("required_data", strings.Join(requiredData, ","))
+	}
+	data, err := c.get(ctx, "getpublicdata", query)
+	if err != nil {
+		return nil, err
+	}
+	var response getPublicDataResponse
+	if err := c.decode("getpublicdata", data, &response); err != nil {
+		return nil, err
+	}
+	stations := make([]PublicStation, 0, len(response.Body))
+	for _, body := range response.Body {
+		stations = append(stations, body.station())
+	}
+	return stations, nil
+}
+
+// NearestPublicStations gathers up to n public stations within the radius in km around the point, nearest first.
+func (c *Client) NearestPublicStations(lat, lon, radiusKm float64, n int) ([]PublicStation, error) {
+	return c.NearestPublicStationsContext(context.Background(), lat, lon, radiusKm, n)
+}
+
+// NearestPublicStationsContext is NearestPublicStations with a context canceling the request.
+func (c *Client) NearestPublicStationsContext(ctx context.Context, lat, lon, radiusKm float64,
+	n int) ([]PublicStation, error) {
+	stations, err := c.GetPublicDataContext(ctx, BoundingBoxAround(lat, lon, radiusKm))
+	if err != nil {
+		return nil, err
+	}
+	var nearest []PublicStation
+	for _, s := range stations {
+		s.Distance = Distance(lat, lon, s.Latitude, s.Longitude)
+		if s.Distance <= radiusKm {
+			nearest = append(nearest, s)
+		}
+	}
+	sort.Slice(nearest, func(i, j int) bool { return nearest[i].Distance < nearest[j].Distance })
+	if n > 0 && len(nearest) > n {
+		nearest = nearest[:n]
+	}
+	return nearest, nil
+}
+
+// Distance computes great-circle distance in km between two points using the haversine formula.
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLon := (lon2 - lon1) * toRad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func (b *publicStationBody) station() PublicStation {
+	s := PublicStation{
+		ID:       b.ID,
+		Altitude: int(b.Place.Altitude),
+		Timezone: b.Place.Timezone,
+		Country:  b.Place.Country,
+		City:     b.Place.City,
+	}
+	if len(b.Place.Location) == 2 {
+		s.Longitude = b.Place.Location[0]
+		s.Latitude = b.Place.Location[1]
+	}
+	for _, m := range b.Measures {
+		if m.Rain60Min != nil || m.Rain24H != nil || m.RainLive != nil {
+			s.Rain60Min, s.Rain24H, s.RainLive = m.Rain60Min, m.Rain24H, m.RainLive
+		}
+		if m.WindStrength != nil {
+			s.WindStrength, s.WindAngle, s.GustStrength, s.GustAngle = m.WindStrength, m.WindAngle, m.GustStrength, m.GustAngle
+		}
+		var newest int64
+		var values []*float64
+		for ts, v := range m.Res {
+			if t, err := strconv.ParseInt(ts, 10, 64); err == nil && t > newest {
+				newest, values = t, v
+			}
+		}
+		for i, name := range m.Type {
+			if i >= len(values) || values[i] == nil {
+				continue
+			}
+			v := *values[i]
+			switch name {
+			case "temperature":
+				s.Temperature = &v
+			case "humidity":
+				s.Humidity = roundInt(v)
+			case "pressure":
+				s.Pressure = &v
+			}
+		}
+		if newest > s.Timestamp {
+			s.Timestamp = newest
+		}
+	}
+	return s
+}
@@ -0,0 +1,41 @@
+package netatmo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// pwsWeatherURL defines PWSWeather upload endpoint, compatible with the Weather Underground protocol.
+const pwsWeatherURL = "https://pwsupdate.pwsweather.com/api/v1/submitwx"
+
+// PWSWeatherUploader uploads observations to a PWSWeather.com station.
+type PWSWeatherUploader struct {
+	StationID  string       // Station ID
+	Key        string       // API key of the station
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+	tracker    uploadTracker
+}
+
+// Upload sends the observation. Observations not newer than the last uploaded one of the device are skipped.
+func (u *PWSWeatherUploader) Upload(ctx context.Context, o Observation) error {
+	if !u.tracker.isNew(&o) {
+		return nil
+	}
+	query := url.Values{}
+	encodeFields(query, &o, imperialFields)
+	query.Set("ID", u.StationID)
+	query.Set("PASSWORD", u.Key)
+	query.Set("dateutc", time.Unix(o.Time, 0).UTC().Format("2006-01-02 15:04:05"))
+	query.Set("softwaretype", "netatmo-weather-go")
+	masked := pwsWeatherURL + "?" + maskQuery(query, "PASSWORD")
+	if dryRun(ctx, "pwsweather", "observation of "+u.StationID, masked) {
+		return nil
+	}
+	if _, err := uploadGet(ctx, u.HTTPClient, pwsWeatherURL, query, masked); err != nil {
+		return err
+	}
+	u.tracker.uploaded(&o)
+	return nil
+}
@@ -0,0 +1,221 @@
+package netatmo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Aggregation defines statistic taken from aggregated buckets.
+type Aggregation string
+
+// Supported aggregations.
+const (
+	AggregationMean  Aggregation = "mean"
+	AggregationMin   Aggregation = "min"
+	AggregationMax   Aggregation = "max"
+	AggregationSum   Aggregation = "sum"
+	AggregationCount Aggregation = "count"
+)
+
+// value returns the statistic of the aggregation.
+func (a Aggregation) value(s Stats) (float64, error) {
+	switch a {
+	case AggregationMean, "":
+		return s.Mean(), nil
+	case AggregationMin:
+		return s.Min, nil
+	case AggregationMax:
+		return s.Max, nil
+	case AggregationSum:
+		return s.Sum, nil
+	case AggregationCount:
+		return float64(s.Count), nil
+	default:
+		return 0, fmt.Errorf("unknown aggregation: %s", a)
+	}
+}
+
+// Query defines conditions of a historical query over a Store.
+type Query struct {
+	Account     string      // Empty matches all accounts
+	DeviceID    string      // Empty matches all devices
+	ModuleID    string      // Empty matches all modules
+	Metrics     []string    // Measurement names (ex. Temperature), empty means all TargetMeasurements
+	Begin       int64       // Inclusive unix time, 0 means unbounded
+	End         int64       // Inclusive unix time, 0 means now
+	Resolution  Resolution  // Empty chooses the resolution by MaxPoints
+	MaxPoints   int         // Maximum number of points per series used to choose the resolution, 0 means raw
+	Aggregation Aggregation // Statistic of aggregated resolutions, empty means mean; ignored for raw data
+}
+
+// Point defines single value of a series.
+type Point struct {
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+// Series defines time series of a single measurement of a module.
+type Series struct {
+	Account     string      `json:"account,omitempty"`
+	DeviceID    string      `json:"device_id"`
+	ModuleID    string      `json:"module_id"`
+	Metric      string      `json:"metric"`
+	Resolution  Resolution  `json:"resolution"`
+	Aggregation Aggregation `json:"aggregation,omitempty"`
+	Points      []Point     `json:"points"`
+}
+
+// QueryStore answers the query from the store without calling the Netatmo API. It returns one series per module
+// and metric having at least one point, ordered by account, device, module and then the order of the requested
+// metrics.
+// Aggregated resolutions are served like LoadMeasures, including ranges not compacted yet.
+func QueryStore(ctx context.Context, store Store, q Query) ([]Series, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+	metrics := q.Metrics
+	if len(metrics) == 0 {
+		metrics = TargetMeasurements
+	}
+	filter := MeasureFilter{Account: q.Account, DeviceID: q.DeviceID, ModuleID: q.ModuleID, Begin: q.Begin, End: q.End}
+	res := q.Resolution
+	if res == "" {
+		res = loadResolution(ctx, filter, q.MaxPoints)
+	}
+	if res != ResolutionRaw {
+		for _, name := range metrics {
+			if targetMeasurement(name) == "" {
+				return nil, fmt.Errorf("measurement type %s is not aggregated, query it at raw resolution", name)
+			}
+		}
+	}
+	builder := newSeriesBuilder(metrics)
+	if res == ResolutionRaw {
+		measures, err := store.Measures(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := range measures {
+			m := &measures[i]
+			for _, name := range metrics {
+				if v, ok := m.Value(name); ok {
+					builder.add(m.Account, m.DeviceID, m.ModuleID, name, res, "", Point{Timestamp: m.Timestamp, Value: v})
+				}
+			}
+		}
+		return builder.result(), nil
+	}
+	aggregates, err := loadAggregates(ctx, store, res, filter)
+	if err != nil {
+		return nil, err
+	}
+	aggregation := q.Aggregation
+	if aggregation == "" {
+		aggregation = AggregationMean
+	}
+	for i := range aggregates {
+		a := &aggregates[i]
+		for _, name := range metrics {
+			stats, ok := a.Metrics[name]
+			if !ok || stats.Count == 0 {
+				continue
+			}
+			v, _ := aggregation.value(stats) // Validated
+			builder.add(a.Account, a.DeviceID, a.ModuleID, name, res, aggregation, Point{Timestamp: a.Timestamp, Value: v})
+		}
+	}
+	return builder.result(), nil
+}
+
+// validate checks the query before reading the store, so invalid queries fail even without matching data. Metrics
+// are respelled like TargetMeasurements (ex. temperature as Temperature), the keys of aggregates.
+func (q *Query) validate() error {
+	metrics := make([]string, len(q.Metrics))
+	for i, name := range q.Metrics {
+		if _, ok := measureFields[strings.ToLower(name)]; !ok {
+			return fmt.Errorf("unknown measurement type: %s", name)
+		}
+		metrics[i] = targetMeasurement(name)
+		if metrics[i] == "" {
+			if q.Resolution != "" && q.Resolution != ResolutionRaw {
+				return fmt.Errorf("measurement type %s is not aggregated, query it at raw resolution", name)
+			}
+			metrics[i] = name
+		}
+	}
+	if len(q.Metrics) > 0 {
+		q.Metrics = metrics
+	}
+	switch q.Resolution {
+	case "", ResolutionRaw, ResolutionHourly, ResolutionDaily:
+	default:
+		return fmt.Errorf("unknown resolution: %s", q.Resolution)
+	}
+	if _, err := q.Aggregation.value(Stats{}); err != nil {
+		return err
+	}
+	if q.MaxPoints < 0 {
+		return fmt.Errorf("negative max points: %d", q.MaxPoints)
+	}
+	if q.End != 0 && q.Begin > q.End {
+		return fmt.Errorf("%w: begin %d is after end %d", ErrInvalidTimeRange, q.Begin, q.End)
+	}
+	return nil
+}
+
+// targetMeasurement returns the TargetMeasurements spelling of the name, empty if it is not one of them.
+func targetMeasurement(name string) string {
+	for _, target := range TargetMeasurements {
+		if strings.EqualFold(name, target) {
+			return target
+		}
+	}
+	return ""
+}
+
+// seriesBuilder groups points into series.
+type seriesBuilder struct {
+	metrics []string
+	index   map[string]int
+	series  []Series
+}
+
+func newSeriesBuilder(metrics []string) *seriesBuilder {
+	return &seriesBuilder{metrics: metrics, index: make(map[string]int)}
+}
+
+func (b *seriesBuilder) add(account, deviceID, moduleID, metric string, res Resolution, aggregation Aggregation, p Point) {
+	k := account + "\x00" + deviceID + "\x00" + moduleID + "\x00" + metric
+	i, ok := b.index[k]
+	if !ok {
+		i = len(b.series)
+		b.index[k] = i
+		b.series = append(b.series, Series{Account: account, DeviceID: deviceID, ModuleID: moduleID, Metric: metric, Resolution: res,
+			Aggregation: aggregation})
+	}
+	b.series[i].Points = append(b.series[i].Points, p)
+}
+
+// result returns series ordered by account, device, module and the order of the requested metrics.
+func (b *seriesBuilder) result() []Series {
+	rank := make(map[string]int)
+	for i, name := range b.metrics {
+		rank[name] = i
+	}
+	sort.SliceStable(b.series, func(i, j int) bool {
+		x, y := &b.series[i], &b.series[j]
+		if x.Account != y.Account {
+			return x.Account < y.Account
+		}
+		if x.DeviceID != y.DeviceID {
+			return x.DeviceID < y.DeviceID
+		}
+		if x.ModuleID != y.ModuleID {
+			return x.ModuleID < y.ModuleID
+		}
+		return rank[x.Metric] < rank[y.Metric]
+	})
+	return b.series
+}
@@ -0,0 +1,175 @@
+package netatmo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultQuestDBNaming defines table and tag naming of QuestDBSink without explicit naming: a single "netatmo"
+// table with account, device and module tags (symbols) and a column per measurement.
+var DefaultQuestDBNaming = MetricNaming{Name: "netatmo"}
+
+// QuestDBSink writes measures to QuestDB with the InfluxDB line protocol (ILP), over HTTP or TCP. Tables come from
+// the name of Namer and tags from its labels, so measurements of a measure with the same name are written as
+// columns of one row (snake case, ex. wind_strength); use a name template with the metric
+// (ex. "{{snake .Metric}}") for a table per measurement.
+type QuestDBSink struct {
+	// URL of the server: http(s)://host:9000 writes over HTTP (recommended, with errors reported per request) and
+	// tcp://host:9009 over plain TCP.
+	URL        string
+	Token      string        // Bearer token of HTTP, optional
+	Username   string        // Basic authentication of HTTP, optional
+	Password   string        // Optional
+	Timeout    time.Duration // TCP dial and write timeout, defaults to 10 seconds
+	Namer      *MetricNamer  // Tables and tags, defaults to DefaultQuestDBNaming
+	HTTPClient *http.Client  // Defaults to http.DefaultClient
+	mu         sync.Mutex
+	conn       net.Conn
+}
+
+// Write sends lines of the measures in a single request or TCP write. The TCP connection is opened on first write
+// and reopened after failures.
+func (s *QuestDBSink) Write(ctx context.Context, measures []Measure) error {
+	if len(measures) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var body bytes.Buffer
+	for i := range measures {
+		if err := s.writeLines(&body, &measures[i]); err != nil {
+			return fmt.Errorf("questdb: %w", err)
+		}
+	}
+	if dryRun(ctx, "questdb", fmt.Sprintf("%d measures to %s", len(measures), s.URL), body.String()) {
+		return nil
+	}
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return fmt.Errorf("questdb: %w", err)
+	}
+	if u.Scheme == "tcp" {
+		err = s.send(ctx, u.Host, body.Bytes())
+	} else {
+		_, err = sinkPost(ctx, s.HTTPClient, strings.TrimSuffix(s.URL, "/")+"/write", "text/plain; charset=utf-8",
+			&body, s.authorize)
+	}
+	if err != nil {
+		return fmt.Errorf("questdb: %w", err)
+	}
+	return nil
+}
+
+// Close closes the TCP connection.
+func (s *QuestDBSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// writeLines writes a line per table and tag set of the measure.
+func (s *QuestDBSink) writeLines(b *bytes.Buffer, m *Measure) error {
+	if s.Namer == nil {
+		namer, err := NewMetricNamer(DefaultQuestDBNaming)
+		if err != nil {
+			return err
+		}
+		s.Namer = namer
+	}
+	return writeILP(b, s.Namer, m)
+}
+
+// writeILP writes a line protocol line per name (table or measurement) and tag set of the measure, with the
+// measurements as fields and a timestamp in nanoseconds.
+func writeILP(b *bytes.Buffer, namer *MetricNamer, m *Measure) error {
+	var keys []string
+	lines := make(map[string][]string)
+	for _, v := range sinkValues(m) {
+		table, tags, err := namer.Name(m, v.Name)
+		if err != nil {
+			return err
+		}
+		key := escapeILP(table, ", ") + ilpTags(tags)
+		if _, ok := lines[key]; !ok {
+			keys = append(keys, key)
+		}
+		lines[key] = append(lines[key], escapeILP(snakeCase(v.Name), ",= ")+"="+
+			strconv.FormatFloat(v.Value, 'f', -1, 64))
+	}
+	for _, key := range keys {
+		fmt.Fprintf(b, "%s %s %d\n", key, strings.Join(lines[key], ","), m.Timestamp*int64(time.Second))
+	}
+	return nil
+}
+
+// send writes the lines to the TCP endpoint.
+func (s *QuestDBSink) send(ctx context.Context, addr string, data []byte) error {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	if s.conn == nil {
+		dialer := net.Dialer{Timeout: timeout}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	_ = s.conn.SetWriteDeadline(time.Now().Add(timeout))
+	if _, err := s.conn.Write(data); err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (s *QuestDBSink) authorize(req *http.Request) {
+	switch {
+	case s.Token != "":
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	case s.Username != "":
+		req.SetBasicAuth(s.Username, s.Password)
+	}
+}
+
+// ilpTags returns the tag set sorted by key, starting with a comma unless empty.
+func ilpTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString("," + escapeILP(k, ",= ") + "=" + escapeILP(tags[k], ",= "))
+	}
+	return b.String()
+}
+
+// escapeILP escapes the special characters of the line protocol element with backslashes.
+func escapeILP(s, special string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r == '\\' || strings.ContainsRune(special, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package netatmo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Rain defines current data of a rain gauge module.
+type Rain struct {
+	DeviceID   string
+	ModuleID   string
+	Timestamp  int64    // Time of the latest sample
+	Current    *float64 // Nullable, rain of the latest sample in mm
+	LastHour   *float64 // Nullable, sum of rain in the last hour in mm
+	Last24Hour *float64 // Nullable, sum of rain in the last 24 hours in mm
+}
+
+// Rate returns current rain rate in mm/h, extrapolated from the latest 5 minutes sample.
+func (r *Rain) Rate() (float64, bool) {
+	if r.Current == nil {
+		return 0, false
+	}
+	return *r.Current * float64(time.Hour/(5*time.Minute)), true
+}
+
+// rainAmount returns rain of the measure in mm: sum_rain of aggregated steps, or rain of the raw sample.
+func (m *Measure) rainAmount() *float64 {
+	if m.SumRain != nil {
+		return m.SumRain
+	}
+	return m.Rain
+}
+
+// FindModule returns the first module of the device having the data type (ex. "Rain", "Wind").
+func (d *Device) FindModule(dataType string) *Module {
+	for i := range d.Modules {
+		if sliceContains(d.Modules[i].DataTypes, dataType) {
+			return &d.Modules[i]
+		}
+	}
+	return nil
+}
+
+// GetRain gathers current data of the rain gauge module attached to the device.
+func (c *Client) GetRain(deviceID string) (*Rain, error) {
+	return c.GetRainContext(context.Background(), deviceID)
+}
+
+// GetRainContext is GetRain with a context canceling the request.
+func (c *Client) GetRainContext(ctx context.Context, deviceID string) (*Rain, error) {
+	device, err := c.getDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	module := device.FindModule("Rain")
+	if module == nil {
+		return nil, fmt.Errorf("no rain gauge on device %s", deviceID)
+	}
+	rain := &Rain{DeviceID: deviceID, ModuleID: module.ID}
+	if data := module.DashboardData; data != nil {
+		rain.Timestamp = data.UTCTime
+		rain.Current = data.Rain
+		rain.LastHour = data.RainPerHour
+		rain.Last24Hour = data.RainPerDay
+	}
+	return rain, nil
+}
+
+// RainAccumulation gathers the sum of rain in mm between the unix times from the sum_rain history of the rain gauge
+// module. The window is aligned to 30 minutes steps of the API.
+func (c *Client) RainAccumulation(deviceID, moduleID string, from, to int64) (float64, error) {
+	return c.RainAccumulationContext(context.Background(), deviceID, moduleID, from, to)
+}
+
+// RainAccumulationContext is RainAccumulation with a context canceling the requests.
+func (c *Client) RainAccumulationContext(ctx context.Context, deviceID, moduleID string, from,
+	to int64) (float64, error) {
+	measures, err := c.getMeasurePages(ctx, deviceID, moduleID, from, to,
+		[]MeasureOption{WithScale(Scale30Min), WithTypes("sum_rain")})
+	if err != nil {
+		return 0, err
+	}
+	sum := 0.0
+	for _, m := range measures {
+		if m.SumRain != nil {
+			sum += *m.SumRain
+		}
+	}
+	return sum, nil
+}
+
+func sliceContains(slice []string, value string) bool {
+	for _, item := range slice {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
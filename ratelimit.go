@@ -0,0 +1,95 @@
+package netatmo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit defines a number of requests allowed per sliding window.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// DefaultRateLimits defines the per-user quotas of the Netatmo API.
+var DefaultRateLimits = []RateLimit{
+	{Requests: 50, Per: 10 * time.Second},
+	{Requests: 500, Per: time.Hour},
+}
+
+// WithRateLimit paces API requests of the client to stay under the limits, DefaultRateLimits if none are given.
+// Requests over a limit wait until the window allows them (counted as RateLimitWaits in Stats), or fail early if the
+// context is canceled or the client is closed. Token requests are not limited. Clients of the same account should
+// share the limits by using a single client.
+func WithRateLimit(limits ...RateLimit) Option {
+	if len(limits) == 0 {
+		limits = DefaultRateLimits
+	}
+	return func(o *clientOptions) {
+		o.rateLimiter = &rateLimiter{limits: limits, sent: make([][]time.Time, len(limits))}
+	}
+}
+
+// rateLimiter keeps the send times of recent requests per limit.
+type rateLimiter struct {
+	mu     sync.Mutex
+	limits []RateLimit
+	sent   [][]time.Time // Ascending send times within the window of each limit
+}
+
+// reserve records a request sent now and returns zero if the limits allow it, otherwise it returns the time to wait
+// before trying again.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var wait time.Duration
+	for i, limit := range l.limits {
+		sent := l.sent[i]
+		for len(sent) > 0 && !sent[0].After(now.Add(-limit.Per)) {
+			sent = sent[1:]
+		}
+		l.sent[i] = sent
+		if len(sent) >= limit.Requests {
+			wait = max(wait, sent[len(sent)-limit.Requests].Add(limit.Per).Sub(now))
+		}
+	}
+	if wait > 0 {
+		return wait
+	}
+	for i := range l.limits {
+		l.sent[i] = append(l.sent[i], now)
+	}
+	return 0
+}
+
+// waitRateLimit blocks until the rate limiter of the client allows a request.
+func (c *Client) waitRateLimit(ctx context.Context) error {
+	l := c.options.rateLimiter
+	if l == nil {
+		return nil
+	}
+	waited := false
+	for {
+		wait := l.reserve(c.now())
+		if wait == 0 {
+			return nil
+		}
+		if !waited {
+			c.stats.rateLimitWait()
+			waited = true
+		}
+		c.logRateLimitWait(ctx, wait)
+		ticker := orSystemClock(c.options.clock).NewTicker(wait)
+		select {
+		case <-ticker.C():
+			ticker.Stop()
+		case <-ctx.Done():
+			ticker.Stop()
+			return ctx.Err()
+		case <-c.closed:
+			ticker.Stop()
+			return ErrClientClosed
+		}
+	}
+}
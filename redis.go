@@ -0,0 +1,255 @@
+package netatmo
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRedisTimeSeriesNaming defines key and label naming of RedisTimeSeriesSink without explicit naming
+// (ex. netatmo:70:ee:50:00:00:14:wind_strength).
+var DefaultRedisTimeSeriesNaming = MetricNaming{
+	Name:      "netatmo:{{.ModuleID}}:{{snake .Metric}}",
+	Sanitizer: SanitizeNone,
+}
+
+// RedisTimeSeriesSink writes measures into RedisTimeSeries with TS.ADD, one key per module and measurement. Keys
+// are created on first write with the retention, duplicate policy and labels (account, device, module, metric and
+// unit by default), so they can be queried with TS.MRANGE FILTER (ex. FILTER metric=temperature).
+type RedisTimeSeriesSink struct {
+	Addr            string        // Address (ex. localhost:6379)
+	Username        string        // ACL user, optional
+	Password        string        // Optional
+	DB              int           // Database number, optional
+	Retention       time.Duration // Retention of created keys, 0 keeps samples forever
+	DuplicatePolicy string        // Policy of samples of an existing timestamp (ex. LAST, FIRST, MAX), defaults to LAST
+	Timeout         time.Duration // Dial and I/O timeout, defaults to 10 seconds
+	Namer           *MetricNamer  // Key names and labels, defaults to DefaultRedisTimeSeriesNaming
+	mu              sync.Mutex
+	conn            net.Conn
+	reader          *bufio.Reader
+}
+
+// Write adds samples of the measures in a single pipeline. The connection is opened on first write and reopened
+// after failures.
+func (s *RedisTimeSeriesSink) Write(ctx context.Context, measures []Measure) error {
+	if len(measures) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var commands [][]string
+	for i := range measures {
+		m := &measures[i]
+		for _, v := range sinkValues(m) {
+			command, err := s.add(m, v)
+			if err != nil {
+				return fmt.Errorf("redis: %w", err)
+			}
+			commands = append(commands, command)
+		}
+	}
+	if IsDryRun(ctx) {
+		lines := make([]string, len(commands))
+		for i, command := range commands {
+			lines[i] = strings.Join(command, " ")
+		}
+		dryRun(ctx, "redis", fmt.Sprintf("%d samples of %d measures to %s", len(commands), len(measures), s.Addr),
+			strings.Join(lines, "\n"))
+		return nil
+	}
+	if err := s.pipeline(ctx, commands); err != nil {
+		return fmt.Errorf("redis: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection.
+func (s *RedisTimeSeriesSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn, s.reader = nil, nil
+	return err
+}
+
+// add returns TS.ADD command of the value.
+func (s *RedisTimeSeriesSink) add(m *Measure, v sinkValue) ([]string, error) {
+	namer := s.Namer
+	if namer == nil {
+		var err error
+		if namer, err = NewMetricNamer(DefaultRedisTimeSeriesNaming); err != nil {
+			return nil, err
+		}
+		s.Namer = namer
+	}
+	key, labels, err := namer.Name(m, v.Name)
+	if err != nil {
+		return nil, err
+	}
+	labels["metric"] = snakeCase(v.Name)
+	if unit, ok := metricUnits[v.Name]; ok {
+		labels["unit"] = unit
+	}
+	policy := s.DuplicatePolicy
+	if policy == "" {
+		policy = "LAST"
+	}
+	command := []string{"TS.ADD", key, strconv.FormatInt(m.Timestamp*1000, 10),
+		strconv.FormatFloat(v.Value, 'f', -1, 64), "ON_DUPLICATE", policy}
+	if s.Retention > 0 {
+		command = append(command, "RETENTION", strconv.FormatInt(s.Retention.Milliseconds(), 10))
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	command = append(command, "LABELS")
+	for _, name := range names {
+		command = append(command, name, labels[name])
+	}
+	return command, nil
+}
+
+// pipeline sends the commands and reads all replies, returning the first error reply.
+func (s *RedisTimeSeriesSink) pipeline(ctx context.Context, commands [][]string) error {
+	if err := s.connect(ctx); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(s.timeout())
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = s.conn.SetDeadline(deadline)
+	w := bufio.NewWriter(s.conn)
+	for _, command := range commands {
+		writeRESP(w, command)
+	}
+	err := w.Flush()
+	var first error
+	for i := 0; err == nil && i < len(commands); i++ {
+		var reply redisError
+		if err = readRESP(s.reader); errors.As(err, &reply) {
+			if first == nil {
+				first = err
+			}
+			err = nil
+		}
+	}
+	if err != nil {
+		_ = s.conn.Close()
+		s.conn, s.reader = nil, nil
+		return err
+	}
+	return first
+}
+
+// connect dials and authenticates unless connected.
+func (s *RedisTimeSeriesSink) connect(ctx context.Context) error {
+	if s.conn != nil {
+		return nil
+	}
+	dialer := net.Dialer{Timeout: s.timeout()}
+	conn, err := dialer.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	s.conn, s.reader = conn, bufio.NewReader(conn)
+	var setup [][]string
+	switch {
+	case s.Username != "":
+		setup = append(setup, []string{"AUTH", s.Username, s.Password})
+	case s.Password != "":
+		setup = append(setup, []string{"AUTH", s.Password})
+	}
+	if s.DB != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(s.DB)})
+	}
+	if len(setup) == 0 {
+		return nil
+	}
+	if err := s.pipeline(ctx, setup); err != nil {
+		if s.conn != nil {
+			_ = s.conn.Close()
+			s.conn, s.reader = nil, nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *RedisTimeSeriesSink) timeout() time.Duration {
+	if s.Timeout > 0 {
+		return s.Timeout
+	}
+	return 10 * time.Second
+}
+
+// writeRESP writes the command as an array of bulk strings.
+func writeRESP(w *bufio.Writer, command []string) {
+	fmt.Fprintf(w, "*%d\r\n", len(command))
+	for _, arg := range command {
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+}
+
+// redisError defines an error reply.
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// readRESP reads a reply, returning error replies as redisError.
+func readRESP(r *bufio.Reader) error {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return errors.New("invalid reply: " + strconv.Quote(line))
+	}
+	line = line[:len(line)-2]
+	switch line[0] {
+	case '+', ':':
+		return nil
+	case '-':
+		return redisError(line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return err
+		}
+		_, err = r.Discard(n + 2)
+		return err
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return err
+		}
+		var first error
+		for i := 0; i < n; i++ {
+			var reply redisError
+			if err := readRESP(r); errors.As(err, &reply) {
+				if first == nil {
+					first = err
+				}
+			} else if err != nil {
+				return err
+			}
+		}
+		return first
+	}
+	return errors.New("invalid reply: " + strconv.Quote(line))
+}
@@ -0,0 +1,243 @@
+package netatmo
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// LowBatteryPercent defines battery percent at or below which reports flag a module.
+const LowBatteryPercent = 20
+
+// CO2Bands defines CO2 concentration bands in ppm used by reports.
+var CO2Bands = []CO2Band{
+	{Label: "good", Max: 800},
+	{Label: "fair", Min: 800, Max: 1200},
+	{Label: "poor", Min: 1200, Max: 2000},
+	{Label: "bad", Min: 2000, Max: math.Inf(1)},
+}
+
+// CO2Band defines a CO2 concentration range [Min, Max) in ppm.
+type CO2Band struct {
+	Label string
+	Min   float64
+	Max   float64
+}
+
+// Report defines a summary of stored data of a period per station.
+type Report struct {
+	Title    string
+	Begin    time.Time
+	End      time.Time
+	Stations []StationReport
+}
+
+// StationReport defines the summary of a station.
+type StationReport struct {
+	DeviceID  string
+	Name      string
+	Days      []DaySummary // Days of the station time zone with outdoor or rain data, ordered by date
+	CO2       []CO2Distribution
+	Batteries []BatteryStatus
+}
+
+// DaySummary defines outdoor temperature extremes and rain total of a day.
+type DaySummary struct {
+	Date time.Time // Midnight of the day in the station time zone
+	Low  *float64  // Nullable, °C
+	High *float64  // Nullable, °C
+	Rain *float64  // Nullable, mm
+}
+
+// CO2Distribution defines distribution of CO2 concentrations of an indoor module.
+type CO2Distribution struct {
+	ModuleID   string
+	ModuleName string
+	Samples    int
+	Median     float64 // ppm
+	P90        float64 // 90th percentile, ppm
+	Max        float64 // ppm
+	Bands      []CO2BandShare
+}
+
+// CO2BandShare defines share of samples within a band.
+type CO2BandShare struct {
+	CO2Band
+	Percent float64
+}
+
+// BatteryStatus defines battery health of a battery powered module.
+type BatteryStatus struct {
+	ModuleID   string
+	ModuleName string
+	Percent    int
+	Reachable  bool
+	Low        bool // Percent at or below LowBatteryPercent
+}
+
+// BuildReport summarizes stored devices and measures between the times. Days follow the time zone of each station,
+// falling back to the local time zone.
+func BuildReport(ctx context.Context, store Store, title string, begin, end time.Time) (*Report, error) {
+	devices, err := store.Devices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Title: title, Begin: begin, End: end}
+	for _, d := range devices {
+		measures, err := store.Measures(ctx, MeasureFilter{Account: d.Account, DeviceID: d.ID, Begin: begin.Unix(),
+			End: end.Unix()})
+		if err != nil {
+			return nil, err
+		}
+		report.Stations = append(report.Stations, summarizeStation(&d, measures))
+	}
+	return report, nil
+}
+
+func summarizeStation(d *Device, measures []Measure) StationReport {
+	station := StationReport{DeviceID: d.ID, Name: d.StationName, Days: summarizeDays(d, measures)}
+	co2 := make(map[string][]float64)
+	for i := range measures {
+		if m := &measures[i]; m.CO2 != nil {
+			co2[m.ModuleID] = append(co2[m.ModuleID], float64(*m.CO2))
+		}
+	}
+	if values := co2[d.ID]; len(values) > 0 {
+		station.CO2 = append(station.CO2, co2Distribution(d.ID, d.ModuleName, values))
+	}
+	for _, m := range d.Modules {
+		if values := co2[m.ID]; len(values) > 0 {
+			station.CO2 = append(station.CO2, co2Distribution(m.ID, m.ModuleName, values))
+		}
+		station.Batteries = append(station.Batteries, BatteryStatus{ModuleID: m.ID, ModuleName: m.ModuleName,
+			Percent: m.BatteryPercent, Reachable: m.Reachable, Low: m.BatteryPercent <= LowBatteryPercent})
+	}
+	return station
+}
+
+// summarizeDays returns outdoor temperature extremes and rain totals of days of the station time zone, ordered by
+// date.
+func summarizeDays(d *Device, measures []Measure) []DaySummary {
+	location := stationLocation(d)
+	outdoor, rain := findModuleByType(d, TypeOutdoor), findModuleByType(d, TypeRain)
+	days := make(map[time.Time]*DaySummary)
+	day := func(timestamp int64) *DaySummary {
+		t := time.Unix(timestamp, 0).In(location)
+		date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+		if days[date] == nil {
+			days[date] = &DaySummary{Date: date}
+		}
+		return days[date]
+	}
+	for i := range measures {
+		m := &measures[i]
+		if outdoor != nil && m.ModuleID == outdoor.ID && m.Temperature != nil {
+			s := day(m.Timestamp)
+			t := *m.Temperature
+			if s.Low == nil || t < *s.Low {
+				s.Low = &t
+			}
+			if s.High == nil || t > *s.High {
+				s.High = &t
+			}
+		}
+		if v := m.rainAmount(); rain != nil && m.ModuleID == rain.ID && v != nil {
+			s := day(m.Timestamp)
+			total := *v
+			if s.Rain != nil {
+				total += *s.Rain
+			}
+			s.Rain = &total
+		}
+	}
+	summaries := make([]DaySummary, 0, len(days))
+	for _, s := range days {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Date.Before(summaries[j].Date) })
+	return summaries
+}
+
+func co2Distribution(moduleID, name string, values []float64) CO2Distribution {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	dist := CO2Distribution{ModuleID: moduleID, ModuleName: name, Samples: len(sorted), Median: median(sorted),
+		P90: sorted[int(math.Ceil(0.9*float64(len(sorted))))-1], Max: sorted[len(sorted)-1]}
+	for _, band := range CO2Bands {
+		n := 0
+		for _, v := range sorted {
+			if v >= band.Min && v < band.Max {
+				n++
+			}
+		}
+		dist.Bands = append(dist.Bands, CO2BandShare{CO2Band: band, Percent: percentOf(n, len(sorted))})
+	}
+	return dist
+}
+
+// WriteHTMLReport renders the report as a self-contained HTML page without external resources, suitable for
+// attaching to emails.
+func WriteHTMLReport(w io.Writer, report *Report) error {
+	return reportTemplate.Execute(w, report)
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"celsius": func(v *float64) string { return formatNullable(v, "%.1f °C") },
+	"mm":      func(v *float64) string { return formatNullable(v, "%.1f mm") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.bar { display: flex; width: 24em; height: 1em; }
+.good { background: #4caf50; } .fair { background: #cddc39; } .poor { background: #ff9800; } .bad { background: #f44336; }
+.low { color: #f44336; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Begin.Format "2006-01-02 15:04"}} – {{.End.Format "2006-01-02 15:04"}}</p>
+{{range .Stations}}
+<h2>{{.Name}} <small>{{.DeviceID}}</small></h2>
+{{if .Days}}
+<h3>Daily summary</h3>
+<table>
+<tr><th>Date</th><th>Low</th><th>High</th><th>Rain</th></tr>
+{{range .Days}}<tr><td>{{.Date.Format "2006-01-02 Mon"}}</td><td>{{celsius .Low}}</td><td>{{celsius .High}}</td><td>{{mm .Rain}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .CO2}}
+<h3>CO2</h3>
+<table>
+<tr><th>Module</th><th>Samples</th><th>Median</th><th>90 %</th><th>Max</th><th>Distribution</th></tr>
+{{range .CO2}}<tr><td>{{.ModuleName}}</td><td>{{.Samples}}</td><td>{{printf "%.0f" .Median}} ppm</td><td>{{printf "%.0f" .P90}} ppm</td><td>{{printf "%.0f" .Max}} ppm</td>
+<td><div class="bar">{{range .Bands}}<div class="{{.Label}}" style="width: {{printf "%.1f" .Percent}}%" title="{{.Label}} {{printf "%.1f" .Percent}} %"></div>{{end}}</div></td></tr>
+{{end}}</table>
+{{end}}
+{{if .Batteries}}
+<h3>Batteries</h3>
+<table>
+<tr><th>Module</th><th>Battery</th><th>Reachable</th></tr>
+{{range .Batteries}}<tr><td>{{.ModuleName}}</td><td{{if .Low}} class="low"{{end}}>{{.Percent}} %</td><td>{{.Reachable}}</td></tr>
+{{end}}</table>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+func formatNullable(v *float64, format string) string {
+	if v == nil {
+		return "–"
+	}
+	return fmt.Sprintf(format, *v)
+}
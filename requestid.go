@@ -0,0 +1,69 @@
+package netatmo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// DefaultRequestIDHeader defines header carrying request IDs.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns context carrying the request ID, used instead of a generated one by requests made with
+// the context.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by the context. Contexts of API requests carry their ID, so
+// custom transports can log it.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithRequestID attaches an ID to each API request in the header (DefaultRequestIDHeader if empty). IDs are taken from
+// the request context (see ContextWithRequestID), or made by the generator (random 16 byte hex if nil).
+func WithRequestID(header string, generator func() string) Option {
+	return func(o *clientOptions) {
+		if header == "" {
+			header = DefaultRequestIDHeader
+		}
+		if generator == nil {
+			generator = NewRequestID
+		}
+		o.requestIDHeader, o.newRequestID = header, generator
+	}
+}
+
+// NewRequestID generates a random request ID.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// RequestError defines failure of an API request.
+type RequestError struct {
+	Endpoint  string
+	RequestID string // Empty unless WithRequestID is used
+	Err       error
+}
+
+// Error returns the message with the request ID.
+func (e *RequestError) Error() string {
+	if e.RequestID == "" {
+		return fmt.Sprintf("%s: %v", e.Endpoint, e.Err)
+	}
+	return fmt.Sprintf("%s (request id %s): %v", e.Endpoint, e.RequestID, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,65 @@
+package netatmo
+
+import (
+	"context"
+	"time"
+)
+
+// RetentionPolicy defines how long stored data is kept. Zero duration keeps the data forever.
+type RetentionPolicy struct {
+	Raw    time.Duration // Maximum age of raw measures (ex. 90 days)
+	Hourly time.Duration // Maximum age of hourly aggregates (ex. 5 years)
+	Daily  time.Duration // Maximum age of daily aggregates
+}
+
+// RetentionResult defines number of records deleted by a retention run.
+type RetentionResult struct {
+	Raw    int
+	Hourly int
+	Daily  int
+}
+
+// ApplyRetention deletes data older than the policy allows, relative to the specified time.
+func ApplyRetention(ctx context.Context, store Store, policy RetentionPolicy, now time.Time) (*RetentionResult, error) {
+	result := &RetentionResult{}
+	if policy.Raw > 0 {
+		deleted, err := store.DeleteMeasures(ctx, MeasureFilter{End: now.Add(-policy.Raw).Unix() - 1})
+		if err != nil {
+			return result, err
+		}
+		result.Raw = deleted
+	}
+	if policy.Hourly > 0 {
+		deleted, err := store.DeleteAggregates(ctx, ResolutionHourly, MeasureFilter{End: now.Add(-policy.Hourly).Unix() - 1})
+		if err != nil {
+			return result, err
+		}
+		result.Hourly = deleted
+	}
+	if policy.Daily > 0 {
+		deleted, err := store.DeleteAggregates(ctx, ResolutionDaily, MeasureFilter{End: now.Add(-policy.Daily).Unix() - 1})
+		if err != nil {
+			return result, err
+		}
+		result.Daily = deleted
+	}
+	return result, nil
+}
+
+// RunRetention applies the policy immediately and then every interval until the context is done. Failed runs are
+// reported to onError (if not nil) and retried on the next tick. It blocks, so start it with a go statement.
+func RunRetention(ctx context.Context, store Store, policy RetentionPolicy, interval time.Duration, onError func(error)) error {
+	clock := ClockFromContext(ctx)
+	ticker := clock.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := ApplyRetention(ctx, store, policy, clock.Now()); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
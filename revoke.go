@@ -0,0 +1,116 @@
+package netatmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// ErrTokenRevoked is wrapped by errors of API calls made after Revoke.
+var ErrTokenRevoked = errors.New("token is revoked")
+
+// Revoke logs out: it invalidates the refresh token at the RevokeURL of the endpoint, if set, then discards the token
+// held by the client and deletes the saved one from a token store implementing Delete(ctx) error (ex.
+// FileTokenStore). Further API calls fail with ErrTokenRevoked. The token is discarded even if revocation fails.
+func (c *Client) Revoke(ctx context.Context) error {
+	source, ok := c.client.Transport.(*oauth2.Transport).Source.(*revocableTokenSource)
+	if !ok {
+		return errors.New("client has no token source")
+	}
+	token := source.revoke()
+	var errs []error
+	if token != nil && c.options.endpoint.RevokeURL != "" {
+		errs = append(errs, c.revokeToken(ctx, token))
+	}
+	if store, ok := c.options.tokenStore.(interface{ Delete(context.Context) error }); ok {
+		errs = append(errs, store.Delete(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// revokeToken sends a revocation request of the refresh token, or of the access token without refresh token.
+func (c *Client) revokeToken(ctx context.Context, token *oauth2.Token) error {
+	form := url.Values{"token": {token.RefreshToken}, "token_type_hint": {"refresh_token"}}
+	if token.RefreshToken == "" {
+		form = url.Values{"token": {token.AccessToken}, "token_type_hint": {"access_token"}}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.options.endpoint.RevokeURL,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(c.oauth.ClientID), url.QueryEscape(c.oauth.ClientSecret))
+	resp, err := (&http.Client{Transport: c.options.baseTransport}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("revoke: unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// Delete removes the token file, doing nothing if it does not exist.
+func (s *FileTokenStore) Delete(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// revocableTokenSource fails with ErrTokenRevoked once revoked.
+type revocableTokenSource struct {
+	mu     sync.Mutex
+	source oauth2.TokenSource // Nil once revoked
+	last   *oauth2.Token      // Initial or last obtained token
+}
+
+func (s *revocableTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	source := s.source
+	s.mu.Unlock()
+	if source == nil {
+		return nil, ErrTokenRevoked
+	}
+	token, err := source.Token()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.source == nil {
+		return nil, ErrTokenRevoked
+	}
+	s.last = token
+	return token, nil
+}
+
+// current returns the last obtained token without refreshing it, nil if revoked.
+func (s *revocableTokenSource) current() *oauth2.Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// revoke discards the source, returning its current token without refreshing it (nil if none or already revoked).
+func (s *revocableTokenSource) revoke() *oauth2.Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.source == nil {
+		return nil
+	}
+	token := s.last
+	s.source, s.last = nil, nil
+	return token
+}
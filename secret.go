@@ -0,0 +1,265 @@
+package netatmo
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSecretNotFound is returned by secret providers for unknown secret names.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretProvider fetches secrets such as client credentials and refresh tokens from a secret store.
+type SecretProvider interface {
+	// Secret returns the value of the named secret (ex. client_secret), wrapping ErrSecretNotFound if missing.
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// SecretProviderFunc adapts an ordinary function to the SecretProvider interface.
+type SecretProviderFunc func(ctx context.Context, name string) (string, error)
+
+// Secret calls f(ctx, name).
+func (f SecretProviderFunc) Secret(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+// Secret names read by LoadCredentials.
+const (
+	SecretClientID     = "client_id"
+	SecretClientSecret = "client_secret"
+	SecretUsername     = "username"
+	SecretPassword     = "password"
+	SecretRefreshToken = "refresh_token"
+)
+
+// Credentials defines secrets authenticating a client.
+type Credentials struct {
+	ClientID     string
+	ClientSecret string
+	Username     string // Empty if authenticated with the refresh token
+	Password     string
+	RefreshToken string // Empty if authenticated with the password
+}
+
+// LoadCredentials fills empty fields of the credentials from the provider. Missing secrets are left empty, so
+// values of flags or configuration files take precedence and the store only has to hold the rest.
+func LoadCredentials(ctx context.Context, provider SecretProvider, cred *Credentials) error {
+	for name, field := range map[string]*string{
+		SecretClientID:     &cred.ClientID,
+		SecretClientSecret: &cred.ClientSecret,
+		SecretUsername:     &cred.Username,
+		SecretPassword:     &cred.Password,
+		SecretRefreshToken: &cred.RefreshToken,
+	} {
+		if *field != "" {
+			continue
+		}
+		value, err := provider.Secret(ctx, name)
+		if errors.Is(err, ErrSecretNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		*field = value
+	}
+	return nil
+}
+
+// jsonSecrets caches a JSON object of secrets fetched once.
+type jsonSecrets struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (j *jsonSecrets) secret(ctx context.Context, name string,
+	fetch func(ctx context.Context) (map[string]string, error)) (string, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.values == nil {
+		values, err := fetch(ctx)
+		if err != nil {
+			return "", err
+		}
+		j.values = values
+	}
+	value, ok := j.values[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	return value, nil
+}
+
+// VaultSecrets reads secrets from a key/value version 2 secret of HashiCorp Vault, with a key per secret name
+// (ex. vault kv put secret/netatmo client_id=... client_secret=... refresh_token=...). The secret is fetched once
+// on first use.
+type VaultSecrets struct {
+	Addr       string       // Defaults to VAULT_ADDR
+	Token      string       // Defaults to VAULT_TOKEN
+	Namespace  string       // Enterprise namespace, defaults to VAULT_NAMESPACE
+	Mount      string       // Mount path of the secrets engine, defaults to "secret"
+	Path       string       // Secret path (ex. netatmo)
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+	cache      jsonSecrets
+}
+
+// Secret returns the value of the key of the secret.
+func (v *VaultSecrets) Secret(ctx context.Context, name string) (string, error) {
+	return v.cache.secret(ctx, name, v.fetch)
+}
+
+func (v *VaultSecrets) fetch(ctx context.Context) (map[string]string, error) {
+	addr, mount := orEnv(v.Addr, "VAULT_ADDR"), v.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+	endpoint := strings.TrimSuffix(addr, "/") + "/v1/" + strings.Trim(mount, "/") + "/data/" +
+		strings.Trim(v.Path, "/")
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", orEnv(v.Token, "VAULT_TOKEN"))
+	if namespace := orEnv(v.Namespace, "VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	var resp struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := secretRequest(ctx, v.HTTPClient, req, &resp); err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+	return resp.Data.Data, nil
+}
+
+// AWSSecrets reads secrets from a secret of AWS Secrets Manager holding a JSON object with a key per secret name
+// (ex. {"client_id": "...", "client_secret": "...", "refresh_token": "..."}). The secret is fetched once on first
+// use. Credentials default to the standard environment variables; use a SecretProviderFunc around the AWS SDK for
+// other credential sources.
+type AWSSecrets struct {
+	Region          string // Defaults to AWS_REGION
+	SecretID        string // Name or ARN of the secret
+	AccessKeyID     string // Defaults to AWS_ACCESS_KEY_ID
+	SecretAccessKey string // Defaults to AWS_SECRET_ACCESS_KEY
+	SessionToken    string // Defaults to AWS_SESSION_TOKEN
+	HTTPClient      *http.Client
+	cache           jsonSecrets
+}
+
+// Secret returns the value of the key of the secret.
+func (a *AWSSecrets) Secret(ctx context.Context, name string) (string, error) {
+	return a.cache.secret(ctx, name, a.fetch)
+}
+
+func (a *AWSSecrets) fetch(ctx context.Context) (map[string]string, error) {
+	region := orEnv(a.Region, "AWS_REGION")
+	body, err := json.Marshal(map[string]string{"SecretId": a.SecretID})
+	if err != nil {
+		return nil, err
+	}
+	host := "secretsmanager." + region + ".amazonaws.com"
+	req, err := http.NewRequest(http.MethodPost, "https://"+host+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	signAWSRequest(req, body, region, "secretsmanager", orEnv(a.AccessKeyID, "AWS_ACCESS_KEY_ID"),
+		orEnv(a.SecretAccessKey, "AWS_SECRET_ACCESS_KEY"), orEnv(a.SessionToken, "AWS_SESSION_TOKEN"),
+		time.Now().UTC())
+	var resp struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := secretRequest(ctx, a.HTTPClient, req, &resp); err != nil {
+		return nil, fmt.Errorf("aws secrets manager: %w", err)
+	}
+	var values map[string]string
+	if err := json.Unmarshal([]byte(resp.SecretString), &values); err != nil {
+		return nil, fmt.Errorf("aws secrets manager: secret is not a JSON object of strings: %w", err)
+	}
+	return values, nil
+}
+
+// signAWSRequest signs the request with AWS Signature Version 4.
+// Reference: https://docs.aws.amazon.com/IAM/latest/UserGuide/create-signed-request.html
+func signAWSRequest(req *http.Request, body []byte, region, service, accessKeyID, secretAccessKey,
+	sessionToken string, now time.Time) {
+	date, stamp := now.Format("20060102"), now.Format("20060102T150405Z")
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Date", stamp)
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+	headers := []string{"content-type", "host", "x-amz-date", "x-amz-target"}
+	values := map[string]string{"content-type": req.Header.Get("Content-Type"), "host": req.URL.Host,
+		"x-amz-date": stamp, "x-amz-target": req.Header.Get("X-Amz-Target")}
+	if sessionToken != "" {
+		headers = append(headers, "x-amz-security-token")
+		values["x-amz-security-token"] = sessionToken
+		sort.Strings(headers)
+	}
+	var canonicalHeaders strings.Builder
+	for _, h := range headers {
+		canonicalHeaders.WriteString(h + ":" + strings.TrimSpace(values[h]) + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+	canonicalRequest := strings.Join([]string{req.Method, "/", "", canonicalHeaders.String(), signedHeaders,
+		hex.EncodeToString(payloadHash[:])}, "\n")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + stamp + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+	key := []byte("AWS4" + secretAccessKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+hex.EncodeToString(hmacSHA256(key, stringToSign)))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// secretRequest sends the request and decodes the JSON response.
+func secretRequest(ctx context.Context, client *http.Client, req *http.Request, v interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return errors.New(resp.Status + ": " + strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, v)
+}
+
+// orEnv returns the value, or the environment variable if empty.
+func orEnv(value, key string) string {
+	if value != "" {
+		return value
+	}
+	return os.Getenv(key)
+}
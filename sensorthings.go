@@ -0,0 +1,326 @@
+package netatmo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sensorThingsPageSize defines default and maximum number of entities of a SensorThings response.
+const (
+	sensorThingsPageSize    = 100
+	sensorThingsMaxPageSize = 1000
+)
+
+// sensorThingsPath matches supported resource paths (ex. /Things('70:ee:50:00:00:01')/Datastreams).
+var sensorThingsPath = regexp.MustCompile(`^/(Things|Datastreams|Locations)(?:\('((?:[^']|'')*)'\))?(?:/(Datastreams|Observations|Locations|Thing))?$`)
+
+// UnitOfMeasurement defines unit of a SensorThings Datastream.
+type UnitOfMeasurement struct {
+	Name       string `json:"name"`
+	Symbol     string `json:"symbol"`
+	Definition string `json:"definition"`
+}
+
+// datastreamMetric defines a measurement exposed as Datastream.
+type datastreamMetric struct {
+	dataType string // Module data type providing the measurement
+	metric   string // Measure value name
+	unit     UnitOfMeasurement
+}
+
+var datastreamMetrics = []datastreamMetric{
+	{"Temperature", "Temperature", UnitOfMeasurement{"degree Celsius", "°C", "http://unitsofmeasure.org/ucum.html#para-30"}},
+	{"Humidity", "Humidity", UnitOfMeasurement{"percent", "%", "http://unitsofmeasure.org/ucum.html#para-29"}},
+	{"CO2", "CO2", UnitOfMeasurement{"parts per million", "ppm", "http://unitsofmeasure.org/ucum.html#para-29"}},
+	{"Pressure", "Pressure", UnitOfMeasurement{"hectopascal", "hPa", "http://unitsofmeasure.org/ucum.html#para-30"}},
+	{"Noise", "Noise", UnitOfMeasurement{"decibel", "dB", "http://unitsofmeasure.org/ucum.html#section-Levels"}},
+	{"Rain", "Rain", UnitOfMeasurement{"millimetre", "mm", "http://unitsofmeasure.org/ucum.html#para-30"}},
+	{"Wind", "WindStrength", UnitOfMeasurement{"kilometre per hour", "km/h", "http://unitsofmeasure.org/ucum.html#para-30"}},
+	{"Wind", "WindAngle", UnitOfMeasurement{"degree", "°", "http://unitsofmeasure.org/ucum.html#para-30"}},
+	{"Wind", "GustStrength", UnitOfMeasurement{"kilometre per hour", "km/h", "http://unitsofmeasure.org/ucum.html#para-30"}},
+	{"Wind", "GustAngle", UnitOfMeasurement{"degree", "°", "http://unitsofmeasure.org/ucum.html#para-30"}},
+}
+
+// datastream defines a measurement of a module.
+type datastream struct {
+	id     string
+	device *Device
+	module string
+	name   string
+	metric datastreamMetric
+}
+
+// SensorThingsHandler serves devices and measures of a store as read-only OGC SensorThings API v1.1 entities: a Thing
+// and a Location per device, a Datastream per measurement of each module and Observations from stored raw measures.
+// Reference: https://docs.ogc.org/is/18-088/18-088.html
+type SensorThingsHandler struct {
+	store   Store
+	baseURL string
+}
+
+// NewSensorThingsHandler creates a handler serving the store. BaseURL is the public URL the handler is mounted at
+// (ex. https://example.com/v1.1), used for self and navigation links.
+func NewSensorThingsHandler(store Store, baseURL string) *SensorThingsHandler {
+	return &SensorThingsHandler{store: store, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// ServeHTTP serves the request, whose path is relative to the base URL (use http.StripPrefix if mounted at a path).
+func (h *SensorThingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.error(w, http.StatusMethodNotAllowed, "read-only service")
+		return
+	}
+	if r.URL.Path == "/" || r.URL.Path == "" {
+		var value []map[string]string
+		for _, name := range []string{"Things", "Locations", "Datastreams"} {
+			value = append(value, map[string]string{"name": name, "url": h.baseURL + "/" + name})
+		}
+		h.write(w, map[string]interface{}{"value": value})
+		return
+	}
+	match := sensorThingsPath.FindStringSubmatch(r.URL.Path)
+	if match == nil {
+		h.error(w, http.StatusNotFound, "unsupported resource path")
+		return
+	}
+	collection, id, nested := match[1], strings.Replace(match[2], "''", "'", -1), match[3]
+	devices, err := h.store.Devices(r.Context())
+	if err != nil {
+		h.error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	streams := datastreams(devices)
+	switch {
+	case id == "" && nested == "":
+		var entities []map[string]interface{}
+		for i := range devices {
+			switch collection {
+			case "Things":
+				entities = append(entities, h.thing(&devices[i]))
+			case "Locations":
+				entities = append(entities, h.location(&devices[i]))
+			}
+		}
+		if collection == "Datastreams" {
+			for _, s := range streams {
+				entities = append(entities, h.datastream(s))
+			}
+		}
+		h.writeCollection(w, r, entities)
+	case collection == "Datastreams":
+		s := findDatastream(streams, id)
+		if s == nil {
+			h.error(w, http.StatusNotFound, "no such datastream")
+			return
+		}
+		switch nested {
+		case "":
+			h.write(w, h.datastream(*s))
+		case "Thing":
+			h.write(w, h.thing(s.device))
+		case "Observations":
+			h.observations(w, r, *s)
+		default:
+			h.error(w, http.StatusNotFound, "unsupported resource path")
+		}
+	default:
+		device := findDevice(devices, id)
+		if device == nil {
+			h.error(w, http.StatusNotFound, "no such "+strings.TrimSuffix(collection, "s"))
+			return
+		}
+		switch {
+		case nested == "" && collection == "Things":
+			h.write(w, h.thing(device))
+		case nested == "" && collection == "Locations", nested == "Locations" && collection == "Things":
+			if nested == "" {
+				h.write(w, h.location(device))
+			} else {
+				h.writeCollection(w, r, []map[string]interface{}{h.location(device)})
+			}
+		case nested == "Datastreams" && collection == "Things":
+			var entities []map[string]interface{}
+			for _, s := range streams {
+				if s.device.ID == device.ID {
+					entities = append(entities, h.datastream(s))
+				}
+			}
+			h.writeCollection(w, r, entities)
+		default:
+			h.error(w, http.StatusNotFound, "unsupported resource path")
+		}
+	}
+}
+
+func (h *SensorThingsHandler) observations(w http.ResponseWriter, r *http.Request, s datastream) {
+	top, skip := pageOf(r.URL.Query())
+	measures, err := h.store.Measures(r.Context(), MeasureFilter{Account: s.device.Account, DeviceID: s.device.ID,
+		ModuleID: s.module})
+	if err != nil {
+		h.error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// Newest first, as clients usually want the latest observations
+	sort.Slice(measures, func(i, j int) bool { return measures[i].Timestamp > measures[j].Timestamp })
+	var entities []map[string]interface{}
+	for i := range measures {
+		v, ok := measures[i].Value(s.metric.metric)
+		if !ok {
+			continue
+		}
+		id := s.id + "@" + strconv.FormatInt(measures[i].Timestamp, 10)
+		at := time.Unix(measures[i].Timestamp, 0).UTC().Format(time.RFC3339)
+		entities = append(entities, map[string]interface{}{
+			"@iot.id":                       id,
+			"phenomenonTime":                at,
+			"resultTime":                    at,
+			"result":                        v,
+			"Datastream@iot.navigationLink": h.link("Datastreams", s.id),
+		})
+	}
+	h.writePage(w, r, entities, top, skip)
+}
+
+func (h *SensorThingsHandler) thing(d *Device) map[string]interface{} {
+	return map[string]interface{}{
+		"@iot.id":                        d.ID,
+		"@iot.selfLink":                  h.link("Things", d.ID),
+		"name":                           d.StationName,
+		"description":                    "Netatmo weather station " + d.StationName,
+		"properties":                     map[string]interface{}{"type": d.Type, "firmware": d.Firmware, "account": d.Account},
+		"Locations@iot.navigationLink":   h.link("Things", d.ID) + "/Locations",
+		"Datastreams@iot.navigationLink": h.link("Things", d.ID) + "/Datastreams",
+	}
+}
+
+func (h *SensorThingsHandler) location(d *Device) map[string]interface{} {
+	return map[string]interface{}{
+		"@iot.id":       d.ID,
+		"@iot.selfLink": h.link("Locations", d.ID),
+		"name":          d.Place.City,
+		"description":   fmt.Sprintf("%s, %s", d.Place.City, d.Place.Country),
+		"encodingType":  "application/vnd.geo+json",
+		"location": map[string]interface{}{
+			"type":        "Point",
+			"coordinates": []float64{d.Place.Longitude(), d.Place.Latitude(), float64(d.Place.Altitude)},
+		},
+	}
+}
+
+func (h *SensorThingsHandler) datastream(s datastream) map[string]interface{} {
+	return map[string]interface{}{
+		"@iot.id":                         s.id,
+		"@iot.selfLink":                   h.link("Datastreams", s.id),
+		"name":                            s.name + " " + s.metric.metric,
+		"description":                     s.metric.metric + " of " + s.name,
+		"observationType":                 "http://www.opengis.net/def/observationType/OGC-OM/2.0/OM_Measurement",
+		"unitOfMeasurement":               s.metric.unit,
+		"ObservedProperty":                map[string]string{"name": s.metric.metric},
+		"Thing@iot.navigationLink":        h.link("Datastreams", s.id) + "/Thing",
+		"Observations@iot.navigationLink": h.link("Datastreams", s.id) + "/Observations",
+	}
+}
+
+// link returns URL of the entity, quoting the ID.
+func (h *SensorThingsHandler) link(collection, id string) string {
+	return h.baseURL + "/" + collection + "('" + url.PathEscape(strings.Replace(id, "'", "''", -1)) + "')"
+}
+
+func (h *SensorThingsHandler) writeCollection(w http.ResponseWriter, r *http.Request, entities []map[string]interface{}) {
+	top, skip := pageOf(r.URL.Query())
+	h.writePage(w, r, entities, top, skip)
+}
+
+// writePage writes the entities selected by $top and $skip, with a link to the next page if any.
+func (h *SensorThingsHandler) writePage(w http.ResponseWriter, r *http.Request, entities []map[string]interface{},
+	top, skip int) {
+	response := map[string]interface{}{"@iot.count": len(entities)}
+	if skip > len(entities) {
+		skip = len(entities)
+	}
+	end := skip + top
+	if end < len(entities) {
+		query := r.URL.Query()
+		query.Set("$top", strconv.Itoa(top))
+		query.Set("$skip", strconv.Itoa(end))
+		response["@iot.nextLink"] = h.baseURL + r.URL.Path + "?" + query.Encode()
+	} else {
+		end = len(entities)
+	}
+	value := entities[skip:end]
+	if value == nil {
+		value = []map[string]interface{}{}
+	}
+	response["value"] = value
+	h.write(w, response)
+}
+
+func (h *SensorThingsHandler) write(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (h *SensorThingsHandler) error(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": status, "message": message})
+}
+
+// datastreams lists measurements of the base stations and modules of the devices.
+func datastreams(devices []Device) []datastream {
+	var streams []datastream
+	for i := range devices {
+		d := &devices[i]
+		name := d.ModuleName
+		if name == "" {
+			name = d.StationName
+		}
+		streams = appendDatastreams(streams, d, d.ID, name, d.DataTypes)
+		for _, m := range d.Modules {
+			streams = appendDatastreams(streams, d, m.ID, m.ModuleName, m.DataTypes)
+		}
+	}
+	return streams
+}
+
+func appendDatastreams(streams []datastream, d *Device, moduleID, name string, dataTypes []string) []datastream {
+	for _, metric := range datastreamMetrics {
+		if sliceContains(dataTypes, metric.dataType) {
+			streams = append(streams, datastream{id: moduleID + "/" + metric.metric, device: d, module: moduleID,
+				name: name, metric: metric})
+		}
+	}
+	return streams
+}
+
+func findDatastream(streams []datastream, id string) *datastream {
+	for i := range streams {
+		if streams[i].id == id {
+			return &streams[i]
+		}
+	}
+	return nil
+}
+
+// pageOf parses $top and $skip query options.
+func pageOf(query url.Values) (int, int) {
+	top, err := strconv.Atoi(query.Get("$top"))
+	if err != nil || top <= 0 {
+		top = sensorThingsPageSize
+	}
+	if top > sensorThingsMaxPageSize {
+		top = sensorThingsMaxPageSize
+	}
+	skip, err := strconv.Atoi(query.Get("$skip"))
+	if err != nil || skip < 0 {
+		skip = 0
+	}
+	return top, skip
+}
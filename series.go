@@ -0,0 +1,203 @@
+package netatmo
+
+import (
+	"math"
+	"time"
+)
+
+// TemperatureSeries defines temperatures in °C ordered by timestamp.
+type TemperatureSeries []Point
+
+// NewTemperatureSeries extracts temperatures of the measures.
+func NewTemperatureSeries(measures []Measure) TemperatureSeries {
+	return SeriesOf[Temperature](measures)
+}
+
+// Min returns the coldest sample, the first one on ties. The second result is false if the series is empty.
+func (s TemperatureSeries) Min() (Point, bool) {
+	return extremePoint(s, func(a, b float64) bool { return a < b })
+}
+
+// Max returns the warmest sample, the first one on ties. The second result is false if the series is empty.
+func (s TemperatureSeries) Max() (Point, bool) {
+	return extremePoint(s, func(a, b float64) bool { return a > b })
+}
+
+// Mean returns the mean temperature in °C, NaN if the series is empty.
+func (s TemperatureSeries) Mean() float64 {
+	return meanOfPoints(s)
+}
+
+// Fahrenheit returns the series converted to °F.
+func (s TemperatureSeries) Fahrenheit() []Point {
+	return convertPoints(s, celsiusToFahrenheit)
+}
+
+// RainSeries defines rain amounts in mm of consecutive steps ordered by timestamp (sum_rain of scales other than
+// ScaleMax, rain of raw samples).
+type RainSeries []Point
+
+// NewRainSeries extracts rain amounts of the measures.
+func NewRainSeries(measures []Measure) RainSeries {
+	var series RainSeries
+	for i := range measures {
+		if v := measures[i].rainAmount(); v != nil {
+			series = append(series, Point{Timestamp: measures[i].Timestamp, Value: *v})
+		}
+	}
+	return series
+}
+
+// Sum returns total rain in mm.
+func (s RainSeries) Sum() float64 {
+	sum := 0.0
+	for _, p := range s {
+		sum += p.Value
+	}
+	return sum
+}
+
+// SumInches returns total rain in inches.
+func (s RainSeries) SumInches() float64 {
+	return mmToInch(s.Sum())
+}
+
+// Max returns the wettest step, the first one on ties. The second result is false if the series is empty.
+func (s RainSeries) Max() (Point, bool) {
+	return extremePoint(s, func(a, b float64) bool { return a > b })
+}
+
+// WetSteps returns the number of steps with rain.
+func (s RainSeries) WetSteps() int {
+	n := 0
+	for _, p := range s {
+		if p.Value > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// WindSample defines a sample of an anemometer.
+type WindSample struct {
+	Timestamp    int64
+	Strength     *int // Nullable, km/h
+	Angle        *int // Nullable, degrees
+	GustStrength *int // Nullable, km/h
+	GustAngle    *int // Nullable, degrees
+}
+
+// WindSeries defines anemometer samples ordered by timestamp.
+type WindSeries []WindSample
+
+// NewWindSeries extracts wind and gust values of the measures, skipping measures without any of them.
+func NewWindSeries(measures []Measure) WindSeries {
+	var s WindSeries
+	for _, m := range measures {
+		if m.WindStrength == nil && m.WindAngle == nil && m.GustStrength == nil && m.GustAngle == nil {
+			continue
+		}
+		s = append(s, WindSample{Timestamp: m.Timestamp, Strength: m.WindStrength, Angle: m.WindAngle,
+			GustStrength: m.GustStrength, GustAngle: m.GustAngle})
+	}
+	return s
+}
+
+// MeanStrength returns the mean wind strength in km/h, NaN if no sample has a strength.
+func (s WindSeries) MeanStrength() float64 {
+	var values []float64
+	for _, w := range s {
+		if w.Strength != nil {
+			values = append(values, float64(*w.Strength))
+		}
+	}
+	return mean(values)
+}
+
+// MaxGust returns the strongest gust, nil if no sample has a gust strength.
+func (s WindSeries) MaxGust() *Gust {
+	var gust *Gust
+	for _, w := range s {
+		if w.GustStrength != nil && (gust == nil || *w.GustStrength > gust.Strength) {
+			gust = &Gust{Timestamp: w.Timestamp, Strength: *w.GustStrength, Angle: w.GustAngle}
+		}
+	}
+	return gust
+}
+
+// MeanDirection returns the prevailing direction in degrees (0 to 360), the vector mean of directions weighted by
+// strength, since the arithmetic mean of angles is wrong around north (ex. 350 and 10 average to 0, not 180). Samples
+// without strength count with weight 1 when no sample has wind. The second result is false if no direction is known
+// or the directions cancel out.
+func (s WindSeries) MeanDirection() (float64, bool) {
+	var x, y float64
+	weighted := false
+	for _, w := range s {
+		if w.Angle != nil && w.Strength != nil && *w.Strength > 0 {
+			weighted = true
+			break
+		}
+	}
+	for _, w := range s {
+		if w.Angle == nil {
+			continue
+		}
+		weight := 1.0
+		if weighted {
+			if w.Strength == nil {
+				continue
+			}
+			weight = float64(*w.Strength)
+		}
+		rad := float64(*w.Angle) * math.Pi / 180
+		x += weight * math.Sin(rad)
+		y += weight * math.Cos(rad)
+	}
+	if math.Hypot(x, y) < 1e-9 {
+		return 0, false
+	}
+	return math.Mod(math.Atan2(x, y)*180/math.Pi+360, 360), true
+}
+
+// Run returns the distance in km the wind travelled (see WindRun).
+func (s WindSeries) Run() float64 {
+	run := 0.0
+	for i := 0; i+1 < len(s); i++ {
+		step := time.Duration(s[i+1].Timestamp-s[i].Timestamp) * time.Second
+		if s[i].Strength == nil || step <= 0 || step > maxWindRunStep {
+			continue
+		}
+		run += float64(*s[i].Strength) * step.Hours()
+	}
+	return run
+}
+
+// extremePoint returns the first point whose value is better than all others.
+func extremePoint(points []Point, better func(a, b float64) bool) (Point, bool) {
+	if len(points) == 0 {
+		return Point{}, false
+	}
+	best := points[0]
+	for _, p := range points[1:] {
+		if better(p.Value, best.Value) {
+			best = p
+		}
+	}
+	return best, true
+}
+
+func meanOfPoints(points []Point) float64 {
+	values := make([]float64, len(points))
+	for i, p := range points {
+		values[i] = p.Value
+	}
+	return mean(values)
+}
+
+func convertPoints(points []Point, convert func(float64) float64) []Point {
+	converted := make([]Point, len(points))
+	for i, p := range points {
+		converted[i] = Point{Timestamp: p.Timestamp, Value: convert(p.Value)}
+	}
+	return converted
+}
@@ -0,0 +1,194 @@
+package netatmo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Sink defines destination of gathered measures.
+type Sink interface {
+	// Write stores or forwards the measures. Implementations must not retain the slice after returning.
+	Write(ctx context.Context, measures []Measure) error
+}
+
+// SinkFunc adapts an ordinary function to the Sink interface.
+type SinkFunc func(ctx context.Context, measures []Measure) error
+
+// Write calls f(ctx, measures).
+func (f SinkFunc) Write(ctx context.Context, measures []Measure) error {
+	return f(ctx, measures)
+}
+
+// MultiSink writes measures to each sink, returning the first error after trying all of them.
+type MultiSink []Sink
+
+// Write writes the measures to each sink.
+func (m MultiSink) Write(ctx context.Context, measures []Measure) error {
+	var first error
+	for _, s := range m {
+		if err := s.Write(ctx, measures); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// sinkValue defines a measurement value written by sinks.
+type sinkValue struct {
+	Name  string // Measurement name (ex. Temperature, min_temp)
+	Value float64
+}
+
+// sinkValues returns non-null values of target, aggregate and derived measurements (except times) of the measure in
+// the order of TargetMeasurements, AggregateMeasurements and DerivedMeasurements.
+func sinkValues(m *Measure) []sinkValue {
+	var values []sinkValue
+	for _, names := range [][]string{TargetMeasurements, AggregateMeasurements, DerivedMeasurements} {
+		for _, name := range names {
+			if name == "date_max_gust" {
+				continue
+			}
+			if v, ok := m.Value(name); ok {
+				values = append(values, sinkValue{Name: name, Value: v})
+			}
+		}
+	}
+	return values
+}
+
+// sinkPost sends the body with POST and returns the response body, failing on non-2xx status. The request is
+// passed to authorize (if not nil) to set credentials.
+func sinkPost(ctx context.Context, client *http.Client, endpoint, contentType string, body io.Reader,
+	authorize func(req *http.Request)) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if authorize != nil {
+		authorize(req)
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, errors.New(resp.Status + ": " + strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// measureKey identifies a single sample of a module.
+type measureKey struct {
+	Account   string
+	DeviceID  string
+	ModuleID  string
+	Timestamp int64
+}
+
+func keyOf(m *Measure) measureKey {
+	return measureKey{Account: m.Account, DeviceID: m.DeviceID, ModuleID: m.ModuleID, Timestamp: m.Timestamp}
+}
+
+// DedupStats defines deduplication statistics of DedupSink.
+type DedupStats struct {
+	Received   int64 // Number of measures passed to Write
+	Written    int64 // Number of measures forwarded to the underlying sink
+	Duplicates int64 // Number of measures dropped because the same module and timestamp was already written
+	Tracked    int   // Number of keys currently remembered
+}
+
+// DedupSink forwards measures to the underlying sink, dropping measures whose (account, device, module, timestamp)
+// was already written. Overlapping fetch windows and restarted pollers can therefore feed the same samples
+// repeatedly without creating duplicate rows downstream.
+type DedupSink struct {
+	sink  Sink
+	mu    sync.Mutex
+	seen  map[measureKey]struct{}
+	stats DedupStats
+}
+
+// NewDedupSink creates deduplicating sink in front of the specified sink.
+func NewDedupSink(sink Sink) *DedupSink {
+	return &DedupSink{
+		sink: sink,
+		seen: make(map[measureKey]struct{}),
+	}
+}
+
+// Write forwards measures not seen before. Measures are remembered only when the underlying sink succeeds, so a
+// failed write can be retried with the same data.
+func (d *DedupSink) Write(ctx context.Context, measures []Measure) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var fresh []Measure
+	batch := make(map[measureKey]struct{})
+	for i := range measures {
+		k := keyOf(&measures[i])
+		if _, ok := d.seen[k]; ok {
+			d.stats.Duplicates++
+			continue
+		}
+		if _, ok := batch[k]; ok {
+			d.stats.Duplicates++
+			continue
+		}
+		batch[k] = struct{}{}
+		fresh = append(fresh, measures[i])
+	}
+	d.stats.Received += int64(len(measures))
+	if len(fresh) == 0 {
+		return nil
+	}
+	if err := d.sink.Write(ctx, fresh); err != nil {
+		return err
+	}
+	for k := range batch {
+		d.seen[k] = struct{}{}
+	}
+	d.stats.Written += int64(len(fresh))
+	return nil
+}
+
+// Seed remembers measures as already written without forwarding them, e.g. the newest rows loaded from storage
+// after restart.
+func (d *DedupSink) Seed(measures []Measure) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := range measures {
+		d.seen[keyOf(&measures[i])] = struct{}{}
+	}
+}
+
+// Forget drops remembered keys older than the specified unix timestamp to bound memory usage.
+func (d *DedupSink) Forget(before int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k := range d.seen {
+		if k.Timestamp < before {
+			delete(d.seen, k)
+		}
+	}
+}
+
+// Stats returns snapshot of the deduplication statistics.
+func (d *DedupSink) Stats() DedupStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	stats := d.stats
+	stats.Tracked = len(d.seen)
+	return stats
+}
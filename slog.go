@@ -0,0 +1,38 @@
+package netatmo
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithLogger logs each API request at debug level (endpoint, duration, HTTP status, request ID and error) and
+// requests delayed by the rate limiter, with the attributes of the logger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *clientOptions) {
+		o.logger = logger
+	}
+}
+
+// logRequest logs an API request if a logger is set.
+func (c *Client) logRequest(ctx context.Context, endpoint, requestID string, d time.Duration, status int, err error) {
+	logger := c.options.logger
+	if logger == nil || !logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []slog.Attr{slog.String("endpoint", endpoint), slog.Duration("duration", d), slog.Int("status", status)}
+	if requestID != "" {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	logger.LogAttrs(ctx, slog.LevelDebug, "netatmo api request", attrs...)
+}
+
+// logRateLimitWait logs a request delayed by the rate limiter if a logger is set.
+func (c *Client) logRateLimitWait(ctx context.Context, wait time.Duration) {
+	if logger := c.options.logger; logger != nil {
+		logger.LogAttrs(ctx, slog.LevelDebug, "netatmo rate limit wait", slog.Duration("wait", wait))
+	}
+}
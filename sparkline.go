@@ -0,0 +1,101 @@
+package netatmo
+
+import (
+	"math"
+	"strings"
+)
+
+// sparkBlocks defines block characters of sparklines from lowest to highest.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the values as a line of block characters scaled between their minimum and maximum
+// (ex. "▁▂▄▆█▆▄"). NaN values are rendered as spaces.
+func Sparkline(values []float64) string {
+	low, high := finiteRange(values)
+	var b strings.Builder
+	for _, v := range values {
+		if math.IsNaN(v) {
+			b.WriteRune(' ')
+			continue
+		}
+		level := 0
+		if high > low {
+			level = int(math.Round((v - low) / (high - low) * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// SparklineOf renders the values of the points resampled to at most width characters by averaging (see Sparkline).
+func SparklineOf(points []Point, width int) string {
+	return Sparkline(resample(points, width))
+}
+
+// brailleDots defines bits of the braille dots of a character cell by column and row from the top.
+var brailleDots = [2][4]rune{{0x01, 0x02, 0x04, 0x40}, {0x08, 0x10, 0x20, 0x80}}
+
+// BrailleChart renders the values of the points as a line chart of braille characters, width characters wide and
+// height lines high. Each character holds 2x4 dots, so the chart resolves width*2 samples of height*4 levels.
+func BrailleChart(points []Point, width, height int) []string {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	values := resample(points, width*2)
+	low, high := finiteRange(values)
+	cells := make([][]rune, height)
+	for i := range cells {
+		cells[i] = make([]rune, width)
+	}
+	rows := height * 4
+	for x, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		level := 0
+		if high > low {
+			level = int(math.Round((v - low) / (high - low) * float64(rows-1)))
+		}
+		y := rows - 1 - level
+		cells[y/4][x/2] |= brailleDots[x%2][y%4]
+	}
+	lines := make([]string, height)
+	for i, row := range cells {
+		for j := range row {
+			row[j] += 0x2800
+		}
+		lines[i] = string(row)
+	}
+	return lines
+}
+
+// resample averages values of the points into at most n buckets of equal count, NaN for none.
+func resample(points []Point, n int) []float64 {
+	if n <= 0 || len(points) == 0 {
+		return nil
+	}
+	if len(points) <= n {
+		values := make([]float64, len(points))
+		for i, p := range points {
+			values[i] = p.Value
+		}
+		return values
+	}
+	values := make([]float64, n)
+	for i := range values {
+		from, to := i*len(points)/n, (i+1)*len(points)/n
+		values[i] = meanOfPoints(points[from:to])
+	}
+	return values
+}
+
+// finiteRange returns minimum and maximum of non-NaN values.
+func finiteRange(values []float64) (float64, float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			low, high = math.Min(low, v), math.Max(high, v)
+		}
+	}
+	return low, high
+}
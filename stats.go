@@ -0,0 +1,103 @@
+package netatmo
+
+import (
+	"encoding/json"
+	"expvar"
+	"strconv"
+	"sync"
+)
+
+// Error classes counted by ClientStats.
+const (
+	ErrorClassTransport = "transport" // Request could not be sent or response could not be read
+	ErrorClassClient    = "4xx"       // API rejected the request
+	ErrorClassServer    = "5xx"       // API failed
+)
+
+// ClientStats defines snapshot of client activity counters.
+type ClientStats struct {
+	Requests       map[string]int64 `json:"requests"` // Keyed by endpoint
+	Errors         map[string]int64 `json:"errors"`   // Keyed by error class
+	RateLimitWaits int64            `json:"rate_limit_waits"`
+	CacheHits      int64            `json:"cache_hits"`
+	CacheMisses    int64            `json:"cache_misses"`
+}
+
+// clientStats holds counters of a client.
+type clientStats struct {
+	mu    sync.Mutex
+	stats ClientStats
+}
+
+func newClientStats() *clientStats {
+	return &clientStats{stats: ClientStats{Requests: make(map[string]int64), Errors: make(map[string]int64)}}
+}
+
+// request counts a request to the endpoint with the response status (0 if no response) and transport error.
+func (s *clientStats) request(endpoint string, status int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.Requests[endpoint]++
+	switch {
+	case err != nil:
+		s.stats.Errors[ErrorClassTransport]++
+	case status >= 500:
+		s.stats.Errors[ErrorClassServer]++
+	case status >= 400:
+		s.stats.Errors[ErrorClassClient]++
+	}
+}
+
+// rateLimitWait counts a request delayed by the rate limiter.
+func (s *clientStats) rateLimitWait() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.RateLimitWaits++
+}
+
+// cache counts a lookup of cached data.
+func (s *clientStats) cache(hit bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if hit {
+		s.stats.CacheHits++
+	} else {
+		s.stats.CacheMisses++
+	}
+}
+
+func (s *clientStats) snapshot() ClientStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot := s.stats
+	snapshot.Requests = copyCounters(s.stats.Requests)
+	snapshot.Errors = copyCounters(s.stats.Errors)
+	return snapshot
+}
+
+func copyCounters(m map[string]int64) map[string]int64 {
+	c := make(map[string]int64, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// Stats returns snapshot of the client activity counters.
+func (c *Client) Stats() ClientStats {
+	return c.stats.snapshot()
+}
+
+// Var returns the counters as expvar variable (ex. expvar.Publish("netatmo", client.Var())).
+func (c *Client) Var() expvar.Var {
+	return expvar.Func(func() interface{} { return c.Stats() })
+}
+
+// String returns the counters as JSON, so ClientStats can be published as expvar.Var too.
+func (s ClientStats) String() string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return strconv.Quote(err.Error())
+	}
+	return string(b)
+}
@@ -0,0 +1,306 @@
+package netatmo
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MeasureFilter defines conditions to select stored measures. Zero value fields match everything.
+type MeasureFilter struct {
+	Account  string
+	DeviceID string
+	ModuleID string
+	Begin    int64 // Inclusive unix time, 0 means unbounded
+	End      int64 // Inclusive unix time, 0 means unbounded
+}
+
+// Match reports whether the measure satisfies the filter.
+func (f *MeasureFilter) Match(m *Measure) bool {
+	if f.Account != "" && f.Account != m.Account {
+		return false
+	}
+	if f.DeviceID != "" && f.DeviceID != m.DeviceID {
+		return false
+	}
+	if f.ModuleID != "" && f.ModuleID != m.ModuleID {
+		return false
+	}
+	if f.Begin != 0 && m.Timestamp < f.Begin {
+		return false
+	}
+	if f.End != 0 && m.Timestamp > f.End {
+		return false
+	}
+	return true
+}
+
+// Store defines storage of station metadata and measure history.
+type Store interface {
+	// Write inserts measures, replacing existing ones with the same account, device, module and timestamp.
+	Write(ctx context.Context, measures []Measure) error
+
+	// Measures returns measures matching the filter, ordered by account, device, module and timestamp.
+	Measures(ctx context.Context, filter MeasureFilter) ([]Measure, error)
+
+	// NewestMeasure returns the newest measure matching the filter, nil if none, without reading the others.
+	NewestMeasure(ctx context.Context, filter MeasureFilter) (*Measure, error)
+
+	// DeleteMeasures deletes measures matching the filter and returns number of deleted measures.
+	DeleteMeasures(ctx context.Context, filter MeasureFilter) (int, error)
+
+	// WriteAggregates inserts aggregates, replacing existing ones with the same account, device, module,
+	// resolution and timestamp.
+	WriteAggregates(ctx context.Context, aggregates []Aggregate) error
+
+	// Aggregates returns aggregates of the resolution matching the filter, ordered by account, device, module and
+	// timestamp.
+	Aggregates(ctx context.Context, res Resolution, filter MeasureFilter) ([]Aggregate, error)
+
+	// DeleteAggregates deletes aggregates of the resolution matching the filter and returns number of deleted
+	// aggregates.
+	DeleteAggregates(ctx context.Context, res Resolution, filter MeasureFilter) (int, error)
+
+	// PutDevices inserts devices, replacing existing ones with the same account and ID.
+	PutDevices(ctx context.Context, devices []Device) error
+
+	// Devices returns all stored devices ordered by account and ID.
+	Devices(ctx context.Context) ([]Device, error)
+}
+
+type seriesKey struct {
+	Account  string
+	DeviceID string
+	ModuleID string
+}
+
+type aggregateKey struct {
+	Account    string
+	DeviceID   string
+	ModuleID   string
+	Resolution Resolution
+	Timestamp  int64
+}
+
+// MemoryStore implements Store on process memory.
+type MemoryStore struct {
+	mu         sync.RWMutex
+	series     map[seriesKey][]Measure // Each series is sorted by timestamp
+	aggregates map[aggregateKey]Aggregate
+	devices    map[deviceKey]Device
+}
+
+type deviceKey struct {
+	Account string
+	ID      string
+}
+
+// NewMemoryStore creates empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		series:     make(map[seriesKey][]Measure),
+		aggregates: make(map[aggregateKey]Aggregate),
+		devices:    make(map[deviceKey]Device),
+	}
+}
+
+// Write inserts measures, replacing existing ones with the same account, device, module and timestamp.
+func (s *MemoryStore) Write(_ context.Context, measures []Measure) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range measures {
+		k := seriesKey{Account: m.Account, DeviceID: m.DeviceID, ModuleID: m.ModuleID}
+		series := s.series[k]
+		i := sort.Search(len(series), func(i int) bool { return series[i].Timestamp >= m.Timestamp })
+		if i < len(series) && series[i].Timestamp == m.Timestamp {
+			series[i] = m
+			continue
+		}
+		series = append(series, Measure{})
+		copy(series[i+1:], series[i:])
+		series[i] = m
+		s.series[k] = series
+	}
+	return nil
+}
+
+// Measures returns measures matching the filter, ordered by account, device, module and timestamp.
+func (s *MemoryStore) Measures(_ context.Context, filter MeasureFilter) ([]Measure, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var measures []Measure
+	for _, k := range s.sortedKeys() {
+		if filter.Account != "" && filter.Account != k.Account {
+			continue
+		}
+		if filter.DeviceID != "" && filter.DeviceID != k.DeviceID {
+			continue
+		}
+		if filter.ModuleID != "" && filter.ModuleID != k.ModuleID {
+			continue
+		}
+		series := s.series[k]
+		i := sort.Search(len(series), func(i int) bool { return series[i].Timestamp >= filter.Begin })
+		for ; i < len(series); i++ {
+			if filter.End != 0 && series[i].Timestamp > filter.End {
+				break
+			}
+			measures = append(measures, series[i])
+		}
+	}
+	return measures, nil
+}
+
+// NewestMeasure returns the newest measure matching the filter, nil if none.
+func (s *MemoryStore) NewestMeasure(_ context.Context, filter MeasureFilter) (*Measure, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var newest *Measure
+	for _, k := range s.sortedKeys() {
+		if filter.Account != "" && filter.Account != k.Account {
+			continue
+		}
+		if filter.DeviceID != "" && filter.DeviceID != k.DeviceID {
+			continue
+		}
+		if filter.ModuleID != "" && filter.ModuleID != k.ModuleID {
+			continue
+		}
+		series := s.series[k]
+		i := len(series)
+		if filter.End != 0 {
+			i = sort.Search(len(series), func(i int) bool { return series[i].Timestamp > filter.End })
+		}
+		if i == 0 || series[i-1].Timestamp < filter.Begin {
+			continue
+		}
+		if newest == nil || series[i-1].Timestamp > newest.Timestamp {
+			m := series[i-1]
+			newest = &m
+		}
+	}
+	return newest, nil
+}
+
+// DeleteMeasures deletes measures matching the filter and returns number of deleted measures.
+func (s *MemoryStore) DeleteMeasures(_ context.Context, filter MeasureFilter) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := 0
+	for k, series := range s.series {
+		kept := series[:0]
+		for i := range series {
+			if filter.Match(&series[i]) {
+				deleted++
+				continue
+			}
+			kept = append(kept, series[i])
+		}
+		if len(kept) == 0 {
+			delete(s.series, k)
+		} else {
+			s.series[k] = kept
+		}
+	}
+	return deleted, nil
+}
+
+// WriteAggregates inserts aggregates, replacing existing ones with the same account, device, module,
+// resolution and timestamp.
+func (s *MemoryStore) WriteAggregates(_ context.Context, aggregates []Aggregate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range aggregates {
+		k := aggregateKey{Account: a.Account, DeviceID: a.DeviceID, ModuleID: a.ModuleID, Resolution: a.Resolution,
+			Timestamp: a.Timestamp}
+		s.aggregates[k] = a
+	}
+	return nil
+}
+
+// Aggregates returns aggregates of the resolution matching the filter, ordered by account, device, module and
+// timestamp.
+func (s *MemoryStore) Aggregates(_ context.Context, res Resolution, filter MeasureFilter) ([]Aggregate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var aggregates []Aggregate
+	for k, a := range s.aggregates {
+		if k.Resolution == res && filter.Match(&Measure{Account: k.Account, DeviceID: k.DeviceID, ModuleID: k.ModuleID,
+			Timestamp: k.Timestamp}) {
+			aggregates = append(aggregates, a)
+		}
+	}
+	sort.Slice(aggregates, func(i, j int) bool {
+		if aggregates[i].Account != aggregates[j].Account {
+			return aggregates[i].Account < aggregates[j].Account
+		}
+		if aggregates[i].DeviceID != aggregates[j].DeviceID {
+			return aggregates[i].DeviceID < aggregates[j].DeviceID
+		}
+		if aggregates[i].ModuleID != aggregates[j].ModuleID {
+			return aggregates[i].ModuleID < aggregates[j].ModuleID
+		}
+		return aggregates[i].Timestamp < aggregates[j].Timestamp
+	})
+	return aggregates, nil
+}
+
+// DeleteAggregates deletes aggregates of the resolution matching the filter and returns number of deleted
+// aggregates.
+func (s *MemoryStore) DeleteAggregates(_ context.Context, res Resolution, filter MeasureFilter) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := 0
+	for k := range s.aggregates {
+		if k.Resolution == res && filter.Match(&Measure{Account: k.Account, DeviceID: k.DeviceID, ModuleID: k.ModuleID,
+			Timestamp: k.Timestamp}) {
+			delete(s.aggregates, k)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// PutDevices inserts devices, replacing existing ones with the same account and ID.
+func (s *MemoryStore) PutDevices(_ context.Context, devices []Device) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range devices {
+		s.devices[deviceKey{Account: d.Account, ID: d.ID}] = d
+	}
+	return nil
+}
+
+// Devices returns all stored devices ordered by account and ID.
+func (s *MemoryStore) Devices(_ context.Context) ([]Device, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	devices := make([]Device, 0, len(s.devices))
+	for _, d := range s.devices {
+		devices = append(devices, d)
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Account != devices[j].Account {
+			return devices[i].Account < devices[j].Account
+		}
+		return devices[i].ID < devices[j].ID
+	})
+	return devices, nil
+}
+
+func (s *MemoryStore) sortedKeys() []seriesKey {
+	keys := make([]seriesKey, 0, len(s.series))
+	for k := range s.series {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Account != keys[j].Account {
+			return keys[i].Account < keys[j].Account
+		}
+		if keys[i].DeviceID != keys[j].DeviceID {
+			return keys[i].DeviceID < keys[j].DeviceID
+		}
+		return keys[i].ModuleID < keys[j].ModuleID
+	})
+	return keys
+}
@@ -0,0 +1,110 @@
+package netatmo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultSyncLookback defines how far back the first sync of a module reaches.
+const defaultSyncLookback = 24 * time.Hour
+
+// Source defines an account to gather data from.
+type Source struct {
+	Account string  // Namespace stamped on devices and measures (ex. "home", "office")
+	Client  *Client // Authenticated client of the account
+}
+
+// SyncResult defines outcome of a sync run.
+type SyncResult struct {
+	Devices  int // Number of devices stored
+	Measures int // Number of measures written
+}
+
+// Syncer gathers station data and measures from one or more accounts into a Store. Devices and measures are
+// stamped with the account of their source, so data of several logins can be queried and exported uniformly.
+type Syncer struct {
+	Sources  []Source
+	Store    Store
+	Lookback time.Duration // How far back the first sync of a module reaches, defaults to 24 hours
+	Clock    Clock         // Defaults to SystemClock
+	Sink     Sink          // Optional, receives new measures once stored (ex. ExecSink); failures are not retried
+
+	// CO2Calibration defines handling of CO2 values gathered while a device calibrates its CO2 sensor.
+	CO2Calibration CO2CalibrationPolicy
+}
+
+// Sync fetches station data of all sources and the measures of each module newer than the stored ones.
+func (s *Syncer) Sync(ctx context.Context) (*SyncResult, error) {
+	result := &SyncResult{}
+	for _, src := range s.Sources {
+		devices, _, err := src.Client.GetStationsDataContext(ctx)
+		if err != nil {
+			return result, fmt.Errorf("account %s: %w", src.Account, err)
+		}
+		for i := range devices {
+			devices[i].Account = src.Account
+		}
+		if err := s.Store.PutDevices(ctx, devices); err != nil {
+			return result, err
+		}
+		result.Devices += len(devices)
+		for i := range devices {
+			d := &devices[i]
+			moduleIDs := []string{d.ID}
+			for _, m := range d.Modules {
+				moduleIDs = append(moduleIDs, m.ID)
+			}
+			for _, moduleID := range moduleIDs {
+				n, err := s.syncModule(ctx, src, d, moduleID)
+				if err != nil {
+					return result, fmt.Errorf("account %s: %w", src.Account, err)
+				}
+				result.Measures += n
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *Syncer) syncModule(ctx context.Context, src Source, device *Device, moduleID string) (int, error) {
+	deviceID := device.ID
+	lookback := s.Lookback
+	if lookback <= 0 {
+		lookback = defaultSyncLookback
+	}
+	now := orSystemClock(s.Clock).Now().Unix()
+	// Resume after the newest stored measure however old it is, so downtime longer than the lookback leaves no gap
+	newest, err := s.Store.NewestMeasure(ctx, MeasureFilter{Account: src.Account, DeviceID: deviceID,
+		ModuleID: moduleID})
+	if err != nil {
+		return 0, err
+	}
+	begin := now - int64(lookback/time.Second)
+	if newest != nil {
+		begin = newest.Timestamp + 1
+	}
+	if begin > now {
+		return 0, nil // Up to date
+	}
+	measures, err := src.Client.GetMeasureSinceContext(ctx, deviceID, moduleID, begin)
+	if err != nil {
+		return 0, err
+	}
+	for i := range measures {
+		measures[i].Account = src.Account
+	}
+	ApplyCO2Calibration(measures, device, s.CO2Calibration)
+	if len(measures) == 0 {
+		return 0, nil
+	}
+	if err := s.Store.Write(ctx, measures); err != nil {
+		return 0, err
+	}
+	if s.Sink != nil {
+		if err := s.Sink.Write(ctx, measures); err != nil {
+			return len(measures), fmt.Errorf("sink: %w", err)
+		}
+	}
+	return len(measures), nil
+}
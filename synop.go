@@ -0,0 +1,114 @@
+package netatmo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// SYNOP formats current conditions of the device as a FM 12 SYNOP land station report, section 1 only
+// (ex. "AAXX 17124 12345 46/// /2705 10183 20121 30081 40132 52012="). StationID is the five digit station number
+// assigned by the observation network. Tendency is optional and omitted if nil (see GetPressureTendency).
+// Cloud, visibility and weather groups are reported as unavailable, as the station is automatic.
+func SYNOP(stationID string, device *Device, tendency *PressureTendency) (string, error) {
+	if len(stationID) != 5 || strings.Trim(stationID, "0123456789") != "" {
+		return "", fmt.Errorf("station id must be five digits: %q", stationID)
+	}
+	var outdoor, wind, rain *DashboardData
+	if m := findModuleByType(device, TypeOutdoor); m != nil {
+		outdoor = m.DashboardData
+	}
+	if m := findModuleByType(device, TypeWind); m != nil {
+		wind = m.DashboardData
+	}
+	if m := findModuleByType(device, TypeRain); m != nil {
+		rain = m.DashboardData
+	}
+	observed := int64(0)
+	for _, data := range []*DashboardData{device.DashboardData, outdoor} {
+		if data != nil && data.UTCTime > observed {
+			observed = data.UTCTime
+		}
+	}
+	// Observations are reported at the nearest whole hour, wind speed in m/s measured (iw=1)
+	at := time.Unix(observed, 0).UTC().Round(time.Hour)
+	groups := []string{"AAXX", at.Format("0215") + "1", stationID}
+	// iR: 1 precipitation group included, 3 no precipitation, 4 not available; ix: 6 automatic without weather
+	precipitation := ""
+	indicator := "4"
+	if rain != nil && rain.RainPerHour != nil {
+		if amount := synopPrecipitation(*rain.RainPerHour); amount != "" {
+			precipitation, indicator = "6"+amount+"5", "1"
+		} else {
+			indicator = "3"
+		}
+	}
+	groups = append(groups, indicator+"6///", "/"+synopWind(wind))
+	if outdoor != nil && outdoor.Temperature != nil {
+		groups = append(groups, "1"+synopTemperature(*outdoor.Temperature))
+		if dewPoint := dewPointOf(outdoor.Temperature, outdoor.Humidity); dewPoint != nil {
+			groups = append(groups, "2"+synopTemperature(*dewPoint))
+		}
+	}
+	if data := device.DashboardData; data != nil {
+		if data.AbsolutePressure != nil {
+			groups = append(groups, "3"+synopPressure(*data.AbsolutePressure))
+		}
+		if data.Pressure != nil {
+			groups = append(groups, "4"+synopPressure(*data.Pressure))
+		}
+	}
+	if tendency != nil {
+		groups = append(groups, fmt.Sprintf("5%d%03d", tendency.Code, int(math.Round(tendency.Amount()*10))))
+	}
+	if precipitation != "" {
+		groups = append(groups, precipitation)
+	}
+	return strings.Join(groups, " ") + "=", nil
+}
+
+// synopWind formats ddff, direction in tens of degrees and speed in m/s.
+func synopWind(data *DashboardData) string {
+	if data == nil || data.WindStrength == nil {
+		return "////"
+	}
+	speed := int(math.Round(float64(*data.WindStrength) / 3.6))
+	if speed == 0 {
+		return "0000"
+	}
+	direction := 99 // Variable
+	if data.WindAngle != nil {
+		direction = int(math.Round(float64(*data.WindAngle)/10)) % 36
+		if direction == 0 {
+			direction = 36
+		}
+	}
+	return fmt.Sprintf("%02d%02d", direction, speed%100)
+}
+
+// synopTemperature formats snTTT, sign and tenths of °C.
+func synopTemperature(v float64) string {
+	tenths := int(math.Round(v * 10))
+	if tenths < 0 {
+		return fmt.Sprintf("1%03d", -tenths)
+	}
+	return fmt.Sprintf("0%03d", tenths)
+}
+
+// synopPressure formats tenths of hPa without the thousands digit.
+func synopPressure(v float64) string {
+	return fmt.Sprintf("%04d", int(math.Round(v*10))%10000)
+}
+
+// synopPrecipitation formats RRR in mm, or returns empty string if there was no precipitation.
+func synopPrecipitation(mm float64) string {
+	switch {
+	case mm < 0.05:
+		return ""
+	case mm < 1:
+		return fmt.Sprintf("99%d", int(math.Round(mm*10)))
+	default:
+		return fmt.Sprintf("%03d", int(math.Min(math.Round(mm), 988)))
+	}
+}
@@ -0,0 +1,68 @@
+package netatmo
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SystemdNotify sends the state (ex. "READY=1", "RELOADING=1", "STATUS=synced 42 measures") to the service manager
+// with the sd_notify protocol. It reports false without error when not started by systemd with NOTIFY_SOCKET.
+func SystemdNotify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:] // Abstract namespace
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SystemdWatchdogInterval returns the watchdog timeout of the service (WatchdogSec), or zero when the watchdog is
+// disabled or meant for another process.
+func SystemdWatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// SystemdWatchdog sends "WATCHDOG=1" at half of the watchdog timeout until the context is done. It returns
+// immediately when the watchdog is disabled. Stalled processes stop pinging, so run it from the goroutine whose
+// liveness matters or make healthy() (if not nil) report it.
+func SystemdWatchdog(ctx context.Context, healthy func() bool) error {
+	interval := SystemdWatchdogInterval() / 2
+	if interval <= 0 {
+		return nil
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if healthy == nil || healthy() {
+			if _, err := SystemdNotify("WATCHDOG=1"); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
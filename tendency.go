@@ -0,0 +1,136 @@
+package netatmo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// tendencyPeriod defines period of WMO pressure tendency.
+const tendencyPeriod = 3 * time.Hour
+
+// steadyPressure defines largest change in hPa treated as steady.
+const steadyPressure = 0.1
+
+// PressureTendency defines pressure change over the last 3 hours, encoded as WMO code table 0200 characteristic
+// ("a") and amount ("ppp").
+type PressureTendency struct {
+	Code   int     // Characteristic of the tendency, 0 to 8
+	Change float64 // Pressure now minus 3 hours ago in hPa
+	Begin  int64   // Unix time of the sample used as 3 hours ago
+	End    int64   // Unix time of the latest sample
+}
+
+// Amount returns absolute pressure change in hPa rounded to tenths, as reported in the "ppp" group.
+func (t *PressureTendency) Amount() float64 {
+	return math.Round(math.Abs(t.Change)*10) / 10
+}
+
+// ComputePressureTendency computes the tendency from pressure measures ordered by timestamp covering at least the
+// last 3 hours (ex. 5 minute or 30 minute scale).
+func ComputePressureTendency(measures []Measure) (*PressureTendency, error) {
+	var points []Point
+	for _, m := range measures {
+		if m.Pressure != nil {
+			points = append(points, Point{Timestamp: m.Timestamp, Value: *m.Pressure})
+		}
+	}
+	if len(points) == 0 {
+		return nil, errors.New("no pressure measures")
+	}
+	last := points[len(points)-1]
+	period := int64(tendencyPeriod / time.Second)
+	tolerance := int64(30 * time.Minute / time.Second)
+	first, ok := nearestPoint(points, last.Timestamp-period, tolerance)
+	if !ok {
+		return nil, errors.New("no pressure measures 3 hours before the latest")
+	}
+	middle, ok := nearestPoint(points, last.Timestamp-period/2, tolerance)
+	if !ok {
+		return nil, errors.New("no pressure measures 1.5 hours before the latest")
+	}
+	return &PressureTendency{
+		Code:   tendencyCode(middle.Value-first.Value, last.Value-middle.Value),
+		Change: last.Value - first.Value,
+		Begin:  first.Timestamp,
+		End:    last.Timestamp,
+	}, nil
+}
+
+// GetPressureTendency fetches the last 3.5 hours of pressure of the base station and computes the tendency.
+func (c *Client) GetPressureTendency(deviceID string) (*PressureTendency, error) {
+	return c.GetPressureTendencyContext(context.Background(), deviceID)
+}
+
+// GetPressureTendencyContext is GetPressureTendency with a context canceling the requests.
+func (c *Client) GetPressureTendencyContext(ctx context.Context, deviceID string) (*PressureTendency, error) {
+	since := c.now().Add(-tendencyPeriod - 30*time.Minute).Unix()
+	measures, err := c.getMeasurePages(ctx, deviceID, deviceID, since, 0,
+		[]MeasureOption{WithScale(ScaleMax), WithTypes("Pressure")})
+	if err != nil {
+		return nil, err
+	}
+	return ComputePressureTendency(measures)
+}
+
+// tendencyCode classifies changes over the first and second half of the period.
+func tendencyCode(first, second float64) int {
+	total := first + second
+	firstSign, secondSign := pressureSign(first), pressureSign(second)
+	switch {
+	case math.Abs(total) < steadyPressure:
+		switch {
+		case firstSign > 0 && secondSign < 0:
+			return 0 // Increasing, then decreasing; same as before
+		case firstSign < 0 && secondSign > 0:
+			return 5 // Decreasing, then increasing; same as before
+		default:
+			return 4 // Steady
+		}
+	case total > 0:
+		switch {
+		case firstSign > 0 && secondSign < 0:
+			return 0 // Increasing, then decreasing; higher than before
+		case firstSign > 0 && secondSign == 0, firstSign > 0 && second < first-steadyPressure:
+			return 1 // Increasing, then steady or increasing more slowly
+		case firstSign <= 0, second > first+steadyPressure:
+			return 3 // Decreasing or steady then increasing, or increasing more rapidly
+		default:
+			return 2 // Increasing steadily
+		}
+	default:
+		switch {
+		case firstSign < 0 && secondSign > 0:
+			return 5 // Decreasing, then increasing; lower than before
+		case firstSign < 0 && secondSign == 0, firstSign < 0 && second > first+steadyPressure:
+			return 6 // Decreasing, then steady or decreasing more slowly
+		case firstSign >= 0, second < first-steadyPressure:
+			return 8 // Steady or increasing then decreasing, or decreasing more rapidly
+		default:
+			return 7 // Decreasing steadily
+		}
+	}
+}
+
+func pressureSign(change float64) int {
+	switch {
+	case change >= steadyPressure:
+		return 1
+	case change <= -steadyPressure:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// nearestPoint returns the point nearest to the timestamp within the tolerance in seconds.
+func nearestPoint(points []Point, timestamp, tolerance int64) (Point, bool) {
+	best, found := Point{}, false
+	for _, p := range points {
+		if d := abs64(p.Timestamp - timestamp); d <= tolerance && (!found || d < abs64(best.Timestamp-timestamp)) {
+			best, found = p, true
+		}
+	}
+	return best, found
+}
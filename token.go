@@ -0,0 +1,168 @@
+package netatmo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenStore persists the OAuth2 token of a client across restarts (see WithTokenStore).
+type TokenStore interface {
+	// Load returns the saved token, or nil without error if none was saved.
+	Load(ctx context.Context) (*oauth2.Token, error)
+	// Save replaces the saved token.
+	Save(ctx context.Context, token *oauth2.Token) error
+}
+
+// FileTokenStore saves the token as JSON to a file readable by the owner only.
+type FileTokenStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// Load reads the token file, returning nil if it does not exist.
+func (s *FileTokenStore) Load(_ context.Context) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var token oauth2.Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Save writes the token to a temporary file renamed over the token file, so readers never see a partial token.
+func (s *FileTokenStore) Save(_ context.Context, token *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0600)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.Path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// Token returns a copy of the token held by the client (access token, refresh token and expiry), refreshing it first
+// if expired, ex. to hand it to another process (see NewClientWithToken).
+func (c *Client) Token() (*oauth2.Token, error) {
+	t, ok := c.client.Transport.(*oauth2.Transport)
+	if !ok {
+		return nil, errors.New("client has no token source")
+	}
+	token, err := t.Source.Token()
+	if err != nil {
+		return nil, err
+	}
+	copied := *token
+	return &copied, nil
+}
+
+// cachedToken returns a copy of the token held by the client without refreshing it, nil if none.
+func (c *Client) cachedToken() *oauth2.Token {
+	t, ok := c.client.Transport.(*oauth2.Transport)
+	if !ok {
+		return nil
+	}
+	source, ok := t.Source.(*revocableTokenSource)
+	if !ok {
+		return nil
+	}
+	token := source.current()
+	if token == nil {
+		return nil
+	}
+	copied := *token
+	return &copied
+}
+
+// WithTokenStore loads the token of the client from the store, if saved, instead of authenticating with the
+// credentials passed to the constructor, and saves tokens obtained or refreshed afterwards. If saving a refreshed
+// token fails, the API call fails and saving is retried on the next call.
+func WithTokenStore(store TokenStore) Option {
+	return func(o *clientOptions) {
+		o.tokenStore = store
+	}
+}
+
+// WithTokenCallback calls the function with each token obtained or refreshed by the client, ex. to persist the
+// rotated refresh token elsewhere than a token store. Called after saving to the token store, if any.
+func WithTokenCallback(fn func(token *oauth2.Token)) Option {
+	return func(o *clientOptions) {
+		o.tokenHooks = append(o.tokenHooks, fn)
+	}
+}
+
+// storedToken returns the token saved in the token store of the options, nil if none.
+func (o *clientOptions) storedToken(ctx context.Context) (*oauth2.Token, error) {
+	if o.tokenStore == nil {
+		return nil, nil
+	}
+	return o.tokenStore.Load(ctx)
+}
+
+// tokenChanged passes a new token to the token store and hooks of the options.
+func (o *clientOptions) tokenChanged(ctx context.Context, token *oauth2.Token) error {
+	if o.tokenStore != nil {
+		if err := o.tokenStore.Save(ctx, token); err != nil {
+			return err
+		}
+	}
+	for _, hook := range o.tokenHooks {
+		hook(token)
+	}
+	return nil
+}
+
+// notifyingTokenSource reports tokens different from the last reported one.
+type notifyingTokenSource struct {
+	source oauth2.TokenSource
+	notify func(token *oauth2.Token) error
+	mu     sync.Mutex
+	last   *oauth2.Token
+}
+
+func (s *notifyingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.last != nil && s.last.AccessToken == token.AccessToken && s.last.RefreshToken == token.RefreshToken {
+		return token, nil
+	}
+	if err := s.notify(token); err != nil {
+		return nil, err
+	}
+	s.last = token
+	return token, nil
+}
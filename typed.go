@@ -0,0 +1,97 @@
+package netatmo
+
+// MetricType defines a measurement type usable as type argument of Value and SeriesOf, checked at compile time
+// instead of measurement names.
+type MetricType interface {
+	MetricName() string // Measurement type name of getmeasure (ex. Temperature, min_temp)
+}
+
+// Measurement types. Aggregates (Min*, Max* and SumRain) are available with scales other than ScaleMax.
+type (
+	Temperature    struct{} // °C
+	CO2            struct{} // ppm
+	Humidity       struct{} // %
+	Pressure       struct{} // mbar
+	Noise          struct{} // dB
+	WindStrength   struct{} // km/h
+	WindAngle      struct{} // degrees
+	GustStrength   struct{} // km/h
+	GustAngle      struct{} // degrees
+	MinTemperature struct{} // °C
+	MaxTemperature struct{} // °C
+	MinHumidity    struct{} // %
+	MaxHumidity    struct{} // %
+	MinPressure    struct{} // mbar
+	MaxPressure    struct{} // mbar
+	MinNoise       struct{} // dB
+	MaxNoise       struct{} // dB
+	SumRain        struct{} // mm
+)
+
+// MetricName returns "Temperature".
+func (Temperature) MetricName() string { return "Temperature" }
+
+// MetricName returns "CO2".
+func (CO2) MetricName() string { return "CO2" }
+
+// MetricName returns "Humidity".
+func (Humidity) MetricName() string { return "Humidity" }
+
+// MetricName returns "Pressure".
+func (Pressure) MetricName() string { return "Pressure" }
+
+// MetricName returns "Noise".
+func (Noise) MetricName() string { return "Noise" }
+
+// MetricName returns "WindStrength".
+func (WindStrength) MetricName() string { return "WindStrength" }
+
+// MetricName returns "WindAngle".
+func (WindAngle) MetricName() string { return "WindAngle" }
+
+// MetricName returns "GustStrength".
+func (GustStrength) MetricName() string { return "GustStrength" }
+
+// MetricName returns "GustAngle".
+func (GustAngle) MetricName() string { return "GustAngle" }
+
+// MetricName returns "min_temp".
+func (MinTemperature) MetricName() string { return "min_temp" }
+
+// MetricName returns "max_temp".
+func (MaxTemperature) MetricName() string { return "max_temp" }
+
+// MetricName returns "min_hum".
+func (MinHumidity) MetricName() string { return "min_hum" }
+
+// MetricName returns "max_hum".
+func (MaxHumidity) MetricName() string { return "max_hum" }
+
+// MetricName returns "min_pressure".
+func (MinPressure) MetricName() string { return "min_pressure" }
+
+// MetricName returns "max_pressure".
+func (MaxPressure) MetricName() string { return "max_pressure" }
+
+// MetricName returns "min_noise".
+func (MinNoise) MetricName() string { return "min_noise" }
+
+// MetricName returns "max_noise".
+func (MaxNoise) MetricName() string { return "max_noise" }
+
+// MetricName returns "sum_rain".
+func (SumRain) MetricName() string { return "sum_rain" }
+
+// Value returns the measurement of the type (ex. netatmo.Value[netatmo.Temperature](m)). The second result is false
+// if the value is null.
+func Value[T MetricType](m Measure) (float64, bool) {
+	var t T
+	return m.Value(t.MetricName())
+}
+
+// SeriesOf extracts the measurement of the type of the measures as points, skipping measures without it
+// (ex. netatmo.SeriesOf[netatmo.CO2](measures)).
+func SeriesOf[T MetricType](measures []Measure) []Point {
+	var t T
+	return PointsOf(measures, t.MetricName())
+}
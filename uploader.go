@@ -0,0 +1,139 @@
+package netatmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Uploader uploads observations to a citizen weather network.
+type Uploader interface {
+	Upload(ctx context.Context, o Observation) error
+}
+
+// UploaderFunc adapts an ordinary function to the Uploader interface.
+type UploaderFunc func(ctx context.Context, o Observation) error
+
+// Upload calls f(ctx, o).
+func (f UploaderFunc) Upload(ctx context.Context, o Observation) error {
+	return f(ctx, o)
+}
+
+// UploadAll uploads the observation with every uploader, returning the first error after trying all of them.
+func UploadAll(ctx context.Context, uploaders []Uploader, o Observation) error {
+	var first error
+	for _, u := range uploaders {
+		if err := u.Upload(ctx, o); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// uploadField maps an observation value to a query parameter of an upload API.
+type uploadField struct {
+	key       string
+	value     func(o *Observation) *float64
+	convert   func(float64) float64 // Optional unit conversion
+	precision int
+}
+
+// imperialFields defines the mapping of the Weather Underground protocol, shared by compatible networks.
+var imperialFields = []uploadField{
+	{"tempf", func(o *Observation) *float64 { return o.Temperature }, celsiusToFahrenheit, 1},
+	{"humidity", func(o *Observation) *float64 { return o.Humidity }, nil, 0},
+	{"dewptf", func(o *Observation) *float64 { return o.DewPoint }, celsiusToFahrenheit, 1},
+	{"baromin", func(o *Observation) *float64 { return o.Pressure }, hPaToInHg, 3},
+	{"windspeedmph", func(o *Observation) *float64 { return o.WindSpeed }, kmhToMph, 1},
+	{"winddir", func(o *Observation) *float64 { return o.WindAngle }, nil, 0},
+	{"windgustmph", func(o *Observation) *float64 { return o.GustSpeed }, kmhToMph, 1},
+	{"windgustdir", func(o *Observation) *float64 { return o.GustAngle }, nil, 0},
+	{"rainin", func(o *Observation) *float64 { return o.RainLastHour }, mmToInch, 2},
+	{"dailyrainin", func(o *Observation) *float64 { return o.RainToday }, mmToInch, 2},
+}
+
+// encodeFields sets non-null values of the observation mapped by the fields.
+func encodeFields(query url.Values, o *Observation, fields []uploadField) {
+	for _, f := range fields {
+		v := f.value(o)
+		if v == nil {
+			continue
+		}
+		value := *v
+		if f.convert != nil {
+			value = f.convert(value)
+		}
+		query.Set(f.key, strconv.FormatFloat(value, 'f', f.precision, 64))
+	}
+}
+
+// uploadTracker remembers the last uploaded observation time of each device to skip unchanged observations.
+type uploadTracker struct {
+	mu   sync.Mutex
+	last map[string]int64
+}
+
+// isNew reports whether the observation is newer than the last uploaded one of the device.
+func (t *uploadTracker) isNew(o *Observation) bool {
+	return t.isDue(o, 0)
+}
+
+// isDue reports whether the observation is newer than the last uploaded one of the device by at least the interval.
+func (t *uploadTracker) isDue(o *Observation, interval time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	last := t.last[o.DeviceID]
+	return o.Time > last && o.Time-last >= int64(interval/time.Second)
+}
+
+// uploaded records the observation as uploaded.
+func (t *uploadTracker) uploaded(o *Observation) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.last == nil {
+		t.last = make(map[string]int64)
+	}
+	t.last[o.DeviceID] = o.Time
+}
+
+// uploadGet sends the query with GET and returns the response body, failing on non-2xx status. Errors report the
+// masked URL instead of the request URL, which carries the station credentials.
+func uploadGet(ctx context.Context, client *http.Client, endpoint string, query url.Values, masked string) (string,
+	error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest(http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", maskURLError(err, masked)
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", maskURLError(err, masked)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("%s %s: %s: %s", http.MethodGet, masked, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return string(body), nil
+}
+
+// maskURLError replaces the URL of a *url.Error by the masked URL.
+func maskURLError(err error, masked string) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return &url.Error{Op: urlErr.Op, URL: masked, Err: urlErr.Err}
+	}
+	return err
+}
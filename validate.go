@@ -0,0 +1,147 @@
+package netatmo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// defaultValidationWindow defines number of observations used to estimate bias.
+const defaultValidationWindow = 24
+
+// Alert kinds reported by CrossValidator.
+const (
+	AlertKindBias       = "bias"
+	AlertKindDivergence = "divergence"
+)
+
+// ValidationThreshold defines tolerated differences between own readings and the neighborhood.
+type ValidationThreshold struct {
+	Bias       float64 // Tolerated mean difference over the window
+	Divergence float64 // Tolerated sudden difference from the usual bias
+}
+
+// DefaultValidationThresholds defines thresholds used by CrossValidator for measurements without explicit ones.
+var DefaultValidationThresholds = map[string]ValidationThreshold{
+	"Temperature": {Bias: 2, Divergence: 4},
+	"Humidity":    {Bias: 10, Divergence: 20},
+	"Pressure":    {Bias: 3, Divergence: 5},
+}
+
+// CrossValidator compares own readings with the neighborhood aggregate of public stations and alerts on systematic
+// bias (ex. sensor placed in the sun) or sudden divergence (ex. failing sensor).
+type CrossValidator struct {
+	Notifier   Notifier                       // Optional, receives the alerts
+	Window     int                            // Number of observations to estimate bias, defaults to 24
+	MinSamples int                            // Observations required before alerting, defaults to half the window
+	Thresholds map[string]ValidationThreshold // Defaults to DefaultValidationThresholds
+	history    map[string][]float64           // Differences keyed by module and measurement
+}
+
+// Validate fetches the area reading around the device location within the radius and observes the device and its
+// outdoor module against it.
+func (v *CrossValidator) Validate(ctx context.Context, client *Client, device *Device, radiusKm float64) ([]Alert, error) {
+	box := BoundingBoxAround(device.Place.Latitude(), device.Place.Longitude(), radiusKm)
+	area, err := client.GetAreaReadingContext(ctx, box)
+	if err != nil {
+		return nil, err
+	}
+	now := ClockFromContext(ctx).Now()
+	var alerts []Alert
+	// Public data temperature and humidity come from outdoor modules, pressure from base stations
+	if outdoor := findModuleByType(device, TypeOutdoor); outdoor != nil && outdoor.DashboardData != nil {
+		alerts = append(alerts, v.Observe(device.ID, outdoor.ID, outdoor.DashboardData, area, now,
+			"Temperature", "Humidity")...)
+	}
+	if device.DashboardData != nil {
+		alerts = append(alerts, v.Observe(device.ID, device.ID, device.DashboardData, area, now, "Pressure")...)
+	}
+	return alerts, notifyAll(ctx, v.Notifier, alerts)
+}
+
+// Observe records differences between own readings of the module and the area for the measurements (Temperature,
+// Humidity or Pressure) and returns alerts. It does not notify.
+func (v *CrossValidator) Observe(deviceID, moduleID string, own *DashboardData, area *AreaReading, now time.Time,
+	metrics ...string) []Alert {
+	if v.history == nil {
+		v.history = make(map[string][]float64)
+	}
+	window := v.Window
+	if window <= 0 {
+		window = defaultValidationWindow
+	}
+	minSamples := v.MinSamples
+	if minSamples <= 0 {
+		minSamples = (window + 1) / 2
+	}
+	var alerts []Alert
+	for _, metric := range metrics {
+		ownValue, ok := own.Value(metric)
+		neighborhood := area.value(metric)
+		threshold, hasThreshold := v.threshold(metric)
+		if !ok || neighborhood == nil || !hasThreshold {
+			continue
+		}
+		diff := ownValue - neighborhood.Median
+		key := moduleID + "\x00" + metric
+		history := v.history[key]
+		if len(history) >= minSamples {
+			bias := mean(history)
+			if math.Abs(diff-bias) > threshold.Divergence {
+				alerts = append(alerts, Alert{Time: now.Unix(), Severity: SeverityWarning, Kind: AlertKindDivergence,
+					DeviceID: deviceID, ModuleID: moduleID, Metric: metric, Value: diff - bias,
+					Message: fmt.Sprintf("%s suddenly diverges from %d nearby stations by %.1f (usual bias %.1f)",
+						metric, neighborhood.Count, diff-bias, bias)})
+			}
+		}
+		history = append(history, diff)
+		if len(history) > window {
+			history = history[len(history)-window:]
+		}
+		v.history[key] = history
+		if len(history) >= minSamples {
+			if bias := mean(history); math.Abs(bias) > threshold.Bias {
+				alerts = append(alerts, Alert{Time: now.Unix(), Severity: SeverityInfo, Kind: AlertKindBias,
+					DeviceID: deviceID, ModuleID: moduleID, Metric: metric, Value: bias,
+					Message: fmt.Sprintf("%s is systematically off from nearby stations by %.1f over %d observations",
+						metric, bias, len(history))})
+			}
+		}
+	}
+	return alerts
+}
+
+func (v *CrossValidator) threshold(metric string) (ValidationThreshold, bool) {
+	thresholds := v.Thresholds
+	if thresholds == nil {
+		thresholds = DefaultValidationThresholds
+	}
+	t, ok := thresholds[metric]
+	return t, ok
+}
+
+// value returns the aggregate of the measurement name, or nil if unavailable.
+func (r *AreaReading) value(metric string) *AreaValue {
+	switch metric {
+	case "Temperature":
+		return r.Temperature
+	case "Humidity":
+		return r.Humidity
+	case "Pressure":
+		return r.Pressure
+	default:
+		return nil
+	}
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,146 @@
+package netatmo
+
+import (
+	"fmt"
+	"time"
+)
+
+// Alert kinds reported by WeatherWarner.
+const (
+	AlertKindFrost = "frost"
+	AlertKindHeat  = "heat"
+)
+
+// WarningThresholds defines conditions of frost and heat warnings.
+type WarningThresholds struct {
+	FrostTemperature float64       // °C at or below which frost forms
+	FrostMargin      float64       // °C above FrostTemperature still at risk when the dew point is below freezing
+	HeatTemperature  float64       // °C at or above which heat is warned
+	HeatHumidex      float64       // Humidex at or above which heat is warned
+	LeadTime         time.Duration // How far the recent trend is extrapolated
+	TrendWindow      time.Duration // Period of samples the trend is computed from
+}
+
+// DefaultWarningThresholds defines thresholds used by WeatherWarner if none are specified.
+var DefaultWarningThresholds = WarningThresholds{
+	FrostTemperature: 0,
+	FrostMargin:      2,
+	HeatTemperature:  35,
+	HeatHumidex:      40,
+	LeadTime:         3 * time.Hour,
+	TrendWindow:      3 * time.Hour,
+}
+
+// Projected extrapolates the temperature in °C with the trend in °C per hour over the lead time.
+func (t WarningThresholds) Projected(temperature, trendPerHour float64) float64 {
+	return temperature + trendPerHour*t.LeadTime.Hours()
+}
+
+// FrostRisk reports whether frost is expected within the lead time from temperature and dew point in °C and the
+// temperature trend in °C per hour. Frost forms when the air cools to freezing, or nearly so under a clear sky when
+// the dew point is below freezing.
+func (t WarningThresholds) FrostRisk(temperature, dewPoint, trendPerHour float64) bool {
+	projected := t.Projected(temperature, trendPerHour)
+	if temperature < projected {
+		projected = temperature
+	}
+	if projected <= t.FrostTemperature {
+		return true
+	}
+	return dewPoint <= t.FrostTemperature && projected <= t.FrostTemperature+t.FrostMargin
+}
+
+// HeatRisk reports whether heat is expected within the lead time from temperature in °C, relative humidity in % and
+// the temperature trend in °C per hour.
+func (t WarningThresholds) HeatRisk(temperature, humidity, trendPerHour float64) bool {
+	projected := t.Projected(temperature, trendPerHour)
+	if temperature > projected {
+		projected = temperature
+	}
+	return projected >= t.HeatTemperature || Humidex(temperature, humidity) >= t.HeatHumidex
+}
+
+type temperatureSample struct {
+	time  int64
+	value float64
+}
+
+// WeatherWarner watches outdoor modules for frost and heat. Alerts are reported once when a risk begins.
+type WeatherWarner struct {
+	Thresholds *WarningThresholds // Defaults to DefaultWarningThresholds
+	history    map[string][]temperatureSample
+	active     map[string]bool // Keyed by module and alert kind
+}
+
+// Observe checks outdoor modules of the devices and returns alerts of newly detected risks.
+func (w *WeatherWarner) Observe(devices []Device, now time.Time) []Alert {
+	if w.history == nil {
+		w.history = make(map[string][]temperatureSample)
+		w.active = make(map[string]bool)
+	}
+	thresholds := DefaultWarningThresholds
+	if w.Thresholds != nil {
+		thresholds = *w.Thresholds
+	}
+	var alerts []Alert
+	for _, d := range devices {
+		outdoor := findModuleByType(&d, TypeOutdoor)
+		if outdoor == nil || outdoor.DashboardData == nil {
+			continue
+		}
+		data := outdoor.DashboardData
+		if data.Temperature == nil || data.Humidity == nil || *data.Humidity <= 0 {
+			continue
+		}
+		temperature, humidity := *data.Temperature, float64(*data.Humidity)
+		trend := w.trend(outdoor.ID, temperatureSample{time: data.UTCTime, value: temperature}, thresholds.TrendWindow)
+		dewPoint := DewPoint(temperature, humidity)
+		if w.transition(outdoor.ID, AlertKindFrost, thresholds.FrostRisk(temperature, dewPoint, trend)) {
+			alerts = append(alerts, warningAlert(now, d.ID, outdoor.ID, AlertKindFrost, temperature,
+				temperature <= thresholds.FrostTemperature,
+				fmt.Sprintf("Frost risk: %.1f °C, dew point %.1f °C, trend %+.1f °C/h", temperature, dewPoint, trend)))
+		}
+		if w.transition(outdoor.ID, AlertKindHeat, thresholds.HeatRisk(temperature, humidity, trend)) {
+			alerts = append(alerts, warningAlert(now, d.ID, outdoor.ID, AlertKindHeat, temperature,
+				temperature >= thresholds.HeatTemperature,
+				fmt.Sprintf("Heat warning: %.1f °C, humidex %.1f, trend %+.1f °C/h", temperature,
+					Humidex(temperature, humidity), trend)))
+		}
+	}
+	return alerts
+}
+
+// trend records the sample and returns temperature change in °C per hour over the window.
+func (w *WeatherWarner) trend(moduleID string, sample temperatureSample, window time.Duration) float64 {
+	history := w.history[moduleID]
+	if len(history) == 0 || history[len(history)-1].time != sample.time {
+		history = append(history, sample)
+	}
+	cutoff := sample.time - int64(window/time.Second)
+	for len(history) > 1 && history[0].time < cutoff {
+		history = history[1:]
+	}
+	w.history[moduleID] = history
+	first := history[0]
+	if sample.time <= first.time {
+		return 0
+	}
+	return (sample.value - first.value) / (float64(sample.time-first.time) / 3600)
+}
+
+// transition records the risk state and reports whether the risk just began.
+func (w *WeatherWarner) transition(moduleID, kind string, risk bool) bool {
+	key := moduleID + "\x00" + kind
+	began := risk && !w.active[key]
+	w.active[key] = risk
+	return began
+}
+
+func warningAlert(now time.Time, deviceID, moduleID, kind string, value float64, critical bool, message string) Alert {
+	severity := SeverityWarning
+	if critical {
+		severity = SeverityCritical
+	}
+	return Alert{Time: now.Unix(), Severity: severity, Kind: kind, DeviceID: deviceID, ModuleID: moduleID,
+		Metric: "Temperature", Value: value, Message: message}
+}
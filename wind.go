@@ -0,0 +1,128 @@
+package netatmo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxWindRunStep defines longest interval between samples integrated by WindRun; longer gaps are ignored.
+const maxWindRunStep = 15 * time.Minute
+
+var compassPoints = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW",
+	"NW", "NNW"}
+
+// Wind defines current data of an anemometer module.
+type Wind struct {
+	DeviceID        string
+	ModuleID        string
+	Timestamp       int64  // Time of the latest sample
+	Strength        *int   // Nullable, km/h
+	Angle           *int   // Nullable, degrees clockwise from north the wind blows from
+	GustStrength    *int   // Nullable, km/h
+	GustAngle       *int   // Nullable, degrees
+	MaxStrength     *int   // Nullable, today's maximum wind strength in km/h
+	MaxStrengthTime *int64 // Nullable
+}
+
+// Direction returns 16-point compass direction of the wind (ex. NNE), or empty string if unknown.
+func (w *Wind) Direction() string {
+	if w.Angle == nil {
+		return ""
+	}
+	return CompassDirection(*w.Angle)
+}
+
+// CompassDirection converts angle in degrees to 16-point compass direction (ex. 30 -> NNE).
+func CompassDirection(angle int) string {
+	a := ((angle % 360) + 360) % 360
+	return compassPoints[int((float64(a)+11.25)/22.5)%16]
+}
+
+// Gust defines a gust of wind.
+type Gust struct {
+	Timestamp int64
+	Strength  int  // km/h
+	Angle     *int // Nullable, degrees
+}
+
+// GetWind gathers current data of the anemometer module attached to the device.
+func (c *Client) GetWind(deviceID string) (*Wind, error) {
+	return c.GetWindContext(context.Background(), deviceID)
+}
+
+// GetWindContext is GetWind with a context canceling the request.
+func (c *Client) GetWindContext(ctx context.Context, deviceID string) (*Wind, error) {
+	device, err := c.getDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	module := device.FindModule("Wind")
+	if module == nil {
+		return nil, fmt.Errorf("no anemometer on device %s", deviceID)
+	}
+	wind := &Wind{DeviceID: deviceID, ModuleID: module.ID}
+	if data := module.DashboardData; data != nil {
+		wind.Timestamp = data.UTCTime
+		wind.Strength = data.WindStrength
+		wind.Angle = data.WindAngle
+		wind.GustStrength = data.GustStrength
+		wind.GustAngle = data.GustAngle
+		wind.MaxStrength = data.MaxWindStrength
+		wind.MaxStrengthTime = data.MaxWindStrengthTime
+	}
+	return wind, nil
+}
+
+// MaxGust gathers the strongest gust between the unix times from the history of the anemometer module. It returns
+// nil if no gust was recorded.
+func (c *Client) MaxGust(deviceID, moduleID string, from, to int64) (*Gust, error) {
+	return c.MaxGustContext(context.Background(), deviceID, moduleID, from, to)
+}
+
+// MaxGustContext is MaxGust with a context canceling the requests.
+func (c *Client) MaxGustContext(ctx context.Context, deviceID, moduleID string, from, to int64) (*Gust, error) {
+	measures, err := c.getMeasurePages(ctx, deviceID, moduleID, from, to,
+		[]MeasureOption{WithTypes("GustStrength", "GustAngle")})
+	if err != nil {
+		return nil, err
+	}
+	var gust *Gust
+	for _, m := range measures {
+		if m.GustStrength != nil && (gust == nil || *m.GustStrength > gust.Strength) {
+			gust = &Gust{Timestamp: m.Timestamp, Strength: *m.GustStrength, Angle: m.GustAngle}
+		}
+	}
+	return gust, nil
+}
+
+// DailyWindRun gathers the wind run in km of the day containing the specified time, in the time's location.
+func (c *Client) DailyWindRun(deviceID, moduleID string, day time.Time) (float64, error) {
+	return c.DailyWindRunContext(context.Background(), deviceID, moduleID, day)
+}
+
+// DailyWindRunContext is DailyWindRun with a context canceling the requests.
+func (c *Client) DailyWindRunContext(ctx context.Context, deviceID, moduleID string, day time.Time) (float64, error) {
+	begin := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	end := begin.AddDate(0, 0, 1)
+	measures, err := c.getMeasurePages(ctx, deviceID, moduleID, begin.Unix(), end.Unix()-1,
+		[]MeasureOption{WithTypes("WindStrength")})
+	if err != nil {
+		return 0, err
+	}
+	return WindRun(measures), nil
+}
+
+// WindRun computes the distance in km the wind travelled over the measures ordered by timestamp, integrating
+// WindStrength of each sample until the next one. Gaps longer than 15 minutes are not counted.
+func WindRun(measures []Measure) float64 {
+	run := 0.0
+	for i := 0; i+1 < len(measures); i++ {
+		step := time.Duration(measures[i+1].Timestamp-measures[i].Timestamp) * time.Second
+		if measures[i].WindStrength == nil || step <= 0 || step > maxWindRunStep {
+			continue
+		}
+		run += float64(*measures[i].WindStrength) * step.Hours()
+	}
+	return run
+}
@@ -0,0 +1,52 @@
+package netatmo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// windyURL defines Windy PWS upload endpoint, followed by the API key.
+// Reference: https://community.windy.com/topic/8168/report-your-weather-station-data-to-windy
+const windyURL = "https://stations.windy.com/pws/update/"
+
+// windyFields defines the metric mapping of Windy.
+var windyFields = []uploadField{
+	{"temp", func(o *Observation) *float64 { return o.Temperature }, nil, 1},
+	{"humidity", func(o *Observation) *float64 { return o.Humidity }, nil, 0},
+	{"dewpoint", func(o *Observation) *float64 { return o.DewPoint }, nil, 1},
+	{"mbar", func(o *Observation) *float64 { return o.Pressure }, nil, 1},
+	{"wind", func(o *Observation) *float64 { return o.WindSpeed }, kmhToMs, 1},
+	{"winddir", func(o *Observation) *float64 { return o.WindAngle }, nil, 0},
+	{"gust", func(o *Observation) *float64 { return o.GustSpeed }, kmhToMs, 1},
+	{"precip", func(o *Observation) *float64 { return o.RainLastHour }, nil, 1},
+}
+
+// WindyUploader uploads observations to a Windy.com station.
+type WindyUploader struct {
+	Key        string       // API key
+	Station    int          // Station index for keys with multiple stations
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+	tracker    uploadTracker
+}
+
+// Upload sends the observation. Observations not newer than the last uploaded one of the device are skipped.
+func (u *WindyUploader) Upload(ctx context.Context, o Observation) error {
+	if !u.tracker.isNew(&o) {
+		return nil
+	}
+	query := url.Values{}
+	encodeFields(query, &o, windyFields)
+	query.Set("station", strconv.Itoa(u.Station))
+	query.Set("ts", strconv.FormatInt(o.Time, 10))
+	masked := windyURL + "***?" + query.Encode()
+	if dryRun(ctx, "windy", "observation of station "+strconv.Itoa(u.Station), masked) {
+		return nil
+	}
+	if _, err := uploadGet(ctx, u.HTTPClient, windyURL+url.PathEscape(u.Key), query, masked); err != nil {
+		return err
+	}
+	u.tracker.uploaded(&o)
+	return nil
+}
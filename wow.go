@@ -0,0 +1,48 @@
+package netatmo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// wowURL defines Met Office WOW automatic reading endpoint, compatible with the Weather Underground protocol.
+// Reference: https://wow.metoffice.gov.uk/support/dataformats
+const wowURL = "https://wow.metoffice.gov.uk/automaticreading"
+
+// WOWUploader uploads observations to a Met Office Weather Observations Website (WOW) site.
+type WOWUploader struct {
+	SiteID     string        // Site ID
+	Key        string        // Authentication key of the site (a 6 digit PIN)
+	Interval   time.Duration // Minimum interval between readings, defaults to 5 minutes
+	HTTPClient *http.Client  // Defaults to http.DefaultClient
+	tracker    uploadTracker
+}
+
+// Upload sends the observation. Observations less than the interval newer than the last uploaded one of the device
+// are skipped.
+func (u *WOWUploader) Upload(ctx context.Context, o Observation) error {
+	interval := u.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	if !u.tracker.isDue(&o, interval) {
+		return nil
+	}
+	query := url.Values{}
+	encodeFields(query, &o, imperialFields)
+	query.Set("siteid", u.SiteID)
+	query.Set("siteAuthenticationKey", u.Key)
+	query.Set("dateutc", time.Unix(o.Time, 0).UTC().Format("2006-01-02 15:04:05"))
+	query.Set("softwaretype", "netatmo-weather-go")
+	masked := wowURL + "?" + maskQuery(query, "siteAuthenticationKey")
+	if dryRun(ctx, "wow", "observation of site "+u.SiteID, masked) {
+		return nil
+	}
+	if _, err := uploadGet(ctx, u.HTTPClient, wowURL, query, masked); err != nil {
+		return err
+	}
+	u.tracker.uploaded(&o)
+	return nil
+}
@@ -0,0 +1,72 @@
+package netatmo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Weather Underground PWS upload endpoints.
+// Reference: https://support.weather.com/s/article/PWS-Upload-Protocol
+const (
+	wundergroundURL          = "https://weatherstation.wunderground.com/weatherstation/updateweatherstation.php"
+	wundergroundRapidFireURL = "https://rtupdate.wunderground.com/weatherstation/updateweatherstation.php"
+)
+
+// wundergroundFields adds indoor values to the common imperial mapping.
+var wundergroundFields = append(append([]uploadField{}, imperialFields...),
+	uploadField{"indoortempf", func(o *Observation) *float64 { return o.IndoorTemperature }, celsiusToFahrenheit, 1},
+	uploadField{"indoorhumidity", func(o *Observation) *float64 { return o.IndoorHumidity }, nil, 0},
+)
+
+// WundergroundUploader uploads observations to a Weather Underground personal weather station.
+type WundergroundUploader struct {
+	StationID  string        // Station ID (ex. KCASANFR123)
+	Key        string        // Station key
+	RapidFire  bool          // Use the real time endpoint, uploading every call with the current time
+	Interval   time.Duration // Expected rapid fire upload interval, defaults to 10 seconds
+	HTTPClient *http.Client  // Defaults to http.DefaultClient
+	tracker    uploadTracker
+}
+
+// Upload sends the observation. Unless RapidFire is set, observations not newer than the last uploaded one of the
+// device are skipped.
+func (u *WundergroundUploader) Upload(ctx context.Context, o Observation) error {
+	if !u.RapidFire && !u.tracker.isNew(&o) {
+		return nil
+	}
+	query := url.Values{}
+	encodeFields(query, &o, wundergroundFields)
+	query.Set("ID", u.StationID)
+	query.Set("PASSWORD", u.Key)
+	query.Set("action", "updateraw")
+	query.Set("dateutc", time.Unix(o.Time, 0).UTC().Format("2006-01-02 15:04:05"))
+	endpoint := wundergroundURL
+	if u.RapidFire {
+		interval := u.Interval
+		if interval <= 0 {
+			interval = 10 * time.Second
+		}
+		endpoint = wundergroundRapidFireURL
+		query.Set("dateutc", "now")
+		query.Set("realtime", "1")
+		query.Set("rtfreq", strconv.FormatFloat(interval.Seconds(), 'f', -1, 64))
+	}
+	masked := endpoint + "?" + maskQuery(query, "PASSWORD")
+	if dryRun(ctx, "weather underground", "observation of "+u.StationID, masked) {
+		return nil
+	}
+	body, err := uploadGet(ctx, u.HTTPClient, endpoint, query, masked)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(strings.TrimSpace(body), "success") {
+		return fmt.Errorf("weather underground: %s", strings.TrimSpace(body))
+	}
+	u.tracker.uploaded(&o)
+	return nil
+}
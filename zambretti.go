@@ -0,0 +1,144 @@
+package netatmo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ZambrettiTrendThreshold defines the 3 hour pressure change in hPa beyond which pressure is rising or falling for
+// the Zambretti forecaster.
+const ZambrettiTrendThreshold = 1.6
+
+// Pressure range of the Zambretti forecaster in hPa, split into 22 bands.
+const (
+	zambrettiTop    = 1050.0
+	zambrettiBottom = 950.0
+)
+
+// Zambretti forecast letters by pressure band for each trend.
+// Reference: https://www.beteljuice.co.uk/zambretti/forecast.html
+var (
+	zambrettiRising  = []byte("ZZZYYTQMLJIGFCBBAAAAAA")
+	zambrettiSteady  = []byte("ZZZZZZXXWSPNKEBBAAAAAA")
+	zambrettiFalling = []byte("ZZZZZZZZXXVUROHDBBBAAA")
+)
+
+// zambrettiWind defines pressure adjustments of 16 wind directions from north, in percent of the pressure range,
+// for the northern hemisphere.
+var zambrettiWind = []float64{6, 5, 5, 2, -0.5, -2, -5, -8.5, -12, -10, -6, -4.5, -3, -0.5, 1.5, 3}
+
+// ZambrettiTexts defines English forecast texts by letter (A: settled fine to Z: stormy, much rain).
+var ZambrettiTexts = map[string]string{
+	"A": "Settled fine",
+	"B": "Fine weather",
+	"C": "Becoming fine",
+	"D": "Fine, becoming less settled",
+	"E": "Fine, possible showers",
+	"F": "Fairly fine, improving",
+	"G": "Fairly fine, possible showers early",
+	"H": "Fairly fine, showery later",
+	"I": "Showery early, improving",
+	"J": "Changeable, mending",
+	"K": "Fairly fine, showers likely",
+	"L": "Rather unsettled clearing later",
+	"M": "Unsettled, probably improving",
+	"N": "Showery, bright intervals",
+	"O": "Showery, becoming less settled",
+	"P": "Changeable, some rain",
+	"Q": "Unsettled, short fine intervals",
+	"R": "Unsettled, rain later",
+	"S": "Unsettled, some rain",
+	"T": "Mostly very unsettled",
+	"U": "Occasional rain, worsening",
+	"V": "Rain at times, very unsettled",
+	"W": "Rain at frequent intervals",
+	"X": "Rain, very unsettled",
+	"Y": "Stormy, may improve",
+	"Z": "Stormy, much rain",
+}
+
+// ZambrettiInput defines observations used by the Zambretti forecaster.
+type ZambrettiInput struct {
+	Pressure  float64    // Sea level pressure in hPa (ex. Pressure of the base station)
+	Change    float64    // Pressure change over the last 3 hours in hPa (see PressureTendency)
+	WindAngle *int       // Nullable, degrees the wind blows from
+	Month     time.Month // Local month, summer is April to September in the northern hemisphere
+	Southern  bool       // Southern hemisphere, reversing seasons and wind directions
+}
+
+// ZambrettiForecast defines a short local forecast for the next hours.
+type ZambrettiForecast struct {
+	Code  string // Letter from A (settled fine) to Z (stormy, much rain)
+	Trend string // Pressure trend: up, down or stable (see DescribeTrendIn)
+	Text  string // English text of the code (see ZambrettiTexts)
+}
+
+// Zambretti forecasts the weather with the Zambretti algorithm from sea level pressure, its 3 hour trend, the wind
+// direction and the season. It suits temperate latitudes; pressures outside 950 to 1050 hPa give the extreme
+// forecasts.
+func Zambretti(in ZambrettiInput) ZambrettiForecast {
+	pressure, span := in.Pressure, zambrettiTop-zambrettiBottom
+	if in.WindAngle != nil {
+		angle := *in.WindAngle
+		if in.Southern {
+			angle += 180
+		}
+		a := ((angle % 360) + 360) % 360
+		pressure += zambrettiWind[int((float64(a)+11.25)/22.5)%16] / 100 * span
+	}
+	summer := in.Month >= time.April && in.Month <= time.September
+	if in.Southern {
+		summer = !summer
+	}
+	trend, table := "stable", zambrettiSteady
+	switch {
+	case in.Change >= ZambrettiTrendThreshold:
+		trend, table = "up", zambrettiRising
+		if summer {
+			pressure += 0.07 * span
+		}
+	case in.Change <= -ZambrettiTrendThreshold:
+		trend, table = "down", zambrettiFalling
+		if !summer {
+			pressure -= 0.07 * span
+		}
+	}
+	band := int(math.Floor((pressure - zambrettiBottom) / (span / 22)))
+	band = max(0, min(band, len(table)-1))
+	code := string(table[band])
+	return ZambrettiForecast{Code: code, Trend: trend, Text: ZambrettiTexts[code]}
+}
+
+// GetZambrettiForecast forecasts the weather at the station with the current pressure and the 3 hour tendency of the
+// base station, and the wind direction of the anemometer, if any.
+func (c *Client) GetZambrettiForecast(deviceID string) (*ZambrettiForecast, error) {
+	return c.GetZambrettiForecastContext(context.Background(), deviceID)
+}
+
+// GetZambrettiForecastContext is GetZambrettiForecast with a context canceling the requests.
+func (c *Client) GetZambrettiForecastContext(ctx context.Context, deviceID string) (*ZambrettiForecast, error) {
+	device, err := c.getDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.DashboardData == nil || device.DashboardData.Pressure == nil {
+		return nil, errors.New("no pressure of the station")
+	}
+	tendency, err := c.GetPressureTendencyContext(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	in := ZambrettiInput{
+		Pressure: *device.DashboardData.Pressure,
+		Change:   tendency.Change,
+		Month:    c.now().In(stationLocation(device)).Month(),
+		Southern: device.Place.Latitude() < 0,
+	}
+	if m := findModuleByType(device, TypeWind); m != nil && m.DashboardData != nil {
+		in.WindAngle = m.DashboardData.WindAngle
+	}
+	forecast := Zambretti(in)
+	return &forecast, nil
+}
@@ -0,0 +1,45 @@
+package netatmo
+
+import (
+	"testing"
+	"time"
+)
+
+// Forecast options by pressure band of the reference implementation, as indexes of the letters A to Z.
+// Reference: https://www.beteljuice.co.uk/zambretti/forecast.html
+var (
+	zambrettiRiseOptions   = []int{25, 25, 25, 24, 24, 19, 16, 12, 11, 9, 8, 6, 5, 2, 1, 1, 0, 0, 0, 0, 0, 0}
+	zambrettiSteadyOptions = []int{25, 25, 25, 25, 25, 25, 23, 23, 22, 18, 15, 13, 10, 4, 1, 1, 0, 0, 0, 0, 0, 0}
+	zambrettiFallOptions   = []int{25, 25, 25, 25, 25, 25, 25, 25, 23, 23, 21, 20, 17, 14, 7, 3, 1, 1, 1, 0, 0, 0}
+)
+
+func TestZambrettiBands(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  float64
+		month   time.Month // Season without pressure adjustment for the trend
+		trend   string
+		options []int
+	}{
+		{name: "rising", change: 2, month: time.January, trend: "up", options: zambrettiRiseOptions},
+		{name: "steady", change: 0, month: time.January, trend: "stable", options: zambrettiSteadyOptions},
+		{name: "falling", change: -2, month: time.July, trend: "down", options: zambrettiFallOptions},
+	}
+	bandWidth := (zambrettiTop - zambrettiBottom) / 22
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for band, option := range tt.options {
+				pressure := zambrettiBottom + (float64(band)+0.5)*bandWidth
+				got := Zambretti(ZambrettiInput{Pressure: pressure, Change: tt.change, Month: tt.month})
+				want := string(rune('A' + option))
+				if got.Code != want || got.Trend != tt.trend {
+					t.Errorf("band %d (%.1f hPa): got %s %s, want %s %s", band, pressure, got.Code, got.Trend, want,
+						tt.trend)
+				}
+				if got.Text != ZambrettiTexts[want] {
+					t.Errorf("band %d: text %q, want %q", band, got.Text, ZambrettiTexts[want])
+				}
+			}
+		})
+	}
+}